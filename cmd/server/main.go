@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"domain-monitor/internal/api"
 	"domain-monitor/internal/config"
 	"domain-monitor/internal/database"
+	"domain-monitor/internal/logging"
 	"domain-monitor/internal/models"
 	"domain-monitor/internal/scheduler"
 	"domain-monitor/internal/services"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
-	"strconv"
-	"strings"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
+	_ "time/tzdata" // Time zones for images without a zoneinfo database
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // loadSettingsFromDB loads settings from database and overrides config
@@ -24,7 +36,7 @@ func loadSettingsFromDB(cfg *config.Config) {
 
 	var settings []models.Setting
 	if err := db.Find(&settings).Error; err != nil {
-		log.Printf("Warning: Failed to load settings from database: %v", err)
+		slog.Warn("Failed to load settings from database", "error", err)
 		return
 	}
 
@@ -34,93 +46,29 @@ func loadSettingsFromDB(cfg *config.Config) {
 		settingsMap[s.Key] = s.Value
 	}
 
-	// Override monitor settings
-	if val, ok := settingsMap["monitor.check_interval"]; ok && val != "" {
-		cfg.Monitor.CheckInterval = val
-	}
-	if val, ok := settingsMap["monitor.alert_days"]; ok && val != "" {
-		// Parse comma-separated days
-		days := []int{}
-		for _, d := range strings.Split(val, ",") {
-			if day, err := strconv.Atoi(strings.TrimSpace(d)); err == nil {
-				days = append(days, day)
-			}
-		}
-		if len(days) > 0 {
-			cfg.Monitor.AlertDays = days
-		}
-	}
-
-	// Override email settings
-	if val, ok := settingsMap["email.enabled"]; ok {
-		cfg.Notifications.Email.Enabled = val == "true"
-	}
-	if val, ok := settingsMap["email.smtp_host"]; ok {
-		cfg.Notifications.Email.SMTPHost = val
-	}
-	if val, ok := settingsMap["email.smtp_port"]; ok {
-		if port, err := strconv.Atoi(val); err == nil {
-			cfg.Notifications.Email.SMTPPort = port
-		}
-	}
-	if val, ok := settingsMap["email.from"]; ok {
-		cfg.Notifications.Email.From = val
-	}
-	if val, ok := settingsMap["email.password"]; ok {
-		cfg.Notifications.Email.Password = val
-	}
-	if val, ok := settingsMap["email.to"]; ok && val != "" {
-		cfg.Notifications.Email.To = strings.Split(val, ",")
-	}
-
-	// Override webhook settings
-	if val, ok := settingsMap["webhook.enabled"]; ok {
-		cfg.Notifications.Webhook.Enabled = val == "true"
-	}
-	if val, ok := settingsMap["webhook.url"]; ok {
-		cfg.Notifications.Webhook.URL = val
-	}
-
-	// Override telegram settings
-	if val, ok := settingsMap["telegram.enabled"]; ok {
-		cfg.Notifications.Telegram.Enabled = val == "true"
-	}
-	if val, ok := settingsMap["telegram.bot_token"]; ok {
-		cfg.Notifications.Telegram.BotToken = val
-	}
-	if val, ok := settingsMap["telegram.chat_id"]; ok {
-		cfg.Notifications.Telegram.ChatID = val
-	}
+	config.ApplySettings(cfg, settingsMap)
 
-	// Override dingding settings
-	if val, ok := settingsMap["dingding.enabled"]; ok {
-		cfg.Notifications.DingDing.Enabled = val == "true"
-	}
-	if val, ok := settingsMap["dingding.webhook"]; ok {
-		cfg.Notifications.DingDing.Webhook = val
-	}
-	if val, ok := settingsMap["dingding.secret"]; ok {
-		cfg.Notifications.DingDing.Secret = val
-	}
-
-	log.Println("Settings loaded from database and applied to configuration")
+	slog.Info("Settings loaded from database and applied to configuration", "count", len(settingsMap))
 }
 
 // initDefaultAdmin initializes the default admin account
 func initDefaultAdmin(authService *services.AuthService) {
 	db := database.GetDB()
+	if db == nil {
+		return
+	}
 
 	// Check if admin user already exists
 	var existingUser models.User
 	if err := db.Where("username = ?", "admin").First(&existingUser).Error; err == nil {
-		log.Println("Admin account already exists")
+		slog.Debug("Admin account already exists")
 		return
 	}
 
 	// Create default admin account (username: admin, password: admin123)
 	hashedPassword, err := authService.HashPassword("admin123")
 	if err != nil {
-		log.Printf("Failed to hash default admin password: %v", err)
+		slog.Error("Failed to hash default admin password", "error", err)
 		return
 	}
 
@@ -128,69 +76,182 @@ func initDefaultAdmin(authService *services.AuthService) {
 		Username:  "admin",
 		Password:  hashedPassword,
 		Email:     "admin@example.com",
+		Role:      models.RoleAdmin,
 		IsActive:  true,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := db.Create(&admin).Error; err != nil {
-		log.Printf("Failed to create default admin account: %v", err)
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&admin).Error
+	}); err != nil {
+		slog.Error("Failed to create default admin account", "error", err)
 		return
 	}
 
-	log.Println("Default admin account created (username: admin, password: admin123)")
+	slog.Warn("Default admin account created, change its password", "username", "admin", "password", "admin123")
+}
+
+// defaultShutdownTimeout is how long shutdown waits for requests and checks when
+// server.shutdown_timeout is not set
+const defaultShutdownTimeout = 30 * time.Second
+
+// fatal logs an error and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// jwtSecret returns the token signing key from the config, where config.ApplyEnv has
+// already applied JIANKONG_JWT_SECRET. Release mode refuses to start without a real
+// secret; debug mode falls back to a random one, which invalidates issued tokens on restart.
+func jwtSecret(cfg *config.Config) (string, error) {
+	secret := cfg.Server.JWTSecret
+	if secret != "" && secret != services.DefaultJWTSecret {
+		return secret, nil
+	}
+
+	if cfg.Server.Mode == "release" {
+		return "", fmt.Errorf("server.jwt_secret (or JIANKONG_JWT_SECRET) must be set to a non-default value in release mode")
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate JWT secret: %w", err)
+	}
+	slog.Warn("server.jwt_secret is not set, using a random secret; tokens will be invalidated on restart")
+	return hex.EncodeToString(buf), nil
 }
 
 func main() {
+	configPath := flag.String("config", "config/config.yaml", "Path to the base configuration file")
+	env := flag.String("env", os.Getenv("APP_ENV"), "Configuration profile to layer over the base file (e.g. dev/staging/prod)")
+	flag.Parse()
+
 	// Load configuration
-	cfg, err := config.LoadConfig("config/config.yaml")
+	cfg, err := config.LoadConfigForEnv(*configPath, *env)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := logging.Setup(&cfg.Log); err != nil {
+		log.Fatalf("Failed to configure logging: %v", err)
+	}
+	if *env != "" {
+		slog.Info("Using configuration profile", "env", *env, "path", config.ProfilePath(*configPath, *env))
+	}
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			fatal("Invalid timezone", err)
+		}
+		models.SetLocation(location)
+		slog.Info("Using time zone", "timezone", location.String())
+	}
 
 	// Initialize database
 	if err := database.InitDB(&cfg.Database); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		fatal("Failed to initialize database", err)
 	}
-	log.Println("Database initialized successfully")
+	slog.Info("Database initialized", "type", cfg.Database.Type)
 
 	// Load settings from database and override config
 	loadSettingsFromDB(cfg)
 
-	// Parse WHOIS timeout
-	timeout, err := time.ParseDuration(cfg.Whois.Timeout)
-	if err != nil {
-		timeout = 30 * time.Second
-	}
-
 	// Initialize services
-	whoisService := services.NewWhoisService(cfg.Whois.APIURL, timeout)
+	whoisService := services.NewWhoisService(&cfg.Whois)
 	notifyService := services.NewNotifyService(&cfg.Notifications)
-	monitorService := services.NewMonitorService(whoisService, notifyService, cfg.Monitor.AlertDays)
-	authService := services.NewAuthService()
+	monitorService := services.NewMonitorService(whoisService, notifyService, &cfg.Monitor)
+	secret, err := jwtSecret(cfg)
+	if err != nil {
+		fatal("Failed to configure authentication", err)
+	}
+	shutdownTimeout := defaultShutdownTimeout
+	if cfg.Server.ShutdownTimeout != "" {
+		timeout, err := time.ParseDuration(cfg.Server.ShutdownTimeout)
+		if err == nil && timeout <= 0 {
+			err = fmt.Errorf("must be positive")
+		}
+		if err != nil {
+			fatal("Invalid server.shutdown_timeout", err)
+		}
+		shutdownTimeout = timeout
+	}
+	refreshGrace := services.DefaultRefreshGrace
+	if cfg.Server.TokenRefreshGrace != "" {
+		grace, err := time.ParseDuration(cfg.Server.TokenRefreshGrace)
+		if err == nil && grace < 0 {
+			err = fmt.Errorf("must not be negative")
+		}
+		if err != nil {
+			fatal("Invalid server.token_refresh_grace", err)
+		}
+		refreshGrace = grace
+	}
+	authService := services.NewAuthService(secret, refreshGrace)
 
 	// Initialize default admin account
 	initDefaultAdmin(authService)
 
+	// Initialize certificate monitoring
+	var certService *services.CertService
+	if cfg.Cert.Enabled {
+		certTimeout, err := time.ParseDuration(cfg.Cert.Timeout)
+		if err != nil {
+			certTimeout = 10 * time.Second
+		}
+		certService = services.NewCertService(cfg.Cert.Port, certTimeout, cfg.Cert.Concurrency)
+	}
+
+	// Initialize DNS record monitoring
+	if cfg.DNS.Enabled {
+		dnsService, err := services.NewDNSService(&cfg.DNS)
+		if err != nil {
+			fatal("Invalid dns configuration", err)
+		}
+		monitorService.SetDNSService(dnsService)
+	}
+
+	// Initialize uptime checks
+	var uptimeService *services.UptimeService
+	if cfg.Uptime.Enabled {
+		uptimeService, err = services.NewUptimeService(&cfg.Uptime, notifyService)
+		if err != nil {
+			fatal("Invalid uptime configuration", err)
+		}
+	}
+	uptimeInterval := cfg.Uptime.Interval
+	if uptimeInterval == "" {
+		uptimeInterval = "*/5 * * * *"
+	}
+
 	// Initialize scheduler
-	sched := scheduler.NewScheduler(monitorService)
-	if err := sched.Start(cfg.Monitor.CheckInterval); err != nil {
-		log.Fatalf("Failed to start scheduler: %v", err)
+	sched := scheduler.NewScheduler(monitorService, certService, uptimeService)
+	if err := sched.Start(cfg.Monitor.CheckInterval, uptimeInterval); err != nil {
+		fatal("Failed to start scheduler", err)
+	}
+
+	// Catch up on domains that missed their scheduled check during downtime
+	if cfg.Monitor.CheckOverdueOnStartup {
+		if err := sched.RunOverdue(); err != nil {
+			slog.Error("Startup overdue check failed", "error", err)
+		}
 	}
-	defer sched.Stop()
 
 	// Setup Gin
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	r := gin.Default()
+	// Request IDs, request logging through slog and panic recovery replace gin's defaults
+	r := gin.New()
+	r.Use(api.RequestLogger())
 
 	// Enable CORS
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+api.APIKeyHeader+", "+api.RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", api.RequestIDHeader)
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
 			return
@@ -199,7 +260,7 @@ func main() {
 	})
 
 	// Setup API routes
-	handler := api.NewHandler(monitorService, whoisService, authService)
+	handler := api.NewHandler(cfg, monitorService, whoisService, authService, sched)
 	api.SetupRoutes(r, handler)
 
 	// Serve static files
@@ -212,8 +273,46 @@ func main() {
 
 	// Start server
 	addr := ":" + cfg.Server.Port
-	log.Printf("Server starting on %s", addr)
-	if err := r.Run(addr); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	server := &http.Server{Addr: addr, Handler: r}
+	go func() {
+		slog.Info("Server starting", "addr", addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to start server", err)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM, then let requests and checks finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+
+	slog.Info("Shutting down", "timeout", shutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	shutdown(shutdownCtx, server, sched, monitorService)
+}
+
+// shutdowner is a component stopped on shutdown: the HTTP server, the scheduler and the
+// monitor service
+type shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// shutdown stops the server in order: new requests are refused and running ones finish,
+// no further scheduled jobs start and the running ones finish, then the remaining
+// background checks drain. Whatever is still running when ctx is done is cancelled.
+func shutdown(ctx context.Context, server, sched, monitorService shutdowner) {
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Warn("HTTP server shutdown incomplete", "error", err)
+	}
+	if err := sched.Shutdown(ctx); err != nil {
+		slog.Warn("Scheduler shutdown incomplete", "error", err)
+	}
+	if err := monitorService.Shutdown(ctx); err != nil {
+		slog.Warn("Cancelled outstanding domain checks", "error", err)
+	}
+	if err := database.Close(); err != nil {
+		slog.Warn("Failed to close database", "error", err)
 	}
+	slog.Info("Server stopped")
 }
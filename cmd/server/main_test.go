@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/services"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJWTSecret(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		secret  string
+		want    string // "" for a generated secret
+		wantErr bool
+	}{
+		{"configured", "release", "s3cret", "s3cret", false},
+		{"missing in release", "release", "", "", true},
+		{"default in release", "release", services.DefaultJWTSecret, "", true},
+		{"missing in debug", "debug", "", "", false},
+		{"default in debug", "debug", services.DefaultJWTSecret, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Server.Mode = tt.mode
+			cfg.Server.JWTSecret = tt.secret
+
+			got, err := jwtSecret(cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("jwtSecret() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("jwtSecret: %v", err)
+			}
+			if tt.want != "" && got != tt.want {
+				t.Errorf("jwtSecret() = %q, want %q", got, tt.want)
+			}
+			if got == "" || got == services.DefaultJWTSecret {
+				t.Errorf("jwtSecret() = %q, want a usable secret", got)
+			}
+		})
+	}
+}
+
+// shutdownLog records the order in which components start and finish shutting down
+type shutdownLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *shutdownLog) add(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+// fakeComponent takes delay to shut down, or until ctx is done
+type fakeComponent struct {
+	name  string
+	delay time.Duration
+	log   *shutdownLog
+}
+
+func (f *fakeComponent) Shutdown(ctx context.Context) error {
+	f.log.add(f.name + " start")
+	defer f.log.add(f.name + " done")
+	select {
+	case <-time.After(f.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestShutdownOrder(t *testing.T) {
+	log := &shutdownLog{}
+	server := &fakeComponent{"server", 30 * time.Millisecond, log}
+	sched := &fakeComponent{"scheduler", 20 * time.Millisecond, log}
+	monitor := &fakeComponent{"monitor", 10 * time.Millisecond, log}
+
+	shutdown(context.Background(), server, sched, monitor)
+
+	// Each step waits for the previous one, however long it takes
+	want := []string{"server start", "server done", "scheduler start", "scheduler done", "monitor start", "monitor done"}
+	if !reflect.DeepEqual(log.events, want) {
+		t.Errorf("shutdown order = %v, want %v", log.events, want)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	log := &shutdownLog{}
+	server := &fakeComponent{"server", time.Hour, log}
+	sched := &fakeComponent{"scheduler", time.Hour, log}
+	monitor := &fakeComponent{"monitor", time.Hour, log}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	shutdown(ctx, server, sched, monitor)
+
+	// A step that runs out of time doesn't keep the later ones from being stopped
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v, want it bounded by the timeout", elapsed)
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("ctx.Err() = %v, want the deadline to have passed", ctx.Err())
+	}
+	want := []string{"server start", "server done", "scheduler start", "scheduler done", "monitor start", "monitor done"}
+	if !reflect.DeepEqual(log.events, want) {
+		t.Errorf("shutdown order = %v, want %v", log.events, want)
+	}
+}
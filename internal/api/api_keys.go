@@ -0,0 +1,102 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListAPIKeys returns all API keys; the keys themselves are never returned
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	db := database.GetDB()
+
+	var keys []models.APIKey
+	if err := db.Order("created_at desc").Find(&keys).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// CreateAPIKey creates an API key. The key is only included in this response.
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req struct {
+		Label string `json:"label" binding:"required"`
+		Role  string `json:"role"` // default viewer
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Label = strings.TrimSpace(req.Label)
+	if req.Label == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "label is required"})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.RoleViewer
+	}
+	if !models.ValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be admin or viewer"})
+		return
+	}
+
+	var createdBy string
+	if claims, ok := CurrentClaims(c); ok {
+		createdBy = claims.Username
+	}
+
+	key, apiKey, err := h.authService.GenerateAPIKey(req.Label, req.Role, createdBy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(apiKey).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"key":     key,
+		"api_key": apiKey,
+		"message": "Store the key now, it cannot be shown again",
+	})
+}
+
+// RevokeAPIKey deactivates an API key; the record is kept for reference
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	err = database.WithRetry(func(db *gorm.DB) error {
+		var apiKey models.APIKey
+		if err := db.First(&apiKey, id).Error; err != nil {
+			return err
+		}
+		return db.Model(&apiKey).Update("is_active", false).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
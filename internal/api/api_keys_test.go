@@ -0,0 +1,90 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// createAPIKey creates an API key through the API and returns it with its record
+func (s *testServer) createAPIKey(token, label, role string) (string, models.APIKey) {
+	s.t.Helper()
+	w := s.do(http.MethodPost, "/api/v1/api-keys", token, map[string]string{"label": label, "role": role})
+	expectStatus(s.t, w, http.StatusCreated)
+	created := decode[struct {
+		Key    string        `json:"key"`
+		APIKey models.APIKey `json:"api_key"`
+	}](s.t, w)
+	return created.Key, created.APIKey
+}
+
+// doWithAPIKey sends a request authenticated with the API key
+func (s *testServer) doWithAPIKey(method, path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set(APIKeyHeader, key)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+
+	key, apiKey := s.createAPIKey(token, "grafana", "")
+	if apiKey.Role != models.RoleViewer || !strings.HasPrefix(key, apiKey.Prefix) {
+		t.Errorf("api key = %+v, want a viewer key recognizable by prefix", apiKey)
+	}
+
+	expectStatus(t, s.doWithAPIKey(http.MethodGet, "/api/v1/domains", key), http.StatusOK)
+	// A viewer key is read-only
+	expectStatus(t, s.doWithAPIKey(http.MethodDelete, fmt.Sprintf("/api/v1/domains/%d", domain.ID), key), http.StatusForbidden)
+
+	var stored models.APIKey
+	database.GetDB().First(&stored, apiKey.ID)
+	if stored.LastUsed == nil {
+		t.Error("last_used not recorded")
+	}
+	if stored.KeyHash == key || strings.Contains(stored.KeyHash, key) {
+		t.Error("key stored in plain text")
+	}
+
+	// The key is not shown again
+	w := s.do(http.MethodGet, "/api/v1/api-keys", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if strings.Contains(w.Body.String(), key) {
+		t.Error("key listed after creation")
+	}
+}
+
+func TestAPIKeyAdminRole(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+
+	key, _ := s.createAPIKey(token, "ci", models.RoleAdmin)
+	expectStatus(t, s.doWithAPIKey(http.MethodDelete, fmt.Sprintf("/api/v1/domains/%d", domain.ID), key), http.StatusOK)
+}
+
+func TestAPIKeyRevoked(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	key, apiKey := s.createAPIKey(token, "grafana", "")
+	expectStatus(t, s.doWithAPIKey(http.MethodGet, "/api/v1/domains", key), http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodDelete, fmt.Sprintf("/api/v1/api-keys/%d", apiKey.ID), token, nil), http.StatusOK)
+	expectStatus(t, s.doWithAPIKey(http.MethodGet, "/api/v1/domains", key), http.StatusUnauthorized)
+
+	expectStatus(t, s.do(http.MethodDelete, "/api/v1/api-keys/999", token, nil), http.StatusNotFound)
+}
+
+func TestAPIKeyInvalid(t *testing.T) {
+	s := newTestServer(t)
+	expectStatus(t, s.doWithAPIKey(http.MethodGet, "/api/v1/domains", "not-a-key"), http.StatusUnauthorized)
+}
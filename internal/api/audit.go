@@ -0,0 +1,141 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Default and maximum page size of the audit log
+const (
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+// ListAudit returns the audit log, newest first. It can be filtered by actor, action and
+// target, and is paginated with page and page_size.
+func (h *Handler) ListAudit(c *gin.Context) {
+	query := database.GetDB().Model(&models.AuditLog{})
+	for _, field := range []string{"actor", "action", "target"} {
+		if value := c.Query(field); value != "" {
+			query = query.Where(field+" = ?", value)
+		}
+	}
+
+	page, pageSize, ok := queryPage(c, defaultAuditPageSize, maxAuditPageSize)
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	entries := []models.AuditLog{}
+	if err := query.Order("created_at desc").Order("id desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&entries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries":   entries,
+		"total":     total,
+		"page":      page,
+		"page_size": pageSize,
+	})
+}
+
+// audit records a change made by the authenticated user. before and after summarize the
+// changed values and are stored as JSON; nil leaves them empty. Failures are only logged
+// since the change itself has already been made.
+func (h *Handler) audit(c *gin.Context, action, target string, before, after any) {
+	entry := models.AuditLog{
+		Action:    action,
+		Target:    target,
+		Before:    auditJSON(before),
+		After:     auditJSON(after),
+		CreatedAt: time.Now(),
+	}
+	if claims, ok := CurrentClaims(c); ok {
+		entry.ActorID = claims.UserID
+		entry.Actor = claims.Username
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&entry).Error
+	}); err != nil {
+		slog.Error("Failed to record audit log entry", "action", action, "target", target, "actor", entry.Actor, "error", err)
+	}
+}
+
+// auditJSON encodes an audit summary, empty for nil
+func auditJSON(v any) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+// changedValues returns the values of the keys of after that differ from before. A key
+// missing from before compares as the zero value.
+func changedValues[V comparable](before, after map[string]V) (map[string]V, map[string]V) {
+	oldValues, newValues := make(map[string]V), make(map[string]V)
+	for key, value := range after {
+		if before[key] != value {
+			oldValues[key], newValues[key] = before[key], value
+		}
+	}
+	return oldValues, newValues
+}
+
+// settingsChanges returns the changed settings before and after an update. Secrets are
+// masked, so the log shows that a secret changed but not its value.
+func settingsChanges(before, after map[string]string) (map[string]string, map[string]string) {
+	oldValues, newValues := changedValues(before, after)
+	for key := range newValues {
+		if !config.IsSecretSetting(key) {
+			continue
+		}
+		for _, values := range []map[string]string{oldValues, newValues} {
+			if values[key] != "" {
+				values[key] = config.MaskedValue
+			}
+		}
+	}
+	return oldValues, newValues
+}
+
+// userSummary returns the audited fields of a user account
+func userSummary(user *models.User) map[string]any {
+	return map[string]any{
+		"username":  user.Username,
+		"email":     user.Email,
+		"role":      user.Role,
+		"is_active": user.IsActive,
+	}
+}
+
+// domainTarget names a domain in the audit log, including trashed domains
+func domainTarget(id uint64) string {
+	var domain models.Domain
+	if err := database.GetDB().Unscoped().Select("id", "name").First(&domain, id).Error; err != nil {
+		return fmt.Sprintf("domain:%d", id)
+	}
+	return "domain:" + domain.Name
+}
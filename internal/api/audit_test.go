@@ -0,0 +1,139 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// auditPage is the response of the audit log endpoint
+type auditPage struct {
+	Entries  []models.AuditLog `json:"entries"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+}
+
+// auditValues decodes a before or after summary of an audit entry
+func auditValues(t *testing.T, data string) map[string]string {
+	t.Helper()
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		t.Fatalf("decode audit summary %q: %v", data, err)
+	}
+	return values
+}
+
+func TestUpdateSettingsAuditsDiff(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.createUser("alice", models.RoleAdmin)
+	token := s.token(admin)
+	setSettings(t, map[string]string{
+		"email.smtp_host": "old.example.com",
+		"email.smtp_port": "587",
+		"email.password":  "old-secret",
+	})
+
+	w := s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{
+		"email.smtp_host": "smtp.example.com",
+		"email.smtp_port": "587", // Unchanged
+		"email.password":  "new-secret",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	w = s.do(http.MethodGet, "/api/v1/audit?action="+models.AuditSettingsUpdate, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[auditPage](t, w)
+	if page.Total != 1 || len(page.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", page.Total)
+	}
+	entry := page.Entries[0]
+	if entry.ActorID != admin.ID || entry.Actor != "alice" || entry.Target != "settings" {
+		t.Errorf("entry = %+v, want actor alice and target settings", entry)
+	}
+
+	wantBefore := map[string]string{"email.smtp_host": "old.example.com", "email.password": "******"}
+	wantAfter := map[string]string{"email.smtp_host": "smtp.example.com", "email.password": "******"}
+	if got := auditValues(t, entry.Before); !reflect.DeepEqual(got, wantBefore) {
+		t.Errorf("before = %v, want %v", got, wantBefore)
+	}
+	if got := auditValues(t, entry.After); !reflect.DeepEqual(got, wantAfter) {
+		t.Errorf("after = %v, want %v", got, wantAfter)
+	}
+}
+
+func TestUpdateSettingsUnchangedNotAudited(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	setSettings(t, map[string]string{"email.smtp_host": "smtp.example.com"})
+
+	w := s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{"email.smtp_host": "smtp.example.com"})
+	expectStatus(t, w, http.StatusOK)
+
+	var count int64
+	database.GetDB().Model(&models.AuditLog{}).Count(&count)
+	if count != 0 {
+		t.Errorf("got %d audit entries for an unchanged setting, want 0", count)
+	}
+}
+
+func TestAuditUserChanges(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	w := s.do(http.MethodPost, "/api/v1/users", token, map[string]any{
+		"username": "bob", "password": "bob-password", "role": models.RoleViewer,
+	})
+	expectStatus(t, w, http.StatusCreated)
+	user := decode[models.User](t, w)
+
+	bob := s.token(&user)
+	w = s.do(http.MethodPost, "/api/v1/auth/change-password", bob, map[string]string{
+		"username": "bob", "old_password": "bob-password", "new_password": "new-bob-password",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	w = s.do(http.MethodGet, "/api/v1/audit?target=user:bob", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[auditPage](t, w)
+	var actions []string
+	for _, entry := range page.Entries {
+		actions = append(actions, entry.Action+" by "+entry.Actor)
+	}
+	want := []string{models.AuditPasswordChange + " by bob", models.AuditUserCreate + " by admin"}
+	if !reflect.DeepEqual(actions, want) {
+		t.Errorf("entries = %v, want %v", actions, want)
+	}
+}
+
+func TestListAuditPagination(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	for range 5 {
+		if err := database.GetDB().Create(&models.AuditLog{Actor: "admin", Action: models.AuditSettingsUpdate, Target: "settings"}).Error; err != nil {
+			t.Fatalf("create audit entry: %v", err)
+		}
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/audit?page=2&page_size=2", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[auditPage](t, w)
+	if page.Total != 5 || page.Page != 2 || page.PageSize != 2 || len(page.Entries) != 2 {
+		t.Errorf("page = total %d, page %d, size %d with %d entries; want 5, 2, 2 with 2",
+			page.Total, page.Page, page.PageSize, len(page.Entries))
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/audit?page=x", token, nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestListAuditAdminOnly(t *testing.T) {
+	s := newTestServer(t)
+	viewer := s.token(s.createUser("viewer", models.RoleViewer))
+
+	w := s.do(http.MethodGet, "/api/v1/audit", viewer, nil)
+	expectStatus(t, w, http.StatusForbidden)
+}
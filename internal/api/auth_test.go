@@ -0,0 +1,88 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// expiredToken returns a token for the user that expired expiredFor ago
+func (s *testServer) expiredToken(user *models.User, expiredFor time.Duration) string {
+	s.t.Helper()
+	claims := &services.Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-expiredFor)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	return token
+}
+
+func TestRefreshTokenEndpoint(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser("alice", models.RoleViewer)
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"valid", s.token(user), http.StatusOK},
+		{"expired within grace", s.expiredToken(user, time.Hour), http.StatusOK},
+		{"expired beyond grace", s.expiredToken(user, services.DefaultRefreshGrace+time.Hour), http.StatusUnauthorized},
+		{"invalid", "not-a-token", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPost, "/api/v1/auth/refresh", tt.token, nil)
+			expectStatus(t, w, tt.want)
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			// The new token is accepted by protected routes
+			token, _ := decode[map[string]any](t, w)["token"].(string)
+			claims, err := s.auth.ValidateToken(token)
+			if err != nil {
+				t.Fatalf("refreshed token invalid: %v", err)
+			}
+			if claims.UserID != user.ID || claims.Role != models.RoleViewer {
+				t.Errorf("claims = %+v, want alice's", claims)
+			}
+			expectStatus(t, s.do(http.MethodGet, "/api/v1/domains", token, nil), http.StatusOK)
+		})
+	}
+}
+
+func TestRefreshTokenFromBody(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser("alice", models.RoleViewer)
+
+	w := s.do(http.MethodPost, "/api/v1/auth/refresh", "", map[string]string{"token": s.token(user)})
+	expectStatus(t, w, http.StatusOK)
+
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/auth/refresh", "", nil), http.StatusBadRequest)
+}
+
+func TestRefreshTokenInactiveUser(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser("alice", models.RoleViewer)
+	token := s.token(user)
+
+	database.GetDB().Model(user).Update("is_active", false)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/auth/refresh", token, nil), http.StatusUnauthorized)
+
+	database.GetDB().Delete(user)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/auth/refresh", token, nil), http.StatusUnauthorized)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/scheduler"
+	"domain-monitor/internal/services"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxBulkDomains limits the number of domains of one bulk request
+const maxBulkDomains = 5000
+
+// bulkResult is the outcome of a bulk action for one domain
+type bulkResult struct {
+	ID      uint   `json:"id"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BulkDomains applies an action (delete, activate, deactivate, tag or refresh) to a list of
+// domains. Database changes run in one transaction; unknown IDs are reported per ID.
+func (h *Handler) BulkDomains(c *gin.Context) {
+	var request struct {
+		Action string   `json:"action" binding:"required"`
+		IDs    []uint   `json:"ids" binding:"required"`
+		Tags   []string `json:"tags"` // Tags added by the tag action
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch request.Action {
+	case "delete", "activate", "deactivate", "refresh":
+	case "tag":
+		if len(request.Tags) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tags is required for the tag action"})
+			return
+		}
+		for _, tag := range request.Tags {
+			if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Tags must not be empty or contain commas"})
+				return
+			}
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "action must be one of delete, activate, deactivate, tag, refresh"})
+		return
+	}
+	if len(request.IDs) == 0 || len(request.IDs) > maxBulkDomains {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids must contain between 1 and 5000 domain IDs"})
+		return
+	}
+
+	ids := uniqueIDs(request.IDs)
+
+	db := database.GetDB()
+
+	var domains []models.Domain
+	if err := db.Omit("raw_whois").Where("id IN ?", ids).Find(&domains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	found := make(map[uint]*models.Domain, len(domains))
+	for i := range domains {
+		found[domains[i].ID] = &domains[i]
+	}
+
+	// Results keep the order of the request
+	results := make([]bulkResult, len(ids))
+	for i, id := range ids {
+		results[i] = bulkResult{ID: id, Success: true}
+		if found[id] == nil {
+			results[i] = bulkResult{ID: id, Error: "Domain not found"}
+		}
+	}
+
+	if request.Action == "refresh" {
+		// WHOIS queries are slow, so the checks run in the background like refresh-all
+		job, err := h.startRefresh(domains)
+		if errors.Is(err, services.ErrRefreshRunning) || errors.Is(err, scheduler.ErrSweepRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusAccepted, bulkResponse(request.Action, results, gin.H{"job": job}))
+		return
+	}
+
+	var requestedBy string
+	if claims, ok := CurrentClaims(c); ok {
+		requestedBy = claims.Username
+	}
+
+	err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for i := range results {
+				domain := found[results[i].ID]
+				if domain == nil {
+					continue
+				}
+				reason, err := h.applyBulkAction(tx, request.Action, domain, request.Tags, requestedBy)
+				if err != nil {
+					return err
+				}
+				if reason != "" {
+					results[i] = bulkResult{ID: domain.ID, Error: reason}
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if request.Action == "delete" {
+		action := models.AuditDomainDelete
+		if h.cfg.Security.RequireDeleteConfirmation {
+			action = models.AuditDomainDeleteRequest
+		}
+		for _, result := range results {
+			if result.Success {
+				h.audit(c, action, "domain:"+found[result.ID].Name, nil, nil)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, bulkResponse(request.Action, results, nil))
+}
+
+// applyBulkAction applies an action to one domain. It returns a reason when the action
+// doesn't apply to the domain, and an error when the transaction must be rolled back.
+func (h *Handler) applyBulkAction(tx *gorm.DB, action string, domain *models.Domain, tags []string, requestedBy string) (string, error) {
+	query := tx.Model(&models.Domain{}).Where("id = ?", domain.ID)
+
+	switch action {
+	case "delete":
+		if !h.cfg.Security.RequireDeleteConfirmation {
+			return "", tx.Delete(&models.Domain{}, domain.ID).Error
+		}
+		// Deletions still need a second admin to confirm them
+		if domain.PendingDeletion() {
+			return "Deletion already requested by " + domain.DeleteRequestedBy, nil
+		}
+		return "", query.Updates(map[string]interface{}{
+			"delete_requested_by": requestedBy,
+			"delete_requested_at": time.Now(),
+		}).Error
+	case "activate":
+		return "", query.Updates(map[string]interface{}{"is_active": true, "consecutive_failures": 0}).Error
+	case "deactivate":
+		return "", query.Update("is_active", false).Error
+	case "tag":
+		for _, tag := range tags {
+			domain.AddTag(tag)
+		}
+		return "", query.UpdateColumn("tags", domain.Tags).Error
+	}
+	return "", nil
+}
+
+// bulkResponse summarizes the per-domain results of a bulk action
+func bulkResponse(action string, results []bulkResult, extra gin.H) gin.H {
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+
+	response := gin.H{
+		"action":    action,
+		"succeeded": succeeded,
+		"failed":    len(results) - succeeded,
+		"results":   results,
+	}
+	for key, value := range extra {
+		response[key] = value
+	}
+	return response
+}
+
+// uniqueIDs removes duplicate IDs, keeping the first occurrence
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
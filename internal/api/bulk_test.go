@@ -0,0 +1,84 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// bulkResponseBody is the response of POST /domains/bulk
+type bulkResponseBody struct {
+	Action    string       `json:"action"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Results   []bulkResult `json:"results"`
+}
+
+func TestBulkDeleteMixedIDs(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	a := createDomain(t, "a.com")
+	b := createDomain(t, "b.com")
+	kept := createDomain(t, "c.com")
+
+	w := s.do(http.MethodPost, "/api/v1/domains/bulk", token, map[string]any{
+		"action": "delete",
+		"ids":    []uint{a.ID, 999, b.ID, a.ID},
+	})
+	expectStatus(t, w, http.StatusOK)
+	body := decode[bulkResponseBody](t, w)
+
+	want := []bulkResult{
+		{ID: a.ID, Success: true},
+		{ID: 999, Error: "Domain not found"},
+		{ID: b.ID, Success: true},
+	}
+	if !reflect.DeepEqual(body.Results, want) {
+		t.Errorf("results = %+v, want %+v", body.Results, want)
+	}
+	if body.Succeeded != 2 || body.Failed != 1 {
+		t.Errorf("succeeded = %d, failed = %d, want 2 and 1", body.Succeeded, body.Failed)
+	}
+
+	var remaining []models.Domain
+	database.GetDB().Find(&remaining)
+	if len(remaining) != 1 || remaining[0].ID != kept.ID {
+		t.Errorf("remaining domains = %+v, want only c.com", remaining)
+	}
+}
+
+func TestBulkActivateAndTag(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+
+	w := s.do(http.MethodPost, "/api/v1/domains/bulk", token, map[string]any{"action": "deactivate", "ids": []uint{domain.ID}})
+	expectStatus(t, w, http.StatusOK)
+	w = s.do(http.MethodPost, "/api/v1/domains/bulk", token, map[string]any{"action": "tag", "ids": []uint{domain.ID}, "tags": []string{"prod"}})
+	expectStatus(t, w, http.StatusOK)
+
+	var stored models.Domain
+	database.GetDB().First(&stored, domain.ID)
+	if stored.IsActive || !stored.HasTag("prod") {
+		t.Errorf("domain = active %v, tags %s; want inactive and tagged prod", stored.IsActive, stored.Tags)
+	}
+}
+
+func TestBulkInvalidRequest(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	tests := []map[string]any{
+		{"action": "archive", "ids": []uint{1}},
+		{"action": "delete", "ids": []uint{}},
+		{"action": "tag", "ids": []uint{1}},
+		{"ids": []uint{1}},
+	}
+	for _, body := range tests {
+		if w := s.do(http.MethodPost, "/api/v1/domains/bulk", token, body); w.Code != http.StatusBadRequest {
+			t.Errorf("bulk %v: status = %d, want 400", body, w.Code)
+		}
+	}
+}
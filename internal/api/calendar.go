@@ -0,0 +1,136 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// icsLineLimit is the maximum length of an iCalendar content line in octets (RFC 5545 3.1)
+const icsLineLimit = 75
+
+// ExportCalendar returns the expiry dates of the domains as an iCalendar (RFC 5545) feed
+// with one all-day event per domain. Reminders follow the alert days of the domain's alert
+// policy unless alarms=false. The list filters apply; retiring domains and domains without
+// an expiry date are left out.
+func (h *Handler) ExportCalendar(c *gin.Context) {
+	domains, ok := h.queryDomains(c)
+	if !ok {
+		return
+	}
+
+	alarms := c.Query("alarms") != "false" && c.Query("alarms") != "0"
+	alertDays := h.calendarAlertDays()
+
+	now := time.Now().UTC()
+	var b strings.Builder
+	w := icsWriter{b: &b}
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//domain-monitor//Domain Expiry//EN")
+	w.line("CALSCALE:GREGORIAN")
+	w.line("METHOD:PUBLISH")
+	w.line("X-WR-CALNAME:" + icsEscape("域名到期"))
+
+	for i := range domains {
+		domain := &domains[i]
+		if domain.Retiring || domain.ExpiryDate == nil {
+			continue
+		}
+
+		expiry := domain.ExpiryDate.In(models.Location())
+		summary := "Renew " + domain.Name
+		description := fmt.Sprintf("%s expires on %s", domain.Name, expiry.Format(models.DateLayout))
+		if domain.Registrar != "" {
+			description += "\nRegistrar: " + domain.Registrar
+		}
+
+		w.line("BEGIN:VEVENT")
+		// The expiry date is part of the UID, so a renewal shows up as a new event
+		w.line(fmt.Sprintf("UID:domain-%d-%s@domain-monitor", domain.ID, expiry.Format("20060102")))
+		w.line("DTSTAMP:" + now.Format("20060102T150405Z"))
+		w.line("DTSTART;VALUE=DATE:" + expiry.Format("20060102"))
+		w.line("DTEND;VALUE=DATE:" + expiry.AddDate(0, 0, 1).Format("20060102"))
+		w.line("SUMMARY:" + icsEscape(summary))
+		w.line("DESCRIPTION:" + icsEscape(description))
+		w.line("TRANSP:TRANSPARENT")
+		if alarms {
+			for _, days := range alertDays(domain) {
+				if days <= 0 {
+					continue
+				}
+				w.line("BEGIN:VALARM")
+				w.line("ACTION:DISPLAY")
+				w.line("DESCRIPTION:" + icsEscape(fmt.Sprintf("%s expires in %d days", domain.Name, days)))
+				w.line(fmt.Sprintf("TRIGGER:-P%dD", days))
+				w.line("END:VALARM")
+			}
+		}
+		w.line("END:VEVENT")
+	}
+
+	w.line("END:VCALENDAR")
+
+	c.Header("Content-Disposition", `inline; filename="domains.ics"`)
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(b.String()))
+}
+
+// calendarAlertDays returns a function giving the alert days of a domain: those of its
+// alert policy or the default policy, else monitor.alert_days. Policies are loaded once.
+func (h *Handler) calendarAlertDays() func(domain *models.Domain) []int {
+	var policies []models.AlertPolicy
+	database.GetDB().Find(&policies)
+
+	byID := make(map[uint]*models.AlertPolicy, len(policies))
+	var fallback *models.AlertPolicy
+	for i := range policies {
+		byID[policies[i].ID] = &policies[i]
+		if policies[i].IsDefault {
+			fallback = &policies[i]
+		}
+	}
+
+	return func(domain *models.Domain) []int {
+		if policy := byID[domain.AlertPolicyID]; policy != nil {
+			return policy.Days()
+		}
+		if fallback != nil {
+			return fallback.Days()
+		}
+		return h.cfg.Monitor.AlertDays
+	}
+}
+
+// icsWriter writes iCalendar content lines, folded at icsLineLimit octets and ended with CRLF
+type icsWriter struct {
+	b *strings.Builder
+}
+
+// line writes one content line. Folded lines continue with a space; multi-byte characters
+// are never split.
+func (w icsWriter) line(s string) {
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		w.b.WriteString(s[:cut])
+		w.b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = icsLineLimit - 1 // The leading space counts
+	}
+	w.b.WriteString(s)
+	w.b.WriteString("\r\n")
+}
+
+// icsEscape escapes a TEXT property value
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createExpiringDomain stores an active domain expiring on the given date
+func createExpiringDomain(t *testing.T, name string, expiry time.Time, retiring bool) {
+	t.Helper()
+	domain := &models.Domain{Name: name, IsActive: true, ExpiryDate: &expiry, Retiring: retiring}
+	if err := database.GetDB().Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+}
+
+// parseICS checks the line structure of an iCalendar document and returns its unfolded lines
+func parseICS(t *testing.T, body string) []string {
+	t.Helper()
+	if !strings.HasSuffix(body, "\r\n") {
+		t.Error("calendar doesn't end with CRLF")
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line longer than %d octets: %q", icsLineLimit, line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("bare LF in line %q", line)
+		}
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	// Components are properly nested
+	var stack []string
+	for _, line := range lines {
+		if name, ok := strings.CutPrefix(line, "BEGIN:"); ok {
+			stack = append(stack, name)
+		} else if name, ok := strings.CutPrefix(line, "END:"); ok {
+			if len(stack) == 0 || stack[len(stack)-1] != name {
+				t.Fatalf("unbalanced END:%s", name)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if len(stack) != 0 {
+		t.Errorf("unclosed components %v", stack)
+	}
+	if len(lines) == 0 || lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+		t.Error("document is not a VCALENDAR")
+	}
+	return lines
+}
+
+// countLines returns how many lines start with prefix
+func countLines(lines []string, prefix string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+func TestExportCalendar(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	loc := models.Location()
+	createExpiringDomain(t, "example.com", time.Date(2026, 5, 1, 10, 0, 0, 0, loc), false)
+	createExpiringDomain(t, "a-very-long-domain-name-to-check-that-lines-are-folded-properly.com", time.Date(2026, 8, 15, 0, 0, 0, 0, loc), false)
+	createExpiringDomain(t, "retiring.com", time.Date(2026, 6, 1, 0, 0, 0, 0, loc), true)
+	createDomain(t, "unknown.com")
+
+	w := s.do(http.MethodGet, "/api/v1/domains/calendar.ics", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("Content-Type = %q, want text/calendar", ct)
+	}
+	lines := parseICS(t, w.Body.String())
+
+	for _, want := range []string{"VERSION:2.0", "PRODID:-//domain-monitor//Domain Expiry//EN"} {
+		if countLines(lines, want) != 1 {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if got := countLines(lines, "BEGIN:VEVENT"); got != 2 {
+		t.Errorf("%d events, want 2 (retiring and unknown expiry left out)", got)
+	}
+	for _, want := range []string{
+		"SUMMARY:Renew example.com",
+		"DTSTART;VALUE=DATE:20260501",
+		"DTEND;VALUE=DATE:20260502",
+		"DTSTART;VALUE=DATE:20260815",
+	} {
+		if countLines(lines, want) != 1 {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if got := countLines(lines, "UID:"); got != 2 {
+		t.Errorf("%d UIDs, want one per event", got)
+	}
+	// Reminders for the default alert days 30, 7 and 1 of each event
+	if got := countLines(lines, "BEGIN:VALARM"); got != 6 {
+		t.Errorf("%d alarms, want 6", got)
+	}
+	if countLines(lines, "TRIGGER:-P30D") != 2 || countLines(lines, "TRIGGER:-P1D") != 2 {
+		t.Error("alarm triggers don't match the alert days")
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/domains/calendar.ics?alarms=false", token, nil)
+	if got := countLines(parseICS(t, w.Body.String()), "BEGIN:VALARM"); got != 0 {
+		t.Errorf("%d alarms with alarms=false, want 0", got)
+	}
+}
+
+func TestExportCalendarTokenParam(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/domains/calendar.ics", "", nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/domains/calendar.ics?token="+token, "", nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/domains/calendar.ics?token=invalid", "", nil), http.StatusUnauthorized)
+}
+
+func TestICSEscape(t *testing.T) {
+	got := icsEscape("a,b;c\\d\ne")
+	if want := `a\,b\;c\\d\ne`; got != want {
+		t.Errorf("icsEscape = %q, want %q", got, want)
+	}
+}
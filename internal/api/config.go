@@ -0,0 +1,152 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"io"
+	"maps"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// maxConfigImportSize limits the size of an imported configuration file
+const maxConfigImportSize = 1 << 20
+
+// loadSettingsMap loads all stored settings as a key/value map
+func loadSettingsMap() (map[string]string, error) {
+	var settings []models.Setting
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Find(&settings).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	settingsMap := make(map[string]string, len(settings))
+	for _, s := range settings {
+		settingsMap[s.Key] = s.Value
+	}
+
+	return settingsMap, nil
+}
+
+// effectiveConfig returns the file configuration with the stored settings applied
+func (h *Handler) effectiveConfig() (*config.Config, error) {
+	cfg, err := h.cfg.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := loadSettingsMap()
+	if err != nil {
+		return nil, err
+	}
+	config.ApplySettings(cfg, settings)
+
+	return cfg, nil
+}
+
+// ExportConfig exports the effective configuration as YAML.
+// Secrets are masked unless include_secrets=true is passed.
+func (h *Handler) ExportConfig(c *gin.Context) {
+	cfg, err := h.effectiveConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("include_secrets") != "true" {
+		config.MaskSecrets(cfg)
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="config.yaml"`)
+	c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", data)
+}
+
+// ImportConfig imports a YAML configuration into the stored settings. The file is merged
+// onto the effective configuration, so keys it leaves out keep their current values.
+// Only database-backed settings are imported; masked secrets are left unchanged.
+func (h *Handler) ImportConfig(c *gin.Context) {
+	data, err := io.ReadAll(io.LimitReader(c.Request.Body, maxConfigImportSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	cfg, err := h.effectiveConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	current := config.SettingsFromConfig(cfg)
+	headers := maps.Clone(cfg.Notifications.Webhook.Headers)
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid YAML: " + err.Error()})
+		return
+	}
+
+	// Masked header values, as in an export without secrets, keep their current value
+	for name, value := range cfg.Notifications.Webhook.Headers {
+		if value != config.MaskedValue {
+			continue
+		}
+		if currentValue, ok := headers[name]; ok {
+			cfg.Notifications.Webhook.Headers[name] = currentValue
+		} else {
+			delete(cfg.Notifications.Webhook.Headers, name)
+		}
+	}
+
+	// Only the values the file changes are stored
+	settings := config.SettingsFromConfig(cfg)
+	for key, value := range settings {
+		if value == config.MaskedValue || value == current[key] {
+			delete(settings, key)
+		}
+	}
+
+	// Reject the whole import if any field is invalid
+	if errs := config.ValidateSettings(settings); errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid settings", "fields": errs})
+		return
+	}
+
+	stored, err := loadSettingsMap()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			for key, value := range settings {
+				if err := tx.Save(&models.Setting{Key: key, Value: value}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	imported := len(settings)
+	if before, after := settingsChanges(stored, settings); len(after) > 0 {
+		h.audit(c, models.AuditSettingsImport, "settings", before, after)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Configuration imported successfully, restart to apply",
+		"imported": imported,
+	})
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestImportConfigMergesPartialFile(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	setSettings(t, map[string]string{
+		"email.smtp_host":        "smtp.example.com",
+		"email.password":         "smtp-secret",
+		"monitor.check_interval": "0 9 * * *",
+	})
+
+	yaml := "notifications:\n  email:\n    from: alerts@example.com\n    password: \"******\"\n"
+	w := s.do(http.MethodPost, "/api/v1/config/import", token, yaml)
+	expectStatus(t, w, http.StatusOK)
+	if imported := decode[map[string]any](t, w)["imported"]; imported != float64(1) {
+		t.Errorf("imported = %v, want 1", imported)
+	}
+
+	stored, err := loadSettingsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"email.from":             "alerts@example.com",
+		"email.smtp_host":        "smtp.example.com",
+		"email.password":         "smtp-secret",
+		"monitor.check_interval": "0 9 * * *",
+	}
+	for key, value := range want {
+		if stored[key] != value {
+			t.Errorf("%s = %q, want %q", key, stored[key], value)
+		}
+	}
+}
+
+func TestImportConfigRejectsInvalidYAML(t *testing.T) {
+	s := newTestServer(t)
+	w := s.do(http.MethodPost, "/api/v1/config/import", s.adminToken(), "monitor: [")
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestImportConfigRejectsInvalidFields(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	yaml := "monitor:\n  check_interval: every 5 min\nnotifications:\n  webhook:\n    url: not a url\n  email:\n    from: alerts@example.com\n"
+	w := s.do(http.MethodPost, "/api/v1/config/import", token, yaml)
+	expectStatus(t, w, http.StatusBadRequest)
+	body := decode[struct {
+		Fields map[string]string `json:"fields"`
+	}](t, w)
+	for _, key := range []string{"monitor.check_interval", "webhook.url"} {
+		if body.Fields[key] == "" {
+			t.Errorf("no error reported for %s: %v", key, body.Fields)
+		}
+	}
+
+	// Nothing is stored, not even the valid field
+	stored, err := loadSettingsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := stored["email.from"]; ok {
+		t.Error("valid field saved despite invalid fields in the import")
+	}
+}
+
+func TestExportConfigMasksWebhookHeaders(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	setSettings(t, map[string]string{"webhook.headers": `{"Authorization":"Bearer s3cret"}`})
+
+	w := s.do(http.MethodGet, "/api/v1/config/export", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	exported := w.Body.String()
+	if strings.Contains(exported, "s3cret") || !strings.Contains(exported, "Authorization") {
+		t.Fatalf("export does not mask the header value:\n%s", exported)
+	}
+
+	// Importing the export leaves the header unchanged
+	w = s.do(http.MethodPost, "/api/v1/config/import", token, exported)
+	expectStatus(t, w, http.StatusOK)
+	stored, err := loadSettingsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"Authorization":"Bearer s3cret"}`; stored["webhook.headers"] != want {
+		t.Errorf("webhook.headers = %q, want %q", stored["webhook.headers"], want)
+	}
+}
+
+func TestImportConfigKeepsMaskedWebhookHeaders(t *testing.T) {
+	s := newTestServer(t)
+	setSettings(t, map[string]string{"webhook.headers": `{"Authorization":"Bearer s3cret"}`})
+
+	yaml := "notifications:\n  webhook:\n    headers:\n      Authorization: \"******\"\n      X-Env: prod\n      X-Unknown: \"******\"\n"
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/config/import", s.adminToken(), yaml), http.StatusOK)
+
+	stored, err := loadSettingsMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(stored["webhook.headers"]), &headers); err != nil {
+		t.Fatalf("webhook.headers = %q: %v", stored["webhook.headers"], err)
+	}
+	want := map[string]string{"Authorization": "Bearer s3cret", "X-Env": "prod"}
+	if len(headers) != len(want) {
+		t.Errorf("headers = %v, want %v", headers, want)
+	}
+	for name, value := range want {
+		if headers[name] != value {
+			t.Errorf("header %s = %q, want %q", name, headers[name], value)
+		}
+	}
+}
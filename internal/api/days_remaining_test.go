@@ -0,0 +1,90 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// createStaleDomain creates a domain expiring in days whose days remaining were stored by a
+// check long ago
+func createStaleDomain(t *testing.T, name string, days, storedDays int) *models.Domain {
+	t.Helper()
+	expiry := time.Now().AddDate(0, 0, days)
+	checked := time.Now().AddDate(0, 0, days-storedDays)
+	domain := &models.Domain{Name: name, IsActive: true, ExpiryDate: &expiry, DaysRemaining: storedDays, LastChecked: &checked}
+	if err := database.GetDB().Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+	return domain
+}
+
+// daysRemaining returns the days remaining of the domains keyed by name
+func daysRemaining(domains []models.Domain) map[string]int {
+	days := make(map[string]int)
+	for _, domain := range domains {
+		days[domain.Name] = domain.DaysRemaining
+	}
+	return days
+}
+
+func TestDaysRemainingReflectNow(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	// Showed 1 day at the last check, expired yesterday
+	expired := createStaleDomain(t, "expired.com", -1, 1)
+	// Showed 40 days at the last check, now expiring soon
+	createStaleDomain(t, "soon.com", 10, 40)
+	createStaleDomain(t, "fresh.com", 100, 100)
+
+	w := s.do(http.MethodGet, "/api/v1/domains", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	want := map[string]int{"expired.com": -1, "soon.com": 10, "fresh.com": 100}
+	for name, days := range daysRemaining(decode[[]models.Domain](t, w)) {
+		if days != want[name] {
+			t.Errorf("list: %s days remaining = %d, want %d", name, days, want[name])
+		}
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/domains/"+strconv.Itoa(int(expired.ID)), token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Domain](t, w).DaysRemaining; got != -1 {
+		t.Errorf("get: days remaining = %d, want -1", got)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/dashboard/expiring", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := daysRemaining(decode[[]models.Domain](t, w)); len(got) != 1 || got["soon.com"] != 10 {
+		t.Errorf("expiring = %v, want only soon.com with 10 days", got)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/dashboard/stats", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	stats := decode[expiringStats](t, w)
+	if stats.Expired != 1 || stats.ExpiringSoon != 1 {
+		t.Errorf("stats = %d expired, %d expiring soon; want 1 and 1", stats.Expired, stats.ExpiringSoon)
+	}
+
+	// Reads don't write the recomputed values back; the next check does
+	var stored models.Domain
+	database.GetDB().First(&stored, expired.ID)
+	if stored.DaysRemaining != 1 {
+		t.Errorf("stored days remaining = %d, want 1", stored.DaysRemaining)
+	}
+}
+
+func TestDaysRemainingUnknownExpiry(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "unknown.com")
+	database.GetDB().Model(domain).Update("days_remaining", 12)
+
+	w := s.do(http.MethodGet, "/api/v1/domains/"+strconv.Itoa(int(domain.ID)), token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.Domain](t, w).DaysRemaining; got != 12 {
+		t.Errorf("days remaining = %d, want the stored 12", got)
+	}
+}
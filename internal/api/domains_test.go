@@ -0,0 +1,110 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCreateDomainUnknownGroup(t *testing.T) {
+	s := newTestServer(t)
+	w := s.do(http.MethodPost, "/api/v1/domains", s.adminToken(), map[string]any{"name": "example.com", "group_id": 42})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	var count int64
+	database.GetDB().Model(&models.Domain{}).Count(&count)
+	if count != 0 {
+		t.Errorf("%d domains stored, want none", count)
+	}
+}
+
+func TestUpdateDomainGroup(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	group := models.DomainGroup{Name: "customer-a"}
+	if err := database.GetDB().Create(&group).Error; err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/api/v1/domains/%d", domain.ID)
+
+	w := s.do(http.MethodPut, path, token, map[string]any{"group_id": group.ID + 1})
+	expectStatus(t, w, http.StatusBadRequest)
+
+	w = s.do(http.MethodPut, path, token, map[string]any{"group_id": group.ID})
+	expectStatus(t, w, http.StatusOK)
+
+	var stored models.Domain
+	database.GetDB().First(&stored, domain.ID)
+	if stored.GroupID != group.ID {
+		t.Errorf("group_id = %d, want %d", stored.GroupID, group.ID)
+	}
+
+	// Removing the group is always allowed
+	w = s.do(http.MethodPut, path, token, map[string]any{"group_id": 0})
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestCreateDomainNormalizesName(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Example.COM", "example.com"},
+		{"https://Example.ORG/path", "example.org"},
+		{"Bücher.DE", "xn--bcher-kva.de"},
+	}
+	for _, tt := range tests {
+		w := s.do(http.MethodPost, "/api/v1/domains", token, map[string]any{"name": tt.input})
+		expectStatus(t, w, http.StatusCreated)
+		if got := decode[models.Domain](t, w).Name; got != tt.want {
+			t.Errorf("%q stored as %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCreateDomainRejectsInvalidAndDuplicate(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createDomain(t, "example.com")
+
+	for _, name := range []string{"", "not a domain", "www.example.net", "com"} {
+		w := s.do(http.MethodPost, "/api/v1/domains", token, map[string]any{"name": name})
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+
+	// Duplicates are detected after normalization
+	w := s.do(http.MethodPost, "/api/v1/domains", token, map[string]any{"name": "HTTP://EXAMPLE.COM/"})
+	expectStatus(t, w, http.StatusConflict)
+	if msg := decode[map[string]string](t, w)["error"]; msg != "Domain example.com already exists" {
+		t.Errorf("error = %q", msg)
+	}
+}
+
+func TestImportDomainsNormalizesNames(t *testing.T) {
+	s := newTestServer(t)
+	createDomain(t, "example.com")
+
+	w := s.do(http.MethodPost, "/api/v1/domains/import", s.adminToken(), map[string]any{
+		"domains": []string{"Example.COM", "http://example.org/", "EXAMPLE.org", "bad name"},
+	})
+	expectStatus(t, w, http.StatusOK)
+	got := decode[struct {
+		Imported int      `json:"imported"`
+		Invalid  []string `json:"invalid"`
+	}](t, w)
+	if got.Imported != 1 || len(got.Invalid) != 1 || got.Invalid[0] != "bad name" {
+		t.Errorf("import result = %+v, want example.org imported and one invalid name", got)
+	}
+
+	var names []string
+	database.GetDB().Model(&models.Domain{}).Order("name").Pluck("name", &names)
+	if len(names) != 2 || names[1] != "example.org" {
+		t.Errorf("stored domains = %v", names)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"domain-monitor/internal/models"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportColumns are the columns of a domain export
+var exportColumns = []string{"name", "registrar", "expiry_date", "days_remaining", "status", "tags", "last_checked"}
+
+// exportedDomain is a domain in a JSON export
+type exportedDomain struct {
+	Name          string `json:"name"`
+	Registrar     string `json:"registrar"`
+	ExpiryDate    string `json:"expiry_date"`
+	DaysRemaining int    `json:"days_remaining"`
+	Status        string `json:"status"`
+	Tags          string `json:"tags"`
+	LastChecked   string `json:"last_checked"`
+}
+
+// ExportDomains exports the domains as CSV (default) or JSON, using the list filters
+func (h *Handler) ExportDomains(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+		return
+	}
+
+	domains, ok := h.queryDomains(c)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("domains-%s.%s", time.Now().In(models.Location()).Format("20060102"), format)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	if format == "json" {
+		exported := make([]exportedDomain, 0, len(domains))
+		for _, domain := range domains {
+			exported = append(exported, exportedDomain{
+				Name:          domain.Name,
+				Registrar:     domain.Registrar,
+				ExpiryDate:    exportDate(domain.ExpiryDate),
+				DaysRemaining: domain.DaysRemaining,
+				Status:        domain.Status,
+				Tags:          strings.Join(domain.TagList(), ","),
+				LastChecked:   exportDate(domain.LastChecked),
+			})
+		}
+		c.JSON(http.StatusOK, exported)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Write(exportColumns)
+	for _, domain := range domains {
+		writer.Write(exportRow(&domain))
+	}
+	writer.Flush()
+}
+
+// exportRow returns the export columns of a domain
+func exportRow(domain *models.Domain) []string {
+	return []string{
+		domain.Name,
+		domain.Registrar,
+		exportDate(domain.ExpiryDate),
+		strconv.Itoa(domain.DaysRemaining),
+		domain.Status,
+		strings.Join(domain.TagList(), ","),
+		exportDate(domain.LastChecked),
+	}
+}
+
+// exportDate formats a date as YYYY-MM-DD, empty when unknown
+func exportDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return models.FormatDate(*t)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/csv"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// createExportDomain stores a checked domain with an expiry date and tags
+func createExportDomain(t *testing.T, name string, groupID uint) *models.Domain {
+	t.Helper()
+	expiry := time.Date(2030, 1, 2, 12, 0, 0, 0, models.Location())
+	checked := time.Date(2026, 3, 4, 8, 0, 0, 0, models.Location())
+	domain := &models.Domain{
+		Name:          name,
+		Registrar:     "Example Registrar",
+		ExpiryDate:    &expiry,
+		Status:        "active",
+		Tags:          `["prod","web"]`,
+		LastChecked:   &checked,
+		GroupID:       groupID,
+		IsActive:      true,
+		NotifyEnabled: true,
+	}
+	if err := database.GetDB().Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+	return domain
+}
+
+func TestExportDomainsCSV(t *testing.T) {
+	s := newTestServer(t)
+	domain := createExportDomain(t, "example.com", 0)
+
+	w := s.do(http.MethodGet, "/api/v1/domains/export", s.adminToken(), nil)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment; filename=domains-") || !strings.HasSuffix(cd, ".csv") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("CSV has %d rows, want the header and one domain", len(records))
+	}
+	if want := []string{"name", "registrar", "expiry_date", "days_remaining", "status", "tags", "last_checked"}; !slices.Equal(records[0], want) {
+		t.Errorf("header = %v, want %v", records[0], want)
+	}
+	days := strconv.Itoa(models.DaysUntil(*domain.ExpiryDate, time.Now()))
+	if want := []string{"example.com", "Example Registrar", "2030-01-02", days, "active", "prod,web", "2026-03-04"}; !slices.Equal(records[1], want) {
+		t.Errorf("row = %v, want %v", records[1], want)
+	}
+}
+
+func TestExportDomainsJSONFiltered(t *testing.T) {
+	s := newTestServer(t)
+	group := models.DomainGroup{Name: "customer-a"}
+	if err := database.GetDB().Create(&group).Error; err != nil {
+		t.Fatal(err)
+	}
+	createExportDomain(t, "example.com", group.ID)
+	createExportDomain(t, "example.org", 0)
+
+	w := s.do(http.MethodGet, "/api/v1/domains/export?format=json&group_id="+strconv.Itoa(int(group.ID)), s.adminToken(), nil)
+	expectStatus(t, w, http.StatusOK)
+	exported := decode[[]exportedDomain](t, w)
+	if len(exported) != 1 || exported[0].Name != "example.com" || exported[0].ExpiryDate != "2030-01-02" || exported[0].Tags != "prod,web" {
+		t.Errorf("exported = %+v, want only example.com", exported)
+	}
+}
+
+func TestExportDomainsInvalidFormat(t *testing.T) {
+	s := newTestServer(t)
+	w := s.do(http.MethodGet, "/api/v1/domains/export?format=xlsx", s.adminToken(), nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"domain-monitor/internal/models"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default and maximum window of the expiry feed in days
+const (
+	defaultFeedDays = 30
+	maxFeedDays     = 3650
+)
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// ExportFeed returns an RSS 2.0 feed of the domains expiring within ?days= days (default 30),
+// expired ones included, soonest first. Each item is published when its domain entered the
+// window. The list filters apply; retiring domains and unknown expiry dates are left out.
+func (h *Handler) ExportFeed(c *gin.Context) {
+	days, err := queryInt(c, "days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if days == 0 {
+		days = defaultFeedDays
+	}
+	days = min(days, maxFeedDays)
+
+	domains, ok := h.queryDomains(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	items := make([]rssItem, 0)
+	sort.SliceStable(domains, func(i, j int) bool {
+		return models.TimeValue(domains[i].ExpiryDate).Before(models.TimeValue(domains[j].ExpiryDate))
+	})
+	for _, domain := range domains {
+		if domain.Retiring || domain.ExpiryDate == nil || domain.DaysRemaining > days {
+			continue
+		}
+
+		expiry := models.FormatDate(models.TimeValue(domain.ExpiryDate))
+		title := fmt.Sprintf("%s expires in %d days", domain.Name, domain.DaysRemaining)
+		description := fmt.Sprintf("%s expires on %s (%d days remaining).", domain.Name, expiry, domain.DaysRemaining)
+		if domain.DaysRemaining <= 0 {
+			title = fmt.Sprintf("%s has expired", domain.Name)
+			description = fmt.Sprintf("%s expired on %s (%d days ago).", domain.Name, expiry, -domain.DaysRemaining)
+		}
+		if domain.Registrar != "" {
+			description += " Registrar: " + domain.Registrar + "."
+		}
+
+		published := domain.ExpiryDate.AddDate(0, 0, -days)
+		if published.After(now) {
+			published = now
+		}
+
+		items = append(items, rssItem{
+			Title:       title,
+			Description: description,
+			GUID: rssGUID{
+				Value: fmt.Sprintf("domain-%d-%s", domain.ID, domain.ExpiryDate.In(models.Location()).Format("20060102")),
+			},
+			PubDate: published.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         "域名到期提醒",
+			Link:          requestBaseURL(c),
+			Description:   fmt.Sprintf("Domains expiring within %d days", days),
+			LastBuildDate: now.UTC().Format(time.RFC1123Z),
+			Items:         items,
+		},
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
+
+// requestBaseURL returns the scheme and host the request was sent to, honoring a proxy's
+// X-Forwarded-Proto header
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host + "/"
+}
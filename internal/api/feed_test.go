@@ -0,0 +1,90 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// feedTitles fetches the feed and returns the titles of its items
+func (s *testServer) feedTitles(token, query string) []string {
+	s.t.Helper()
+	w := s.do(http.MethodGet, "/api/v1/feed.rss"+query, token, nil)
+	expectStatus(s.t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/rss+xml") {
+		s.t.Errorf("Content-Type = %q, want application/rss+xml", ct)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(w.Body.Bytes(), &feed); err != nil {
+		s.t.Fatalf("feed is not valid XML: %v\n%s", err, w.Body.String())
+	}
+	if feed.Version != "2.0" || feed.Channel.Title == "" {
+		s.t.Errorf("feed = version %q, title %q", feed.Version, feed.Channel.Title)
+	}
+
+	titles := []string{}
+	for _, item := range feed.Channel.Items {
+		if _, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil {
+			s.t.Errorf("item %q pubDate %q: %v", item.Title, item.PubDate, err)
+		}
+		if item.GUID.Value == "" || item.Description == "" {
+			s.t.Errorf("item %q without a GUID or description", item.Title)
+		}
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func TestExportFeed(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	now := time.Now()
+	for _, d := range []struct {
+		name     string
+		days     int
+		retiring bool
+	}{
+		{"later.com", 60, false},
+		{"soon.com", 5, false},
+		{"month.com", 20, false},
+		{"expired.com", -3, false},
+		{"retiring.com", 2, true},
+	} {
+		expiry := now.AddDate(0, 0, d.days).Add(time.Hour)
+		domain := models.Domain{Name: d.name, IsActive: true, ExpiryDate: &expiry, Retiring: d.retiring, Registrar: "Smith & Sons <Registrar>"}
+		if err := database.GetDB().Create(&domain).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	createDomain(t, "unknown.com")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"expired.com has expired", "soon.com expires in 5 days", "month.com expires in 20 days"}},
+		{"?days=7", []string{"expired.com has expired", "soon.com expires in 5 days"}},
+		{"?days=90", []string{"expired.com has expired", "soon.com expires in 5 days", "month.com expires in 20 days", "later.com expires in 60 days"}},
+	}
+	for _, tt := range tests {
+		if got := s.feedTitles(token, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("feed%s titles = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/feed.rss?days=soon", token, nil), http.StatusBadRequest)
+}
+
+func TestExportFeedTokenParam(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/feed.rss", "", nil), http.StatusUnauthorized)
+	s.feedTitles("", "?token="+token)
+}
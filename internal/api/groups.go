@@ -0,0 +1,137 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListGroups returns all domain groups
+func (h *Handler) ListGroups(c *gin.Context) {
+	db := database.GetDB()
+
+	var groups []models.DomainGroup
+	if err := db.Order("name asc").Find(&groups).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, groups)
+}
+
+// CreateGroup adds a new domain group
+func (h *Handler) CreateGroup(c *gin.Context) {
+	var group models.DomainGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateGroup(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group.ID = 0
+	group.CreatedAt = time.Now()
+	group.UpdatedAt = time.Now()
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&group).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, group)
+}
+
+// UpdateGroup updates a domain group
+func (h *Handler) UpdateGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var group models.DomainGroup
+	if err := db.First(&group, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validateGroup(&group); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	group.ID = uint(id)
+	group.UpdatedAt = time.Now()
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(&group).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, group)
+}
+
+// DeleteGroup removes a domain group; its domains fall back to the default templates
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Domain{}).Where("group_id = ?", id).Update("group_id", 0).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&models.DomainGroup{}, id).Error
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Group deleted successfully"})
+}
+
+// validateGroup checks the group name and that all templates parse
+func validateGroup(group *models.DomainGroup) error {
+	if group.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	templates := map[string]string{}
+	if group.ChannelTemplates != "" {
+		if err := json.Unmarshal([]byte(group.ChannelTemplates), &templates); err != nil {
+			return fmt.Errorf("channel_templates must be a JSON object of channel type to template: %w", err)
+		}
+	}
+	templates["default"] = group.Template
+
+	for channel, text := range templates {
+		if _, err := template.New(channel).Parse(text); err != nil {
+			return fmt.Errorf("invalid %s template: %w", channel, err)
+		}
+	}
+	return nil
+}
@@ -1,105 +1,457 @@
 package api
 
 import (
+	"domain-monitor/internal/config"
 	"domain-monitor/internal/database"
 	"domain-monitor/internal/models"
+	"domain-monitor/internal/scheduler"
 	"domain-monitor/internal/services"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
 	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // Handler holds service dependencies
 type Handler struct {
+	cfg            *config.Config
 	monitorService *services.MonitorService
 	whoisService   *services.WhoisService
 	authService    *services.AuthService
+	loginLimiter   *services.LoginLimiter
+	scheduler      *scheduler.Scheduler
 }
 
 // NewHandler creates a new API handler
-func NewHandler(monitorService *services.MonitorService, whoisService *services.WhoisService, authService *services.AuthService) *Handler {
+func NewHandler(cfg *config.Config, monitorService *services.MonitorService, whoisService *services.WhoisService, authService *services.AuthService, sched *scheduler.Scheduler) *Handler {
 	return &Handler{
+		cfg:            cfg,
 		monitorService: monitorService,
 		whoisService:   whoisService,
 		authService:    authService,
+		loginLimiter:   services.NewLoginLimiter(&cfg.Security),
+		scheduler:      sched,
 	}
 }
 
 // SetupRoutes configures all API routes
 func SetupRoutes(r *gin.Engine, handler *Handler) {
+	// Probes report database problems themselves
+	probes := r.Group("/api/v1")
+	{
+		probes.GET("/health", handler.Health)
+		probes.GET("/ready", handler.Ready)
+	}
+
 	api := r.Group("/api/v1")
+	api.Use(RequireDB())
 	{
 		// Authentication (no auth required)
 		api.POST("/auth/login", handler.Login)
 		api.POST("/auth/validate", handler.ValidateToken)
-		api.POST("/auth/change-password", handler.ChangePassword)
+		api.POST("/auth/refresh", handler.RefreshToken)
+
+		// Inbound registrar events (token protected)
+		api.POST("/webhooks/registrar/:provider", handler.RegistrarWebhook)
+	}
+
+	// Prometheus metrics (optional token)
+	if handler.cfg.Metrics.Enabled {
+		r.GET("/metrics", handler.Metrics)
+	}
+
+	// Subscriptions of calendar apps and feed readers, which pass the credentials as ?token=
+	feeds := api.Group("")
+	feeds.Use(QueryTokenAuth(handler.authService))
+	{
+		feeds.GET("/domains/calendar.ics", handler.ExportCalendar)
+		feeds.GET("/feed.rss", handler.ExportFeed)
+	}
+
+	protected := api.Group("")
+	protected.Use(AuthMiddleware(handler.authService))
+	{
+		// Viewers have read-only access; changes require the admin role
+		admin := RequireRole(models.RoleAdmin)
+
+		protected.POST("/auth/change-password", handler.ChangePassword)
 
 		// Domain management
-		api.GET("/domains", handler.ListDomains)
-		api.POST("/domains", handler.CreateDomain)
-		api.GET("/domains/:id", handler.GetDomain)
-		api.PUT("/domains/:id", handler.UpdateDomain)
-		api.DELETE("/domains/:id", handler.DeleteDomain)
-		api.POST("/domains/import", handler.ImportDomains)
-		api.GET("/domains/:id/refresh", handler.RefreshDomain)
+		protected.GET("/domains", handler.ListDomains)
+		protected.GET("/domains/export", handler.ExportDomains)
+		protected.GET("/domains/trash", handler.ListTrash)
+		protected.POST("/domains", admin, handler.CreateDomain)
+		protected.GET("/domains/:id", handler.GetDomain)
+		protected.PUT("/domains/:id", admin, handler.UpdateDomain)
+		protected.DELETE("/domains/:id", admin, handler.DeleteDomain)
+		protected.POST("/domains/:id/delete/confirm", admin, handler.ConfirmDeleteDomain)
+		protected.POST("/domains/:id/delete/cancel", admin, handler.CancelDeleteDomain)
+		protected.POST("/domains/:id/restore", admin, handler.RestoreDomain)
+		protected.DELETE("/domains/:id/purge", admin, handler.PurgeDomain)
+		protected.POST("/domains/import", admin, handler.ImportDomains)
+		protected.POST("/domains/import/csv", admin, handler.ImportDomainsCSV)
+		protected.POST("/domains/import/:provider", admin, handler.ImportRegistrarDomains)
+		protected.GET("/domains/:id/refresh", admin, handler.RefreshDomain)
+		protected.GET("/domains/:id/whois", handler.GetWhois)
+		protected.GET("/domains/:id/whois/download", handler.DownloadWhois)
+		protected.GET("/domains/:id/history", handler.GetDomainHistory)
+		protected.POST("/domains/bulk", admin, handler.BulkDomains)
+		protected.POST("/domains/refresh-all", admin, handler.RefreshAllDomains)
+		protected.GET("/domains/refresh-all/:job", handler.GetRefreshJob)
+		protected.POST("/domains/:id/tags", admin, handler.AddDomainTags)
+		protected.DELETE("/domains/:id/tags/:tag", admin, handler.RemoveDomainTag)
+		protected.POST("/domains/:id/pause", admin, handler.PauseDomain)
+		protected.POST("/domains/:id/resume", admin, handler.ResumeDomain)
+
+		// Domain groups
+		protected.GET("/groups", handler.ListGroups)
+		protected.POST("/groups", admin, handler.CreateGroup)
+		protected.PUT("/groups/:id", admin, handler.UpdateGroup)
+		protected.DELETE("/groups/:id", admin, handler.DeleteGroup)
+
+		// Alert policies
+		protected.GET("/policies", handler.ListPolicies)
+		protected.POST("/policies", admin, handler.CreatePolicy)
+		protected.PUT("/policies/:id", admin, handler.UpdatePolicy)
+		protected.DELETE("/policies/:id", admin, handler.DeletePolicy)
+
+		// Users
+		protected.GET("/users", admin, handler.ListUsers)
+		protected.POST("/users", admin, handler.CreateUser)
+		protected.PUT("/users/:id", admin, handler.UpdateUser)
+		protected.DELETE("/users/:id", admin, handler.DeleteUser)
+
+		// API keys
+		protected.GET("/api-keys", admin, handler.ListAPIKeys)
+		protected.POST("/api-keys", admin, handler.CreateAPIKey)
+		protected.DELETE("/api-keys/:id", admin, handler.RevokeAPIKey)
+
+		// Scheduler
+		protected.GET("/scheduler/status", handler.GetSchedulerStatus)
+		protected.POST("/scheduler/run", admin, handler.RunScheduler)
 
 		// Dashboard statistics
-		api.GET("/dashboard/stats", handler.GetStats)
-		api.GET("/dashboard/expiring", handler.GetExpiring)
+		protected.GET("/dashboard/stats", handler.GetStats)
+		protected.GET("/dashboard/expiring", handler.GetExpiring)
+		protected.GET("/dashboard/renewal-cost", handler.GetRenewalCost)
 
 		// Notifications
-		api.GET("/notifications", handler.ListNotifications)
+		protected.GET("/notifications", handler.ListNotifications)
+		protected.DELETE("/notifications", admin, handler.DeleteNotifications)
+
+		// Notification channels
+		protected.GET("/channels", handler.ListChannels)
 
 		// System settings
-		api.GET("/settings", handler.GetSettings)
-		api.PUT("/settings", handler.UpdateSettings)
+		// Viewers see the settings with credentials masked; RequireRole reloads the role
+		protected.GET("/settings", RequireRole(models.RoleAdmin, models.RoleViewer), handler.GetSettings)
+		protected.PUT("/settings", admin, handler.UpdateSettings)
+		protected.GET("/config/export", admin, handler.ExportConfig)
+		protected.POST("/config/import", admin, handler.ImportConfig)
+
+		// Audit log
+		protected.GET("/audit", admin, handler.ListAudit)
 
 		// Testing
-		api.POST("/test/notification/:id", handler.TestNotification)
+		protected.POST("/test/notification/:id", admin, handler.TestNotification)
+		protected.POST("/test/channel/:type", admin, handler.TestChannel)
+	}
+}
+
+// RequireDB aborts requests with 503 while the database is unavailable
+func RequireDB() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if database.GetDB() == nil {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": database.ErrUnavailable.Error()})
+			return
+		}
+		c.Next()
 	}
 }
 
 // ListDomains retrieves all domains
 func (h *Handler) ListDomains(c *gin.Context) {
+	domains, ok := h.queryDomains(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// queryDomains loads the domains matching the list filters and sort order of the request.
+// On failure the error response is written and ok is false.
+func (h *Handler) queryDomains(c *gin.Context) ([]models.Domain, bool) {
 	db := database.GetDB()
 
+	// Retiring domains are listed after the ones being kept
+	query := db.Omit("raw_whois").Order("retiring asc").Order("expiry_date asc")
+	if groupID := c.Query("group_id"); groupID != "" {
+		query = query.Where("group_id = ?", groupID)
+	}
+	if retiring := c.Query("retiring"); retiring != "" {
+		query = query.Where("retiring = ?", retiring == "true" || retiring == "1")
+	}
+
 	var domains []models.Domain
-	if err := db.Order("expiry_date asc").Find(&domains).Error; err != nil {
+	if err := query.Find(&domains).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		return nil, false
 	}
+	refreshDaysRemaining(domains)
 
-	c.JSON(http.StatusOK, domains)
+	// Age is derived from the registration date, so it is filtered and sorted here
+	minAge, err := queryInt(c, "min_age_days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	maxAge, err := queryInt(c, "max_age_days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if minAge > 0 || maxAge > 0 {
+		filtered := make([]models.Domain, 0, len(domains))
+		for _, domain := range domains {
+			if domain.CreatedDate == nil || domain.AgeDays < minAge || (maxAge > 0 && domain.AgeDays > maxAge) {
+				continue
+			}
+			filtered = append(filtered, domain)
+		}
+		domains = filtered
+	}
+
+	// Tags are stored as JSON arrays, so the filter runs here; all given tags must match
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		filtered := make([]models.Domain, 0, len(domains))
+		for _, domain := range domains {
+			if hasAllTags(&domain, tags) {
+				filtered = append(filtered, domain)
+			}
+		}
+		domains = filtered
+	}
+
+	switch c.Query("sort") {
+	case "age": // Oldest first
+		sortByAge(domains, true)
+	case "-age": // Newest first
+		sortByAge(domains, false)
+	}
+
+	return domains, true
+}
+
+// refreshDaysRemaining recomputes the days remaining of domains loaded for a response. The
+// stored column is only updated by checks, so it can be a day or more behind.
+func refreshDaysRemaining(domains []models.Domain) {
+	now := time.Now()
+	for i := range domains {
+		domains[i].RefreshDaysRemaining(now)
+	}
+}
+
+// hasAllTags reports whether the domain has every tag
+func hasAllTags(domain *models.Domain, tags []string) bool {
+	for _, tag := range tags {
+		if !domain.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// sortByAge sorts domains by age, keeping domains without a registration date last
+func sortByAge(domains []models.Domain, oldestFirst bool) {
+	sort.SliceStable(domains, func(i, j int) bool {
+		a, b := domains[i], domains[j]
+		if (a.CreatedDate == nil) != (b.CreatedDate == nil) {
+			return b.CreatedDate == nil
+		}
+		if oldestFirst {
+			return a.AgeDays > b.AgeDays
+		}
+		return a.AgeDays < b.AgeDays
+	})
+}
+
+// queryInt parses an optional non-negative integer query parameter
+func queryInt(c *gin.Context, key string) (int, error) {
+	value := c.Query(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %q", key, value)
+	}
+	return n, nil
+}
+
+// queryPage parses the page and page_size query parameters. It writes the error response
+// and returns false when they are invalid.
+func queryPage(c *gin.Context, defaultSize, maxSize int) (int, int, bool) {
+	page, err := queryInt(c, "page")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+
+	pageSize, err := queryInt(c, "page_size")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+	if pageSize == 0 {
+		pageSize = defaultSize
+	}
+
+	return max(page, 1), min(pageSize, maxSize), true
 }
 
 // CreateDomain adds a new domain
 func (h *Handler) CreateDomain(c *gin.Context) {
-	var domain models.Domain
-	if err := c.ShouldBindJSON(&domain); err != nil {
+	var request struct {
+		models.Domain
+		NotifyEnabled *bool `json:"notify_enabled"` // Defaults to true when omitted
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	domain := request.Domain
+	if domain.CheckFrequencyDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check_frequency_days must not be negative"})
+		return
+	}
+	if err := normalizeDomainChecks(&domain); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := normalizeRenewalCost(&domain); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !checkDomainGroup(c, &domain) {
+		return
+	}
 
-	db := database.GetDB()
+	name, err := services.NormalizeDomain(domain.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	domain.Name = name
+
+	if err := h.checkTLD(c, domain.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Set initial values
 	domain.CreatedAt = time.Now()
 	domain.UpdatedAt = time.Now()
 	domain.IsActive = true
-
-	if err := db.Create(&domain).Error; err != nil {
+	notifyEnabled := request.NotifyEnabled == nil || *request.NotifyEnabled
+	domain.NotifyEnabled = notifyEnabled
+
+	// The unique index on the names of live domains rejects duplicates
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&domain).Error
+	}); database.IsDuplicateKey(err) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Domain %s already exists", name)})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// GORM replaces zero values of fields with a default, so store a muted domain explicitly
+	if !notifyEnabled {
+		if err := database.WithRetry(func(db *gorm.DB) error {
+			return db.Model(&domain).Update("notify_enabled", false).Error
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// Immediately check the domain
 	go h.monitorService.CheckDomain(&domain)
 
 	c.JSON(http.StatusCreated, domain)
 }
 
+// normalizeDomainChecks validates the DNS and uptime check settings of a domain. DNS record
+// types are stored upper-cased without duplicates.
+func normalizeDomainChecks(domain *models.Domain) error {
+	recordTypes, err := models.ParseDNSRecordTypes(domain.DNSRecordTypes)
+	if err != nil {
+		return err
+	}
+	domain.DNSRecordTypes = strings.Join(recordTypes, ",")
+
+	domain.UptimeURL = strings.TrimSpace(domain.UptimeURL)
+	if domain.UptimeURL != "" {
+		u, err := url.Parse(domain.UptimeURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("uptime_url must be an http or https URL")
+		}
+	}
+	return nil
+}
+
+// normalizeRenewalCost validates the renewal cost of a domain. The currency is stored
+// upper-cased and is required when a cost is set.
+func normalizeRenewalCost(domain *models.Domain) error {
+	if domain.RenewalCost < 0 {
+		return fmt.Errorf("renewal_cost must not be negative")
+	}
+	domain.Currency = strings.ToUpper(strings.TrimSpace(domain.Currency))
+	if domain.Currency != "" && !currencyPattern.MatchString(domain.Currency) {
+		return fmt.Errorf("currency must be a three-letter ISO 4217 code, e.g. USD")
+	}
+	if domain.RenewalCost > 0 && domain.Currency == "" {
+		return fmt.Errorf("currency is required when renewal_cost is set")
+	}
+	return nil
+}
+
+// currencyPattern matches ISO 4217 currency codes
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// checkDomainGroup verifies that the group a domain references exists, writing the error
+// response and returning false otherwise
+func checkDomainGroup(c *gin.Context, domain *models.Domain) bool {
+	if domain.GroupID == 0 {
+		return true
+	}
+
+	var count int64
+	if err := database.GetDB().Model(&models.DomainGroup{}).Where("id = ?", domain.GroupID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if count == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Group %d does not exist", domain.GroupID)})
+		return false
+	}
+	return true
+}
+
 // GetDomain retrieves a single domain
 func (h *Handler) GetDomain(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -116,6 +468,9 @@ func (h *Handler) GetDomain(c *gin.Context) {
 		return
 	}
 
+	domain.RefreshDaysRemaining(time.Now())
+	domain.NextAlert = h.monitorService.NextAlert(&domain)
+
 	c.JSON(http.StatusOK, domain)
 }
 
@@ -135,14 +490,38 @@ func (h *Handler) UpdateDomain(c *gin.Context) {
 		return
 	}
 
+	wasActive := domain.IsActive
 	if err := c.ShouldBindJSON(&domain); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if domain.CheckFrequencyDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "check_frequency_days must not be negative"})
+		return
+	}
+	if err := normalizeDomainChecks(&domain); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := normalizeRenewalCost(&domain); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !checkDomainGroup(c, &domain) {
+		return
+	}
+
+	// Re-enabled domains start over, so an auto-disabled one isn't turned off by its next failure
+	if domain.IsActive && !wasActive {
+		domain.ConsecutiveFailures = 0
+	}
+
 	domain.UpdatedAt = time.Now()
 
-	if err := db.Save(&domain).Error; err != nil {
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(&domain).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -150,7 +529,7 @@ func (h *Handler) UpdateDomain(c *gin.Context) {
 	c.JSON(http.StatusOK, domain)
 }
 
-// DeleteDomain removes a domain
+// DeleteDomain moves a domain to the trash (or requests deletion when confirmation is required)
 func (h *Handler) DeleteDomain(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -158,16 +537,129 @@ func (h *Handler) DeleteDomain(c *gin.Context) {
 		return
 	}
 
+	if h.cfg.Security.RequireDeleteConfirmation {
+		h.requestDomainDeletion(c, id)
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Delete(&models.Domain{}, id).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit(c, models.AuditDomainDelete, domainTarget(id), nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Domain moved to trash"})
+}
+
+// requestDomainDeletion marks a domain as pending deletion until another admin confirms it
+func (h *Handler) requestDomainDeletion(c *gin.Context, id uint64) {
+	claims, _ := CurrentClaims(c)
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+	if domain.PendingDeletion() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Deletion already requested by " + domain.DeleteRequestedBy})
+		return
+	}
+
+	domain.DeleteRequestedBy = claims.Username
+	domain.DeleteRequestedAt = models.NullTime(time.Now())
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"delete_requested_by": domain.DeleteRequestedBy,
+			"delete_requested_at": domain.DeleteRequestedAt,
+		}).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit(c, models.AuditDomainDeleteRequest, "domain:"+domain.Name, nil, nil)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Deletion requested, another admin must confirm it",
+		"domain":  domain,
+	})
+}
+
+// ConfirmDeleteDomain moves a domain pending deletion to the trash; the requester can't confirm their own request
+func (h *Handler) ConfirmDeleteDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	claims, _ := CurrentClaims(c)
+
 	db := database.GetDB()
 
-	if err := db.Delete(&models.Domain{}, id).Error; err != nil {
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+	if !domain.PendingDeletion() {
+		c.JSON(http.StatusConflict, gin.H{"error": "Deletion of this domain has not been requested"})
+		return
+	}
+	if domain.DeleteRequestedBy == claims.Username {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Deletion must be confirmed by a different admin"})
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Delete(&models.Domain{}, id).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.audit(c, models.AuditDomainDeleteConfirm, "domain:"+domain.Name, map[string]string{"requested_by": domain.DeleteRequestedBy}, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Domain deleted successfully"})
 }
 
+// CancelDeleteDomain withdraws a pending deletion request
+func (h *Handler) CancelDeleteDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	requestedBy := domain.DeleteRequestedBy
+	domain.DeleteRequestedBy = ""
+	domain.DeleteRequestedAt = nil
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"delete_requested_by": "",
+			"delete_requested_at": nil,
+		}).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit(c, models.AuditDomainDeleteCancel, "domain:"+domain.Name, map[string]string{"requested_by": requestedBy}, nil)
+
+	c.JSON(http.StatusOK, domain)
+}
+
 // ImportDomains imports multiple domains
 func (h *Handler) ImportDomains(c *gin.Context) {
 	var request struct {
@@ -179,18 +671,89 @@ func (h *Handler) ImportDomains(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
+	imported, unsupported, invalid := h.importDomains(request.Domains)
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":           len(request.Domains),
+		"imported":        imported,
+		"unsupported_tld": unsupported, // Skipped in block mode, imported with a warning in warn mode
+		"invalid":         invalid,     // Not a registrable domain name, skipped
+	})
+}
+
+// ImportRegistrarDomains imports all domains of a registrar account
+func (h *Handler) ImportRegistrarDomains(c *gin.Context) {
+	var request struct {
+		Credentials map[string]string `json:"credentials"`
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	importer, err := services.NewRegistrarImporter(c.Param("provider"), request.Credentials)
+	if errors.Is(err, services.ErrUnknownImporter) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	names, err := importer.ListDomains()
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	imported, unsupported, invalid := h.importDomains(names)
+
+	c.JSON(http.StatusOK, gin.H{
+		"provider":        c.Param("provider"),
+		"total":           len(names),
+		"imported":        imported,
+		"unsupported_tld": unsupported,
+		"invalid":         invalid,
+	})
+}
+
+// importDomains normalizes, creates and checks the given domains, skipping existing ones
+// (rejected by the unique index on domain names).
+// It returns the number imported, the names with an unsupported TLD and the invalid names.
+func (h *Handler) importDomains(names []string) (int, []string, []string) {
 	imported := 0
+	unsupported := make([]string, 0)
+	invalid := make([]string, 0)
+
+	for _, name := range names {
+		domainName, err := services.NormalizeDomain(name)
+		if err != nil {
+			invalid = append(invalid, name)
+			continue
+		}
+
+		if mode := h.tldValidation(); mode != "" {
+			if err := h.whoisService.CheckTLD(domainName); err != nil {
+				unsupported = append(unsupported, domainName)
+				if mode == "block" {
+					continue
+				}
+			}
+		}
 
-	for _, domainName := range request.Domains {
 		domain := models.Domain{
-			Name:      domainName,
-			IsActive:  true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			Name:          domainName,
+			IsActive:      true,
+			NotifyEnabled: true,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
 		}
 
-		if err := db.Create(&domain).Error; err != nil {
+		if err := database.WithRetry(func(db *gorm.DB) error {
+			return db.Create(&domain).Error
+		}); err != nil {
 			continue
 		}
 
@@ -198,10 +761,38 @@ func (h *Handler) ImportDomains(c *gin.Context) {
 		go h.monitorService.CheckDomain(&domain)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"total":    len(request.Domains),
-		"imported": imported,
-	})
+	return imported, unsupported, invalid
+}
+
+// tldValidation returns the TLD validation mode (warn/block), empty when disabled
+func (h *Handler) tldValidation() string {
+	switch mode := h.cfg.Whois.TLDValidation; mode {
+	case "warn", "block":
+		return mode
+	default:
+		return ""
+	}
+}
+
+// checkTLD validates the TLD of a new domain according to whois.tld_validation.
+// In warn mode the problem is returned as a Warning header; in block mode as an error.
+func (h *Handler) checkTLD(c *gin.Context, name string) error {
+	mode := h.tldValidation()
+	if mode == "" {
+		return nil
+	}
+
+	err := h.whoisService.CheckTLD(name)
+	if err == nil {
+		return nil
+	}
+	if mode == "block" {
+		return err
+	}
+
+	slog.Warn("Adding domain despite TLD check", "domain", name, "error", err)
+	c.Header("Warning", fmt.Sprintf("299 - %q", err.Error()))
+	return nil
 }
 
 // RefreshDomain manually refreshes a domain's WHOIS data
@@ -220,7 +811,8 @@ func (h *Handler) RefreshDomain(c *gin.Context) {
 		return
 	}
 
-	if err := h.monitorService.CheckDomain(&domain); err != nil {
+	if err := h.monitorService.RefreshDomain(c.Request.Context(), &domain); err != nil {
+		slog.WarnContext(c.Request.Context(), "Domain refresh failed", "domain", domain.Name, "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -228,8 +820,222 @@ func (h *Handler) RefreshDomain(c *gin.Context) {
 	c.JSON(http.StatusOK, domain)
 }
 
-// GetStats retrieves dashboard statistics
+// RefreshAllDomains queues a re-check of all active domains, optionally scoped by group_id or tag
+func (h *Handler) RefreshAllDomains(c *gin.Context) {
+	db := database.GetDB()
+
+	query := db.Where("is_active = ?", true)
+	if groupID := c.Query("group_id"); groupID != "" {
+		id, err := strconv.ParseUint(groupID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+			return
+		}
+		query = query.Where("group_id = ?", id)
+	}
+
+	var domains []models.Domain
+	if err := query.Find(&domains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if tag := c.Query("tag"); tag != "" {
+		matched := make([]models.Domain, 0, len(domains))
+		for _, domain := range domains {
+			if domain.HasTag(tag) {
+				matched = append(matched, domain)
+			}
+		}
+		domains = matched
+	}
+
+	// WHOIS queries are slow, so the checks run in the background
+	job, err := h.startRefresh(domains)
+	if errors.Is(err, services.ErrRefreshRunning) || errors.Is(err, scheduler.ErrSweepRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "job": job})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Refresh queued",
+		"queued":  len(domains),
+		"job":     job,
+	})
+}
+
+// GetRefreshJob returns the progress of a refresh-all job
+func (h *Handler) GetRefreshJob(c *gin.Context) {
+	job, ok := h.monitorService.RefreshJob(c.Param("job"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refresh job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetWhois returns the stored raw WHOIS response of a domain together with the values
+// parsed from it by the last check, to debug responses that don't parse
+func (h *Handler) GetWhois(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	if domain.RawWhois == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No WHOIS data stored for this domain, refresh it first"})
+		return
+	}
+
+	// API and RDAP responses are JSON and returned as is, native WHOIS responses are text
+	format := "text"
+	var raw interface{} = domain.RawWhois
+	if json.Valid([]byte(domain.RawWhois)) {
+		format = "json"
+		raw = json.RawMessage(domain.RawWhois)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"domain":     domain.Name,
+		"checked_at": domain.LastChecked,
+		"format":     format,
+		"raw":        raw,
+		"parsed": services.DomainInfo{
+			Domain:      domain.Name,
+			Registrar:   domain.Registrar,
+			ExpiryDate:  models.TimeValue(domain.ExpiryDate),
+			CreatedDate: models.TimeValue(domain.CreatedDate),
+			UpdatedDate: models.TimeValue(domain.UpdatedDate),
+			Status:      domain.Status,
+			NameServers: domain.NameServerList(),
+		},
+	})
+}
+
+// DownloadWhois returns the stored raw WHOIS response of a domain as a file
+func (h *Handler) DownloadWhois(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	if domain.RawWhois == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No WHOIS data stored for this domain, refresh it first"})
+		return
+	}
+
+	// API and RDAP responses are JSON, native WHOIS responses are plain text
+	ext, contentType := "txt", "text/plain; charset=utf-8"
+	if json.Valid([]byte(domain.RawWhois)) {
+		ext, contentType = "json", "application/json; charset=utf-8"
+	}
+
+	filename := fmt.Sprintf("%s-whois-%s.%s", domain.Name, models.TimeValue(domain.LastChecked).Format("20060102-150405"), ext)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Data(http.StatusOK, contentType, []byte(domain.RawWhois))
+}
+
+// PauseDomain pauses scheduled checks of a domain until a date
+func (h *Handler) PauseDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	var request struct {
+		Until  time.Time `json:"until" binding:"required"` // RFC 3339, e.g. 2026-01-31T00:00:00+08:00
+		Reason string    `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !request.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	if err := h.monitorService.PauseDomain(&domain, request.Until, request.Reason); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// ResumeDomain resumes scheduled checks of a paused domain
+func (h *Handler) ResumeDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	if err := h.monitorService.ResumeDomain(&domain); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// Default and maximum expiring soon window in days
+const (
+	defaultExpiringDays = 30
+	maxExpiringDays     = 3650
+)
+
+// defaultExpiringBuckets are the days remaining buckets of the dashboard stats
+var defaultExpiringBuckets = []int{7, 30, 90}
+
+// GetStats retrieves dashboard statistics. ?days= overrides the expiring soon window, and
+// expiring counts the domains within each of monitor.expiring_buckets.
 func (h *Handler) GetStats(c *gin.Context) {
+	days, ok := h.expiringDays(c)
+	if !ok {
+		return
+	}
+
 	db := database.GetDB()
 
 	var total int64
@@ -238,50 +1044,131 @@ func (h *Handler) GetStats(c *gin.Context) {
 	var active int64
 	db.Model(&models.Domain{}).Where("is_active = ?", true).Count(&active)
 
-	var expiringSoon int64
-	db.Model(&models.Domain{}).Where("days_remaining <= ? AND days_remaining > 0", 30).Count(&expiringSoon)
+	// Days remaining are recomputed from the expiry dates, since the stored values are only
+	// as current as the last check
+	var domains []models.Domain
+	if err := db.Select("id", "expiry_date", "days_remaining", "retiring", "check_status").Find(&domains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	refreshDaysRemaining(domains)
+
+	// Retiring domains are counted separately rather than as expiring
+	expiringWithin := func(days int) int {
+		count := 0
+		for _, domain := range domains {
+			if !domain.Retiring && domain.ExpiryDate != nil && domain.DaysRemaining > 0 && domain.DaysRemaining <= days {
+				count++
+			}
+		}
+		return count
+	}
+	expiringSoon := expiringWithin(days)
+
+	buckets := make([]gin.H, 0, len(h.expiringBuckets()))
+	for _, bucket := range h.expiringBuckets() {
+		buckets = append(buckets, gin.H{"days": bucket, "count": expiringWithin(bucket)})
+	}
+
+	// Without a parsed expiry date days remaining is meaningless, so those domains aren't expired
+	expired := 0
+	for _, domain := range domains {
+		if domain.ExpiryDate != nil && domain.DaysRemaining <= 0 && domain.CheckStatus != models.CheckStatusParseError {
+			expired++
+		}
+	}
+
+	var retiring int64
+	db.Model(&models.Domain{}).Where("retiring = ?", true).Count(&retiring)
 
-	var expired int64
-	db.Model(&models.Domain{}).Where("days_remaining <= ?", 0).Count(&expired)
+	// Unparseable WHOIS answers and repeatedly failing checks
+	var needsAttention int64
+	db.Model(&models.Domain{}).Where("check_status = ? OR last_error <> ?", models.CheckStatusParseError, "").Count(&needsAttention)
 
 	c.JSON(http.StatusOK, gin.H{
-		"total":          total,
-		"active":         active,
-		"expiring_soon":  expiringSoon,
-		"expired":        expired,
+		"total":           total,
+		"active":          active,
+		"expiring_days":   days,
+		"expiring_soon":   expiringSoon,
+		"expiring":        buckets,
+		"expired":         expired,
+		"retiring":        retiring,
+		"needs_attention": needsAttention,
 	})
 }
 
 // GetExpiring retrieves domains expiring soon
 func (h *Handler) GetExpiring(c *gin.Context) {
+	days, ok := h.expiringDays(c)
+	if !ok {
+		return
+	}
+
 	db := database.GetDB()
 
+	// The stored days remaining can only be too high between checks, so the window is
+	// applied after recomputing them from the expiry dates
 	var domains []models.Domain
-	if err := db.Where("days_remaining <= ? AND days_remaining > 0", 30).
-		Order("days_remaining asc").
-		Limit(10).
-		Find(&domains).Error; err != nil {
+	if err := db.Where("retiring = ?", false).Find(&domains).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	refreshDaysRemaining(domains)
+	sort.SliceStable(domains, func(i, j int) bool {
+		return models.TimeValue(domains[i].ExpiryDate).Before(models.TimeValue(domains[j].ExpiryDate))
+	})
 
-	c.JSON(http.StatusOK, domains)
+	expiring := make([]models.Domain, 0, 10)
+	for _, domain := range domains {
+		if domain.ExpiryDate == nil || domain.DaysRemaining <= 0 || domain.DaysRemaining > days {
+			continue
+		}
+		if expiring = append(expiring, domain); len(expiring) == 10 {
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, expiring)
 }
 
-// ListNotifications retrieves notification history
-func (h *Handler) ListNotifications(c *gin.Context) {
-	db := database.GetDB()
+// expiringDays returns the expiring soon window: ?days=, or monitor.expiring_days. It writes
+// the error response and returns false when the parameter is invalid.
+func (h *Handler) expiringDays(c *gin.Context) (int, bool) {
+	days, err := queryInt(c, "days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	if days == 0 {
+		days = h.cfg.Monitor.ExpiringDays
+	}
+	if days <= 0 {
+		days = defaultExpiringDays
+	}
+	return min(days, maxExpiringDays), true
+}
 
-	var notifications []models.Notification
-	if err := db.Order("sent_at desc").Limit(100).Find(&notifications).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+// expiringBuckets returns the sorted positive monitor.expiring_buckets, or the defaults
+func (h *Handler) expiringBuckets() []int {
+	var buckets []int
+	for _, days := range h.cfg.Monitor.ExpiringBuckets {
+		if days > 0 && !slices.Contains(buckets, days) {
+			buckets = append(buckets, days)
+		}
+	}
+	if len(buckets) == 0 {
+		return defaultExpiringBuckets
 	}
+	slices.Sort(buckets)
+	return buckets
+}
 
-	c.JSON(http.StatusOK, notifications)
+// ListChannels returns the supported notification channels and their config fields
+func (h *Handler) ListChannels(c *gin.Context) {
+	c.JSON(http.StatusOK, services.SupportedChannels())
 }
 
-// GetSettings retrieves system settings
+// GetSettings retrieves system settings. Credentials are only shown to admins.
 func (h *Handler) GetSettings(c *gin.Context) {
 	db := database.GetDB()
 
@@ -291,6 +1178,14 @@ func (h *Handler) GetSettings(c *gin.Context) {
 		return
 	}
 
+	if claims, ok := CurrentClaims(c); !ok || claims.UserRole() != models.RoleAdmin {
+		for i := range settings {
+			if config.IsSecretSetting(settings[i].Key) && settings[i].Value != "" {
+				settings[i].Value = config.MaskedValue
+			}
+		}
+	}
+
 	c.JSON(http.StatusOK, settings)
 }
 
@@ -302,14 +1197,43 @@ func (h *Handler) UpdateSettings(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
+	// Reject the whole update if any field is invalid
+	if errs := config.ValidateSettings(settings); errs != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid settings", "fields": errs})
+		return
+	}
+
+	stored, err := loadSettingsMap()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	interval, intervalChanged := settings["monitor.check_interval"]
+	intervalChanged = intervalChanged && interval != ""
 
 	for key, value := range settings {
 		setting := models.Setting{
 			Key:   key,
 			Value: value,
 		}
-		db.Save(&setting)
+		if err := database.WithRetry(func(db *gorm.DB) error {
+			return db.Save(&setting).Error
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if before, after := settingsChanges(stored, settings); len(after) > 0 {
+		h.audit(c, models.AuditSettingsUpdate, "settings", before, after)
+	}
+
+	// Apply the new interval without a restart
+	if intervalChanged && h.scheduler != nil {
+		if err := h.scheduler.Reschedule(interval); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Settings updated successfully"})
@@ -352,26 +1276,38 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	// Refuse attempts while the username or client IP is locked out
+	clientIP := c.ClientIP()
+	if wait, ok := h.loginLimiter.Allow(loginReq.Username, clientIP, time.Now()); !ok {
+		minutes := int(wait.Minutes()) + 1
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": fmt.Sprintf("登录失败次数过多，请 %d 分钟后再试", minutes)})
+		return
+	}
+
 	db := database.GetDB()
 
 	// Find user by username
 	var user models.User
 	if err := db.Where("username = ?", loginReq.Username).First(&user).Error; err != nil {
+		h.loginLimiter.Fail(loginReq.Username, clientIP, time.Now())
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误"})
 		return
 	}
 
-	// Check if account is active
-	if !user.IsActive {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "账户已被禁用"})
-		return
-	}
-
 	// Verify password
 	if !h.authService.CheckPassword(user.Password, loginReq.Password) {
+		h.loginLimiter.Fail(loginReq.Username, clientIP, time.Now())
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户名或密码错误"})
 		return
 	}
+	h.loginLimiter.Succeed(loginReq.Username)
+
+	// Check if account is active
+	if !user.IsActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "账户已被禁用"})
+		return
+	}
 
 	// Generate JWT token
 	token, err := h.authService.GenerateToken(&user)
@@ -386,6 +1322,62 @@ func (h *Handler) Login(c *gin.Context) {
 			"id":       user.ID,
 			"username": user.Username,
 			"email":    user.Email,
+			"role":     user.Role,
+		},
+	})
+}
+
+// RefreshToken re-issues a token that is still valid or expired within the grace period,
+// taken from the Authorization header or the request body
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		c.ShouldBindJSON(&req)
+		token = req.Token
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token 不能为空"})
+		return
+	}
+
+	claims, err := h.authService.RefreshToken(token)
+	if errors.Is(err, services.ErrRefreshExpired) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token 已过期，请重新登录"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "无效的 token"})
+		return
+	}
+
+	// The account may have been deleted or disabled since the token was issued
+	var user models.User
+	if err := database.GetDB().First(&user, claims.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+		return
+	}
+	if !user.IsActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "账户已被禁用"})
+		return
+	}
+
+	newToken, err := h.authService.GenerateToken(&user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "生成 token 失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token": newToken,
+		"user": gin.H{
+			"id":       user.ID,
+			"username": user.Username,
+			"email":    user.Email,
+			"role":     user.Role,
 		},
 	})
 }
@@ -412,6 +1404,7 @@ func (h *Handler) ValidateToken(c *gin.Context) {
 		"user": gin.H{
 			"id":       claims.UserID,
 			"username": claims.Username,
+			"role":     claims.UserRole(),
 		},
 	})
 }
@@ -435,6 +1428,12 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 		return
 	}
 
+	// Users can only change their own password
+	if claims, ok := CurrentClaims(c); ok && claims.Username != req.Username {
+		c.JSON(http.StatusForbidden, gin.H{"error": "只能修改自己的密码"})
+		return
+	}
+
 	db := database.GetDB()
 
 	// Find user by username
@@ -461,10 +1460,35 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 	user.Password = hashedPassword
 	user.UpdatedAt = time.Now()
 
-	if err := db.Save(&user).Error; err != nil {
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(&user).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "密码更新失败"})
 		return
 	}
+	h.audit(c, models.AuditPasswordChange, "user:"+user.Username, nil, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "密码修改成功，请使用新密码登录"})
 }
+
+// TestChannel sends a sample notification through one channel using the current settings,
+// so credentials can be verified before any domain is added
+func (h *Handler) TestChannel(c *gin.Context) {
+	cfg, err := h.effectiveConfig()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = services.TestChannel(c.Param("type"), &cfg.Notifications)
+	if errors.Is(err, services.ErrUnknownChannel) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Test notification sent successfully"})
+}
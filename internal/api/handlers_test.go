@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTestChannel(t *testing.T) {
+	var received int
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+	}))
+	defer webhook.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	w := s.do(http.MethodPost, "/api/v1/test/channel/carrier-pigeon", token, nil)
+	expectStatus(t, w, http.StatusBadRequest)
+
+	// The channel is tested even though it is disabled
+	setSettings(t, map[string]string{"webhook.enabled": "false", "webhook.url": webhook.URL})
+	w = s.do(http.MethodPost, "/api/v1/test/channel/webhook", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if received != 1 {
+		t.Errorf("webhook received %d requests, want 1", received)
+	}
+
+	setSettings(t, map[string]string{"webhook.url": failing.URL})
+	w = s.do(http.MethodPost, "/api/v1/test/channel/webhook", token, nil)
+	expectStatus(t, w, http.StatusBadGateway)
+	if msg := decode[map[string]string](t, w)["error"]; msg == "" {
+		t.Error("failed test returned no error message")
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"domain-monitor/internal/database"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// probeTimeout bounds the database ping of the health probes
+const probeTimeout = 2 * time.Second
+
+// Health reports the state of the database and the scheduler. It always answers 200
+// so that liveness probes don't restart the server over a database outage.
+func (h *Handler) Health(c *gin.Context) {
+	dbOK, schedulerRunning := h.probe(c.Request.Context())
+
+	status := "ok"
+	if !dbOK || !schedulerRunning {
+		status = "degraded"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":            status,
+		"db_ok":             dbOK,
+		"scheduler_running": schedulerRunning,
+		"last_check_time":   h.monitorService.LastCheckTime(),
+	})
+}
+
+// Ready answers 503 until the database is migrated and reachable and the scheduler runs
+func (h *Handler) Ready(c *gin.Context) {
+	dbOK, schedulerRunning := h.probe(c.Request.Context())
+	if !dbOK || !schedulerRunning {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"ready":             false,
+			"db_ok":             dbOK,
+			"scheduler_running": schedulerRunning,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ready": true})
+}
+
+// probe pings the database and checks the scheduler
+func (h *Handler) probe(ctx context.Context) (bool, bool) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	dbOK := database.Ping(ctx) == nil
+	schedulerRunning := h.scheduler != nil && h.scheduler.Running()
+	return dbOK, schedulerRunning
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"net/http"
+	"testing"
+)
+
+func TestHealthy(t *testing.T) {
+	s := newTestServer(t)
+	s.startScheduler("0 9 * * *")
+
+	w := s.do(http.MethodGet, "/api/v1/health", "", nil)
+	expectStatus(t, w, http.StatusOK)
+	health := decode[map[string]any](t, w)
+	if health["status"] != "ok" || health["db_ok"] != true || health["scheduler_running"] != true {
+		t.Errorf("health = %v", health)
+	}
+	if _, ok := health["last_check_time"]; !ok {
+		t.Error("health has no last_check_time")
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/ready", "", nil)
+	expectStatus(t, w, http.StatusOK)
+}
+
+func TestHealthDatabaseDown(t *testing.T) {
+	s := newTestServer(t)
+	s.startScheduler("0 9 * * *")
+	database.Close()
+
+	// Liveness stays up so the server isn't restarted over a database outage
+	w := s.do(http.MethodGet, "/api/v1/health", "", nil)
+	expectStatus(t, w, http.StatusOK)
+	health := decode[map[string]any](t, w)
+	if health["status"] != "degraded" || health["db_ok"] != false || health["scheduler_running"] != true {
+		t.Errorf("health = %v", health)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/ready", "", nil)
+	expectStatus(t, w, http.StatusServiceUnavailable)
+	if ready := decode[map[string]any](t, w); ready["db_ok"] != false {
+		t.Errorf("ready = %v", ready)
+	}
+}
+
+func TestReadyWithoutScheduler(t *testing.T) {
+	s := newTestServer(t)
+
+	w := s.do(http.MethodGet, "/api/v1/ready", "", nil)
+	expectStatus(t, w, http.StatusServiceUnavailable)
+	if ready := decode[map[string]any](t, w); ready["db_ok"] != true || ready["scheduler_running"] != false {
+		t.Errorf("ready = %v", ready)
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/scheduler"
+	"domain-monitor/internal/services"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testServer is the API wired to a temporary SQLite database
+type testServer struct {
+	t       *testing.T
+	cfg     *config.Config
+	handler *Handler
+	auth    *services.AuthService
+	router  *gin.Engine
+}
+
+// newTestServer starts the API on a fresh database; configure adjusts the config first
+func newTestServer(t *testing.T, configure ...func(cfg *config.Config)) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	if err := database.InitDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// Domains created through the API are checked in the background; the WHOIS API
+	// answers without network access and cleanup waits for the checks to finish
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(whoisAPI.Close)
+
+	cfg := &config.Config{}
+	cfg.Monitor.AlertDays = []int{30, 7, 1}
+	cfg.Whois.APIURL = whoisAPI.URL
+	cfg.Whois.CacheTTL = "0"
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	whoisService := services.NewWhoisService(&cfg.Whois)
+	notifyService := services.NewNotifyService(&cfg.Notifications)
+	monitorService := services.NewMonitorService(whoisService, notifyService, &cfg.Monitor)
+	t.Cleanup(func() { monitorService.Shutdown(context.Background()) })
+	authService := services.NewAuthService("test-secret", services.DefaultRefreshGrace)
+
+	handler := NewHandler(cfg, monitorService, whoisService, authService, nil)
+	router := gin.New()
+	SetupRoutes(router, handler)
+
+	return &testServer{t: t, cfg: cfg, handler: handler, auth: authService, router: router}
+}
+
+// startScheduler starts a scheduler for the handler with the check interval
+func (s *testServer) startScheduler(interval string) *scheduler.Scheduler {
+	s.t.Helper()
+	sched := scheduler.NewScheduler(s.handler.monitorService, nil, nil)
+	if err := sched.Start(interval, ""); err != nil {
+		s.t.Fatalf("start scheduler: %v", err)
+	}
+	s.t.Cleanup(sched.Stop)
+	s.handler.scheduler = sched
+	return sched
+}
+
+// createUser stores an active user with the password "password"
+func (s *testServer) createUser(username, role string) *models.User {
+	s.t.Helper()
+	hashed, err := s.auth.HashPassword("password")
+	if err != nil {
+		s.t.Fatalf("HashPassword: %v", err)
+	}
+	user := &models.User{Username: username, Password: hashed, Role: role, IsActive: true}
+	if err := database.GetDB().Create(user).Error; err != nil {
+		s.t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// token returns a login token for the user
+func (s *testServer) token(user *models.User) string {
+	s.t.Helper()
+	token, err := s.auth.GenerateToken(user)
+	if err != nil {
+		s.t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+// adminToken creates an admin and returns its login token
+func (s *testServer) adminToken() string {
+	s.t.Helper()
+	return s.token(s.createUser("admin", models.RoleAdmin))
+}
+
+// do sends a request authenticated with the token (if set); body is encoded as JSON
+// unless it is already a string
+func (s *testServer) do(method, path, token string, body any) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case string:
+		reader = bytes.NewBufferString(b)
+	default:
+		data, err := json.Marshal(b)
+		if err != nil {
+			s.t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewBuffer(data)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+// decode unmarshals a JSON response
+func decode[T any](t *testing.T, w *httptest.ResponseRecorder) T {
+	t.Helper()
+	var v T
+	if err := json.Unmarshal(w.Body.Bytes(), &v); err != nil {
+		t.Fatalf("decode %q: %v", w.Body.String(), err)
+	}
+	return v
+}
+
+// expectStatus fails the test unless the response has the status
+func expectStatus(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// setSettings stores settings directly in the database
+func setSettings(t *testing.T, settings map[string]string) {
+	t.Helper()
+	for key, value := range settings {
+		if err := database.GetDB().Save(&models.Setting{Key: key, Value: value}).Error; err != nil {
+			t.Fatalf("save setting: %v", err)
+		}
+	}
+}
+
+// createDomain stores an active domain
+func createDomain(t *testing.T, name string) *models.Domain {
+	t.Helper()
+	domain := &models.Domain{Name: name, IsActive: true, NotifyEnabled: true}
+	if err := database.GetDB().Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+	return domain
+}
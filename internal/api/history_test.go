@@ -0,0 +1,42 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGetDomainHistory(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	other := createDomain(t, "example.org")
+
+	now := time.Now()
+	for i, id := range []uint{domain.ID, domain.ID, domain.ID, other.ID} {
+		entry := models.DomainCheckHistory{DomainID: id, CheckedAt: now.Add(time.Duration(i) * time.Hour), DaysRemaining: 100 - i, Success: true}
+		if err := database.GetDB().Create(&entry).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := fmt.Sprintf("/api/v1/domains/%d/history", domain.ID)
+
+	w := s.do(http.MethodGet, path, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	history := decode[[]models.DomainCheckHistory](t, w)
+	if len(history) != 3 || history[0].DaysRemaining != 98 || history[2].DaysRemaining != 100 {
+		t.Errorf("history = %+v, want the domain's 3 entries newest first", history)
+	}
+
+	w = s.do(http.MethodGet, path+"?limit=1", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if history := decode[[]models.DomainCheckHistory](t, w); len(history) != 1 || history[0].DaysRemaining != 98 {
+		t.Errorf("limited history = %+v, want the newest entry", history)
+	}
+
+	expectStatus(t, s.do(http.MethodGet, path+"?limit=0", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/domains/999/history", token, nil), http.StatusNotFound)
+}
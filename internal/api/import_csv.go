@@ -0,0 +1,176 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCSVImportSize limits the size of uploaded CSV files
+const maxCSVImportSize = 5 << 20
+
+// csvRowResult is the import result of one CSV row
+type csvRowResult struct {
+	Line   int    `json:"line"`
+	Name   string `json:"name,omitempty"`
+	Status string `json:"status"` // imported/duplicate/unsupported_tld/failed
+	Error  string `json:"error,omitempty"`
+}
+
+// ImportDomainsCSV imports domains from an uploaded CSV file with name,tags,notes columns.
+// A header row is optional; without one the columns are read in that order.
+func (h *Handler) ImportDomainsCSV(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A CSV file is required in the \"file\" form field"})
+		return
+	}
+	if fileHeader.Size > maxCSVImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "CSV file is too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	columns := map[string]int{"name": 0, "tags": 1, "notes": 2}
+	results := make([]csvRowResult, 0)
+	seen := make(map[string]bool)
+	imported := 0
+
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			results = append(results, csvRowResult{Line: parseErr.StartLine, Status: "failed", Error: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		// Excel prefixes UTF-8 exports with a byte order mark
+		if first && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff")
+			if header := csvHeader(record); header != nil {
+				columns = header
+				continue
+			}
+		}
+
+		result := csvRowResult{Line: line, Name: strings.TrimSpace(csvField(record, columns, "name"))}
+		name, err := services.NormalizeDomain(result.Name)
+		switch {
+		case result.Name == "":
+			result.Status, result.Error = "failed", "missing domain name"
+		case err != nil:
+			result.Status, result.Error = "failed", err.Error()
+		case seen[name]:
+			result.Status, result.Error = "duplicate", "duplicate of an earlier row"
+		default:
+			result.Name = name
+			seen[name] = true
+			result.Status, result.Error = h.importCSVDomain(result.Name, csvField(record, columns, "tags"), csvField(record, columns, "notes"))
+			if result.Status == "imported" {
+				imported++
+			}
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported": imported,
+		"results":  results,
+	})
+}
+
+// importCSVDomain creates one domain from a CSV row and returns the row status and error
+func (h *Handler) importCSVDomain(name, tags, notes string) (string, string) {
+	if mode := h.tldValidation(); mode != "" {
+		if err := h.whoisService.CheckTLD(name); err != nil && mode == "block" {
+			return "unsupported_tld", err.Error()
+		}
+	}
+
+	domain := models.Domain{
+		Name:          name,
+		Tags:          csvTags(tags),
+		Notes:         strings.TrimSpace(notes),
+		IsActive:      true,
+		NotifyEnabled: true,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&domain).Error
+	}); database.IsDuplicateKey(err) {
+		return "duplicate", "domain already exists"
+	} else if err != nil {
+		return "failed", err.Error()
+	}
+
+	go h.monitorService.CheckDomain(&domain)
+	return "imported", ""
+}
+
+// csvHeader returns the column positions if the record is a header row, nil otherwise
+func csvHeader(record []string) map[string]int {
+	columns := make(map[string]int)
+	for i, field := range record {
+		switch key := strings.ToLower(strings.TrimSpace(field)); key {
+		case "name", "domain":
+			columns["name"] = i
+		case "tags", "notes":
+			columns[key] = i
+		}
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil
+	}
+	return columns
+}
+
+// csvField returns a column of the record, empty if the column is missing
+func csvField(record []string, columns map[string]int, column string) string {
+	i, ok := columns[column]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return record[i]
+}
+
+// csvTags normalizes a comma or semicolon separated tag list
+func csvTags(value string) string {
+	tags := make([]string, 0)
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return strings.Join(tags, ",")
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+// uploadCSV posts the content as the "file" form field of the CSV import
+func (s *testServer) uploadCSV(token, content string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "domains.csv")
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/domains/import/csv", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestImportDomainsCSV(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createDomain(t, "existing.com")
+
+	csv := "\ufeffNotes,Domain,Tags\n" +
+		"Main site,Example.COM,\"prod; web\"\n" +
+		",example.org,\n" +
+		",example.com,\n" +
+		",existing.com,\n" +
+		",bad\"quote.com,\n" +
+		",not a domain,\n"
+	w := s.uploadCSV(token, csv)
+	expectStatus(t, w, http.StatusOK)
+
+	got := decode[struct {
+		Imported int            `json:"imported"`
+		Results  []csvRowResult `json:"results"`
+	}](t, w)
+	if got.Imported != 2 {
+		t.Errorf("imported = %d, want 2", got.Imported)
+	}
+	want := []csvRowResult{
+		{Line: 2, Name: "example.com", Status: "imported"},
+		{Line: 3, Name: "example.org", Status: "imported"},
+		{Line: 4, Name: "example.com", Status: "duplicate"},
+		{Line: 5, Name: "existing.com", Status: "duplicate"},
+		{Line: 6, Status: "failed"},
+		{Line: 7, Name: "not a domain", Status: "failed"},
+	}
+	if len(got.Results) != len(want) {
+		t.Fatalf("results = %+v, want %d rows", got.Results, len(want))
+	}
+	for i, result := range got.Results {
+		if result.Line != want[i].Line || result.Name != want[i].Name || result.Status != want[i].Status {
+			t.Errorf("row %d = %+v, want %+v", i, result, want[i])
+		}
+		if result.Status != "imported" && result.Error == "" {
+			t.Errorf("row %d has no error message", i)
+		}
+	}
+
+	var domain models.Domain
+	if err := database.GetDB().Where("name = ?", "example.com").First(&domain).Error; err != nil {
+		t.Fatal(err)
+	}
+	if domain.Notes != "Main site" || !slices.Equal(domain.TagList(), []string{"prod", "web"}) {
+		t.Errorf("notes = %q, tags = %v", domain.Notes, domain.TagList())
+	}
+}
+
+func TestImportDomainsCSVWithoutHeader(t *testing.T) {
+	s := newTestServer(t)
+	w := s.uploadCSV(s.adminToken(), "example.com,prod,Main site\n")
+	expectStatus(t, w, http.StatusOK)
+	if imported := decode[map[string]any](t, w)["imported"]; imported != float64(1) {
+		t.Errorf("imported = %v, want 1", imported)
+	}
+}
+
+func TestImportDomainsCSVRequiresFile(t *testing.T) {
+	s := newTestServer(t)
+	w := s.do(http.MethodPost, "/api/v1/domains/import/csv", s.adminToken(), nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"bytes"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// login posts the credentials to the login endpoint
+func (s *testServer) login(username, password string) int {
+	s.t.Helper()
+	return s.do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": username, "password": password}).Code
+}
+
+// loginFrom posts the credentials to the login endpoint from the client IP
+func (s *testServer) loginFrom(username, password, ip string) int {
+	s.t.Helper()
+	body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestLoginLockout(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Security.LoginMaxAttempts = 3
+		cfg.Security.LoginLockout = "10m"
+	})
+	s.createUser("alice", models.RoleViewer)
+
+	for i := 0; i < 3; i++ {
+		if code := s.login("alice", "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: status = %d, want 401", i+1, code)
+		}
+	}
+
+	// Locked out, even with the right password
+	w := s.do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": "alice", "password": "password"})
+	expectStatus(t, w, http.StatusTooManyRequests)
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil || retryAfter <= 0 || retryAfter > 600 {
+		t.Errorf("Retry-After = %q, want seconds up to the 10m lockout", w.Header().Get("Retry-After"))
+	}
+}
+
+func TestLoginSuccessClearsOnlyUserFailures(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Security.LoginMaxAttempts = 3 })
+	s.createUser("alice", models.RoleViewer)
+	s.createUser("bob", models.RoleViewer)
+
+	// Two failures for alice from one IP, then she logs in from there
+	for i := 0; i < 2; i++ {
+		s.loginFrom("alice", "wrong", "192.0.2.1")
+	}
+	if code := s.loginFrom("alice", "password", "192.0.2.1"); code != http.StatusOK {
+		t.Fatalf("login after 2 failures: status = %d, want 200", code)
+	}
+
+	// Her username count was cleared, so two more failures from elsewhere don't lock her out
+	for i := 0; i < 2; i++ {
+		s.loginFrom("alice", "wrong", "192.0.2.2")
+	}
+	if code := s.loginFrom("alice", "password", "192.0.2.3"); code != http.StatusOK {
+		t.Errorf("alice's login: status = %d, want 200 after her count was cleared", code)
+	}
+
+	// The first IP keeps its two failures, so one more locks it out for other usernames too
+	s.loginFrom("bob", "wrong", "192.0.2.1")
+	if code := s.loginFrom("bob", "password", "192.0.2.1"); code != http.StatusTooManyRequests {
+		t.Errorf("bob's login from the locked IP: status = %d, want 429", code)
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"crypto/subtle"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/metrics"
+	"domain-monitor/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// expiringWindows are the day windows of the jiankong_domains_expiring gauge
+var expiringWindows = []int{7, 30}
+
+// Metrics serves Prometheus metrics: service counters plus domain gauges read from the database
+func (h *Handler) Metrics(c *gin.Context) {
+	if token := h.cfg.Metrics.Token; token != "" {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid metrics token"})
+			return
+		}
+	}
+
+	db := database.GetDB()
+	if db == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": database.ErrUnavailable.Error()})
+		return
+	}
+
+	var total int64
+	if err := db.Model(&models.Domain{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var domains []models.Domain
+	if err := db.Select("name", "days_remaining", "expiry_date").Where("is_active = ?", true).Order("name").Find(&domains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	refreshDaysRemaining(domains)
+
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := c.Writer
+
+	metrics.WriteGauge(w, "jiankong_domains_total", "Monitored domains.", float64(total))
+	metrics.WriteGauge(w, "jiankong_domains_active", "Domains with monitoring enabled.", float64(len(domains)))
+
+	metrics.WriteGaugeHeader(w, "jiankong_domains_expiring", "Active domains expiring within the window (including expired ones).")
+	for _, days := range expiringWindows {
+		count := 0
+		for _, domain := range domains {
+			if domain.ExpiryDate != nil && domain.DaysRemaining <= days {
+				count++
+			}
+		}
+		window := strconv.Itoa(days) + "d"
+		metrics.WriteSample(w, "jiankong_domains_expiring", metrics.Labels([]string{"window"}, []string{window}), float64(count))
+	}
+
+	// One series per domain, so only when enabled
+	if h.cfg.Metrics.PerDomain {
+		metrics.WriteGaugeHeader(w, "jiankong_domain_days_remaining", "Days until the domain expires.")
+		for _, domain := range domains {
+			if domain.ExpiryDate == nil {
+				continue
+			}
+			metrics.WriteSample(w, "jiankong_domain_days_remaining", metrics.Labels([]string{"domain"}, []string{domain.Name}), float64(domain.DaysRemaining))
+		}
+	}
+
+	metrics.WriteCounters(w)
+}
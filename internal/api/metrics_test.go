@@ -0,0 +1,108 @@
+package api
+
+import (
+	"bufio"
+	"domain-monitor/internal/config"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scrape fetches /metrics and returns the samples by series, e.g. `name{label="value"}`
+func (s *testServer) scrape(token string) map[string]float64 {
+	s.t.Helper()
+	w := s.do(http.MethodGet, "/metrics", token, nil)
+	expectStatus(s.t, w, http.StatusOK)
+
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndex(line, " ")
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			s.t.Fatalf("invalid sample %q: %v", line, err)
+		}
+		samples[line[:i]] = value
+	}
+	return samples
+}
+
+func TestMetricsAfterCheck(t *testing.T) {
+	expiry := time.Now().AddDate(0, 0, 20).UTC().Format(time.RFC3339)
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code":0,"data":{"registrar":"Example Registrar","expirationDate":%q}}`, expiry)
+	}))
+	defer whoisAPI.Close()
+
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Whois.APIURL = whoisAPI.URL
+		cfg.Metrics.Enabled = true
+		cfg.Metrics.PerDomain = true
+	})
+	domain := createDomain(t, "example.com")
+	createDomain(t, "example.org") // Never checked
+
+	// Counters are process-wide, so the check is measured against the previous scrape
+	before := s.scrape("")
+	if err := s.handler.monitorService.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	after := s.scrape("")
+
+	want := map[string]float64{
+		`jiankong_domains_total`:                               2,
+		`jiankong_domains_active`:                              2,
+		`jiankong_domains_expiring{window="7d"}`:               0,
+		`jiankong_domains_expiring{window="30d"}`:              1,
+		`jiankong_domain_days_remaining{domain="example.com"}`: 20,
+	}
+	for series, value := range want {
+		if got, ok := after[series]; !ok || got != value {
+			t.Errorf("%s = %v (present %v), want %v", series, got, ok, value)
+		}
+	}
+	if _, ok := after[`jiankong_domain_days_remaining{domain="example.org"}`]; ok {
+		t.Error("days remaining exported for a domain without an expiry date")
+	}
+
+	for _, series := range []string{`jiankong_whois_queries_total{result="success"}`, `jiankong_domain_checks_total{result="success"}`} {
+		if got := after[series] - before[series]; got != 1 {
+			t.Errorf("%s increased by %v, want 1", series, got)
+		}
+	}
+}
+
+func TestMetricsPerDomainOptIn(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Metrics.Enabled = true })
+	createDomain(t, "example.com")
+
+	for series := range s.scrape("") {
+		if strings.HasPrefix(series, "jiankong_domain_days_remaining") {
+			t.Errorf("per-domain series %s exported without per_domain", series)
+		}
+	}
+}
+
+func TestMetricsToken(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Metrics.Enabled = true
+		cfg.Metrics.Token = "scrape-token"
+	})
+
+	expectStatus(t, s.do(http.MethodGet, "/metrics", "", nil), http.StatusUnauthorized)
+	expectStatus(t, s.do(http.MethodGet, "/metrics", "wrong", nil), http.StatusUnauthorized)
+	s.scrape("scrape-token")
+}
+
+func TestMetricsDisabled(t *testing.T) {
+	s := newTestServer(t)
+	expectStatus(t, s.do(http.MethodGet, "/metrics", "", nil), http.StatusNotFound)
+}
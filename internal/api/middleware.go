@@ -0,0 +1,133 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// claimsKey is the gin.Context key holding the authenticated user's claims
+const claimsKey = "claims"
+
+// APIKeyHeader carries an API key, accepted instead of a Bearer token
+const APIKeyHeader = "X-API-Key"
+
+// AuthMiddleware requires a valid "Authorization: Bearer <token>" header or an active
+// API key in the X-API-Key header. A login token is only accepted while its user still
+// exists and is active.
+func AuthMiddleware(authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+			claims, err := authService.AuthenticateAPIKey(key)
+			if errors.Is(err, services.ErrInvalidAPIKey) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "无效的 API Key"})
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.Set(claimsKey, claims)
+			c.Next()
+			return
+		}
+
+		header := c.GetHeader("Authorization")
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || strings.TrimSpace(token) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "未登录或缺少 token"})
+			return
+		}
+
+		claims, err := authService.ValidateToken(strings.TrimSpace(token))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "无效的 token"})
+			return
+		}
+		if !reloadUser(c, claims) {
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Next()
+	}
+}
+
+// QueryTokenAuth is AuthMiddleware that also accepts the credentials in the token query
+// parameter (an API key or a login token), for clients such as calendar apps and feed
+// readers that subscribe to a URL and can't send headers
+func QueryTokenAuth(authService *services.AuthService) gin.HandlerFunc {
+	auth := AuthMiddleware(authService)
+	return func(c *gin.Context) {
+		if token := strings.TrimSpace(c.Query("token")); token != "" {
+			if strings.HasPrefix(token, services.APIKeyPrefix) {
+				c.Request.Header.Set(APIKeyHeader, token)
+			} else {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		auth(c)
+	}
+}
+
+// CurrentClaims returns the claims of the authenticated user, if any
+func CurrentClaims(c *gin.Context) (*services.Claims, bool) {
+	value, exists := c.Get(claimsKey)
+	if !exists {
+		return nil, false
+	}
+	claims, ok := value.(*services.Claims)
+	return claims, ok
+}
+
+// RequireRole allows only users with one of the given roles; use after AuthMiddleware,
+// which has already replaced a login token's role with the user's current one.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := CurrentClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "未登录或缺少 token"})
+			return
+		}
+		for _, role := range roles {
+			if claims.UserRole() == role {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "没有权限执行此操作"})
+	}
+}
+
+// reloadUser checks that the user of a login token still exists and is active, and
+// updates the claims with the user's current role, as it may have changed since the
+// token was issued. API keys are looked up on every request and need no reload. On
+// failure the error response is written and ok is false.
+func reloadUser(c *gin.Context, claims *services.Claims) bool {
+	if claims.UserID == 0 {
+		return true
+	}
+
+	var user models.User
+	if err := database.GetDB().Select("id", "role", "is_active").First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "用户不存在"})
+			return false
+		}
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if !user.IsActive {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "账户已被禁用"})
+		return false
+	}
+
+	claims.Role = user.Role
+	return true
+}
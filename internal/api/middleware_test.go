@@ -0,0 +1,101 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProtectedRouteRequiresToken(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic YWRtaW46cGFzc3dvcmQ=", http.StatusUnauthorized},
+		{"invalid token", "Bearer not-a-token", http.StatusUnauthorized},
+		{"valid token", "Bearer " + token, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/domains", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			expectStatus(t, w, tt.want)
+		})
+	}
+}
+
+func TestAuthMiddlewareStoresClaims(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser("viewer", models.RoleViewer)
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(s.auth), func(c *gin.Context) {
+		claims, ok := CurrentClaims(c)
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"username": claims.Username, "role": claims.Role})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+s.token(user))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]string](t, w); got["username"] != "viewer" || got["role"] != models.RoleViewer {
+		t.Errorf("claims = %v, want the viewer's", got)
+	}
+}
+
+func TestAuthMiddlewareRejectsRemovedUser(t *testing.T) {
+	s := newTestServer(t)
+	user := s.createUser("alice", models.RoleViewer)
+	token := s.token(user)
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(s.auth), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	expectStatus(t, get(), http.StatusOK)
+
+	// The token is still valid, but the user may no longer sign in
+	database.GetDB().Model(user).Update("is_active", false)
+	expectStatus(t, get(), http.StatusUnauthorized)
+
+	database.GetDB().Delete(user)
+	expectStatus(t, get(), http.StatusUnauthorized)
+}
+
+func TestLoginIsPublic(t *testing.T) {
+	s := newTestServer(t)
+	s.createUser("admin", models.RoleAdmin)
+
+	w := s.do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": "admin", "password": "password"})
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]any](t, w); got["token"] == nil || got["token"] == "" {
+		t.Errorf("login response has no token: %v", got)
+	}
+}
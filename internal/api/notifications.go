@@ -0,0 +1,134 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default and maximum page size of the notification history
+const (
+	defaultNotificationPageSize = 50
+	maxNotificationPageSize     = 500
+)
+
+// ListNotifications retrieves notification history, newest first. It can be filtered by
+// domain_id, type (channel), status and a from/to date range, and is paginated with
+// page and page_size.
+func (h *Handler) ListNotifications(c *gin.Context) {
+	db := database.GetDB()
+	query := db.Model(&models.Notification{})
+
+	if value := c.Query("domain_id"); value != "" {
+		domainID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+			return
+		}
+		query = query.Where("domain_id = ?", domainID)
+	}
+
+	if value := c.Query("type"); value != "" {
+		query = query.Where("type = ?", value)
+	}
+
+	switch status := c.Query("status"); status {
+	case "":
+	case "success", "failed":
+		query = query.Where("status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be success or failed"})
+		return
+	}
+
+	if value := c.Query("event"); value != "" {
+		query = query.Where("event = ?", value)
+	}
+
+	if value := c.Query("from"); value != "" {
+		from, _, err := parseDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD) or RFC 3339 time"})
+			return
+		}
+		query = query.Where("sent_at >= ?", from)
+	}
+
+	if value := c.Query("to"); value != "" {
+		to, dateOnly, err := parseDateParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD) or RFC 3339 time"})
+			return
+		}
+		// A date includes the whole day
+		if dateOnly {
+			query = query.Where("sent_at < ?", to.AddDate(0, 0, 1))
+		} else {
+			query = query.Where("sent_at <= ?", to)
+		}
+	}
+
+	page, pageSize, ok := queryPage(c, defaultNotificationPageSize, maxNotificationPageSize)
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	notifications := []models.Notification{}
+	if err := query.Order("sent_at desc").Order("id desc").
+		Offset((page - 1) * pageSize).Limit(pageSize).
+		Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"total":         total,
+		"page":          page,
+		"page_size":     pageSize,
+	})
+}
+
+// DeleteNotifications deletes the notifications sent before the required before parameter
+// (a date, meaning the start of that day, or an RFC 3339 time) and returns how many were deleted
+func (h *Handler) DeleteNotifications(c *gin.Context) {
+	value := c.Query("before")
+	if value == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "before is required"})
+		return
+	}
+	before, _, err := parseDateParam(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "before must be a date (YYYY-MM-DD) or RFC 3339 time"})
+		return
+	}
+
+	deleted, err := services.DeleteNotificationsBefore(before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// parseDateParam parses a query parameter given as a local date (YYYY-MM-DD) or an
+// RFC 3339 time, reporting whether it was a date
+func parseDateParam(value string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation(models.DateLayout, value, models.Location()); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
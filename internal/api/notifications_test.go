@@ -0,0 +1,124 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// notificationPage is the response of GET /notifications
+type notificationPage struct {
+	Notifications []models.Notification `json:"notifications"`
+	Total         int64                 `json:"total"`
+	Page          int                   `json:"page"`
+	PageSize      int                   `json:"page_size"`
+}
+
+// createNotification stores a notification sent ago before now
+func createNotification(t *testing.T, domainID uint, channel, status string, ago time.Duration) {
+	t.Helper()
+	n := models.Notification{DomainID: domainID, Type: channel, Status: status, Event: "expiry", SentAt: time.Now().Add(-ago)}
+	if err := database.GetDB().Create(&n).Error; err != nil {
+		t.Fatalf("create notification: %v", err)
+	}
+}
+
+func TestListNotificationsFilter(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createNotification(t, 1, "email", "success", 3*time.Hour)
+	createNotification(t, 1, "webhook", "failed", 2*time.Hour)
+	createNotification(t, 2, "email", "failed", time.Hour)
+	createNotification(t, 2, "email", "success", 10*24*time.Hour)
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{"", 4},
+		{"?status=failed", 2},
+		{"?status=success", 2},
+		{"?domain_id=1", 2},
+		{"?domain_id=2&status=failed", 1},
+		{"?type=email&status=failed", 1},
+		{"?from=" + time.Now().UTC().Add(-24*time.Hour).Format(time.RFC3339), 3},
+	}
+	for _, tt := range tests {
+		w := s.do(http.MethodGet, "/api/v1/notifications"+tt.query, token, nil)
+		expectStatus(t, w, http.StatusOK)
+		page := decode[notificationPage](t, w)
+		if page.Total != tt.want || int64(len(page.Notifications)) != tt.want {
+			t.Errorf("GET /notifications%s: total %d, %d listed, want %d", tt.query, page.Total, len(page.Notifications), tt.want)
+		}
+	}
+
+	// Filtered by status, only matching notifications are listed
+	w := s.do(http.MethodGet, "/api/v1/notifications?status=failed", token, nil)
+	for _, n := range decode[notificationPage](t, w).Notifications {
+		if n.Status != "failed" {
+			t.Errorf("notification %d has status %s, want failed", n.ID, n.Status)
+		}
+	}
+}
+
+func TestListNotificationsPagination(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	for i := 0; i < 5; i++ {
+		createNotification(t, 1, "email", "success", time.Duration(i)*time.Hour)
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/notifications?page=2&page_size=2", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	page := decode[notificationPage](t, w)
+	if page.Total != 5 || page.Page != 2 || page.PageSize != 2 || len(page.Notifications) != 2 {
+		t.Fatalf("page = total %d, page %d, size %d, %d listed", page.Total, page.Page, page.PageSize, len(page.Notifications))
+	}
+	// Newest first: the second page holds the third and fourth newest
+	if !page.Notifications[0].SentAt.After(page.Notifications[1].SentAt) {
+		t.Error("notifications not ordered newest first")
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/notifications", token, nil)
+	if got := decode[notificationPage](t, w).PageSize; got != defaultNotificationPageSize {
+		t.Errorf("default page size = %d, want %d", got, defaultNotificationPageSize)
+	}
+}
+
+func TestListNotificationsInvalidFilter(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	for _, query := range []string{"?status=pending", "?domain_id=abc", "?from=yesterday", "?to=2026-13-01"} {
+		w := s.do(http.MethodGet, "/api/v1/notifications"+query, token, nil)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("GET /notifications%s: status = %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestDeleteNotifications(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createNotification(t, 1, "email", "success", 40*24*time.Hour)
+	createNotification(t, 1, "email", "success", 35*24*time.Hour)
+	createNotification(t, 1, "email", "success", time.Hour)
+
+	before := time.Now().AddDate(0, 0, -30).In(models.Location()).Format(models.DateLayout)
+	w := s.do(http.MethodDelete, "/api/v1/notifications?before="+before, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[map[string]int64](t, w)["deleted"]; got != 2 {
+		t.Errorf("deleted = %d, want 2", got)
+	}
+
+	var remaining int64
+	database.GetDB().Model(&models.Notification{}).Count(&remaining)
+	if remaining != 1 {
+		t.Errorf("%d notifications left, want the recent one", remaining)
+	}
+
+	expectStatus(t, s.do(http.MethodDelete, "/api/v1/notifications", token, nil), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodDelete, "/api/v1/notifications?before=soon", token, nil), http.StatusBadRequest)
+}
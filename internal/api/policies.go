@@ -0,0 +1,162 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListPolicies returns all alert policies
+func (h *Handler) ListPolicies(c *gin.Context) {
+	db := database.GetDB()
+
+	var policies []models.AlertPolicy
+	if err := db.Order("name asc").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// CreatePolicy adds a new alert policy
+func (h *Handler) CreatePolicy(c *gin.Context) {
+	var policy models.AlertPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePolicy(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy.ID = 0
+	policy.CreatedAt = time.Now()
+	policy.UpdatedAt = time.Now()
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&policy).Error; err != nil {
+				return err
+			}
+			return setDefaultPolicy(tx, &policy)
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, policy)
+}
+
+// UpdatePolicy updates an alert policy; the change applies to all domains using it
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy ID"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var policy models.AlertPolicy
+	if err := db.First(&policy, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Policy not found"})
+		return
+	}
+
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := validatePolicy(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy.ID = uint(id)
+	policy.UpdatedAt = time.Now()
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&policy).Error; err != nil {
+				return err
+			}
+			return setDefaultPolicy(tx, &policy)
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeletePolicy removes an alert policy; its domains fall back to the default policy
+func (h *Handler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy ID"})
+		return
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&models.Domain{}).Where("alert_policy_id = ?", id).Update("alert_policy_id", 0).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&models.AlertPolicy{}, id).Error
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Policy deleted successfully"})
+}
+
+// setDefaultPolicy makes sure at most one policy is the default
+func setDefaultPolicy(tx *gorm.DB, policy *models.AlertPolicy) error {
+	if !policy.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.AlertPolicy{}).Where("id <> ? AND is_default = ?", policy.ID, true).Update("is_default", false).Error
+}
+
+// validatePolicy checks the policy thresholds, channels and template
+func validatePolicy(policy *models.AlertPolicy) error {
+	if policy.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	for _, d := range strings.Split(policy.AlertDays, ",") {
+		if _, err := strconv.Atoi(strings.TrimSpace(d)); err != nil {
+			return fmt.Errorf("alert_days must be comma separated numbers, got %q", policy.AlertDays)
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, channel := range services.SupportedChannels() {
+		known[channel.Type] = true
+	}
+	for _, channel := range strings.Split(policy.Channels, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" && !known[channel] {
+			return fmt.Errorf("unknown channel %q", channel)
+		}
+	}
+
+	if _, err := template.New("policy").Parse(policy.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
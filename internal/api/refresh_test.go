@@ -0,0 +1,82 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshAllDomains(t *testing.T) {
+	// The WHOIS API holds every query until released
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	unblock := func() { releaseOnce.Do(func() { close(release) }) }
+	var queries atomic.Int32
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+		<-release
+		w.Write([]byte(`{"code":0,"data":{"registrar":"Example Registrar","expirationDate":"2030-01-02T00:00:00Z"}}`))
+	}))
+	defer whoisAPI.Close()
+	defer unblock()
+
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Whois.APIURL = whoisAPI.URL
+	})
+	token := s.adminToken()
+	for _, name := range []string{"example.com", "example.org", "example.net"} {
+		createDomain(t, name)
+	}
+
+	start := time.Now()
+	w := s.do(http.MethodPost, "/api/v1/domains/refresh-all", token, nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("refresh-all took %v, want it to return before the checks finish", elapsed)
+	}
+	expectStatus(t, w, http.StatusAccepted)
+	got := decode[struct {
+		Queued int                 `json:"queued"`
+		Job    services.RefreshJob `json:"job"`
+	}](t, w)
+	if got.Queued != 3 || got.Job.Status != services.JobRunning || got.Job.Total != 3 {
+		t.Fatalf("response = %+v, want a running job for 3 domains", got)
+	}
+
+	// Only one refresh runs at a time
+	w = s.do(http.MethodPost, "/api/v1/domains/refresh-all", token, nil)
+	expectStatus(t, w, http.StatusConflict)
+
+	waitFor(t, func() bool { return queries.Load() > 0 })
+	unblock()
+
+	var job services.RefreshJob
+	waitFor(t, func() bool {
+		w := s.do(http.MethodGet, "/api/v1/domains/refresh-all/"+got.Job.ID, token, nil)
+		expectStatus(t, w, http.StatusOK)
+		job = decode[services.RefreshJob](t, w)
+		return job.Status == services.JobFinished
+	})
+	if job.Checked != 3 || job.Failed != 0 || job.FinishedAt == nil {
+		t.Errorf("finished job = %+v, want 3 checked without failures", job)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/domains/refresh-all/999", token, nil)
+	expectStatus(t, w, http.StatusNotFound)
+}
+
+// waitFor polls the condition until it holds or the test times out after 5 seconds
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
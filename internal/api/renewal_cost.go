@@ -0,0 +1,100 @@
+package api
+
+import (
+	"domain-monitor/internal/models"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Default and maximum window of the renewal cost report in days
+const (
+	defaultRenewalCostDays = 365
+	maxRenewalCostDays     = 3650
+)
+
+// renewalCostTotal is the cost of the renewals of one currency, in one month or overall
+type renewalCostTotal struct {
+	Month    string  `json:"month,omitempty"` // YYYY-MM, empty for the overall totals
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+	Renewals int     `json:"renewals"` // Number of renewals summed up
+}
+
+// GetRenewalCost sums the renewal costs due within ?days= days (default 365) per month and
+// currency. Domains renew yearly from their expiry date, so a window longer than a year
+// counts a domain once per renewal. The list filters apply; retiring domains, domains
+// without a cost and expired domains are left out.
+func (h *Handler) GetRenewalCost(c *gin.Context) {
+	days, err := queryInt(c, "days")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if days == 0 {
+		days = defaultRenewalCostDays
+	}
+	days = min(days, maxRenewalCostDays)
+
+	domains, ok := h.queryDomains(c)
+	if !ok {
+		return
+	}
+
+	months, totals := renewalCosts(domains, time.Now(), days)
+	c.JSON(http.StatusOK, gin.H{
+		"days":   days,
+		"months": months,
+		"totals": totals,
+	})
+}
+
+// renewalCosts sums the renewals due from today until days from now, per month and
+// currency and per currency. Both lists are sorted by month and currency.
+func renewalCosts(domains []models.Domain, now time.Time, days int) ([]renewalCostTotal, []renewalCostTotal) {
+	byMonth := make(map[[2]string]*renewalCostTotal)
+	byCurrency := make(map[string]*renewalCostTotal)
+
+	for _, domain := range domains {
+		if domain.Retiring || domain.RenewalCost <= 0 || domain.ExpiryDate == nil || models.DaysUntil(*domain.ExpiryDate, now) < 0 {
+			continue
+		}
+		for renewal := *domain.ExpiryDate; models.DaysUntil(renewal, now) <= days; renewal = renewal.AddDate(1, 0, 0) {
+			month := models.InLocation(renewal).Format("2006-01")
+
+			key := [2]string{month, domain.Currency}
+			if byMonth[key] == nil {
+				byMonth[key] = &renewalCostTotal{Month: month, Currency: domain.Currency}
+			}
+			byMonth[key].Total += domain.RenewalCost
+			byMonth[key].Renewals++
+
+			if byCurrency[domain.Currency] == nil {
+				byCurrency[domain.Currency] = &renewalCostTotal{Currency: domain.Currency}
+			}
+			byCurrency[domain.Currency].Total += domain.RenewalCost
+			byCurrency[domain.Currency].Renewals++
+		}
+	}
+
+	return sortedTotals(byMonth), sortedTotals(byCurrency)
+}
+
+// sortedTotals returns the totals sorted by month and currency, rounded to cents
+func sortedTotals[K comparable](totals map[K]*renewalCostTotal) []renewalCostTotal {
+	list := make([]renewalCostTotal, 0, len(totals))
+	for _, total := range totals {
+		total.Total = math.Round(total.Total*100) / 100
+		list = append(list, *total)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Month != list[j].Month {
+			return list[i].Month < list[j].Month
+		}
+		return list[i].Currency < list[j].Currency
+	})
+	return list
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// costDomain returns an active domain expiring at expiry that renews for cost in currency
+func costDomain(name string, expiry time.Time, cost float64, currency string) models.Domain {
+	return models.Domain{Name: name, IsActive: true, ExpiryDate: &expiry, RenewalCost: cost, Currency: currency}
+}
+
+func TestRenewalCostsMixedCurrencies(t *testing.T) {
+	models.SetLocation(time.UTC)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	retiring := costDomain("retiring.com", now.Add(5*day), 99, "USD")
+	retiring.Retiring = true
+
+	domains := []models.Domain{
+		costDomain("a.com", now.Add(5*day), 10.10, "USD"),
+		costDomain("b.com", now.Add(10*day), 20.20, "USD"),
+		costDomain("c.com", now.Add(15*day), 50, "EUR"),
+		costDomain("d.cn", now.Add(40*day), 69, "CNY"),
+		costDomain("e.com", now.Add(45*day), 5.05, "USD"),
+		costDomain("later.com", now.Add(200*day), 1000, "USD"),
+		costDomain("expired.com", now.Add(-2*day), 1000, "USD"),
+		costDomain("free.com", now.Add(5*day), 0, ""),
+		{Name: "unknown.com", IsActive: true, RenewalCost: 1000, Currency: "USD"},
+		retiring,
+	}
+
+	months, totals := renewalCosts(domains, now, 60)
+	wantMonths := []renewalCostTotal{
+		{Month: "2026-03", Currency: "EUR", Total: 50, Renewals: 1},
+		{Month: "2026-03", Currency: "USD", Total: 30.3, Renewals: 2},
+		{Month: "2026-04", Currency: "CNY", Total: 69, Renewals: 1},
+		{Month: "2026-04", Currency: "USD", Total: 5.05, Renewals: 1},
+	}
+	if !reflect.DeepEqual(months, wantMonths) {
+		t.Errorf("months = %+v, want %+v", months, wantMonths)
+	}
+	wantTotals := []renewalCostTotal{
+		{Currency: "CNY", Total: 69, Renewals: 1},
+		{Currency: "EUR", Total: 50, Renewals: 1},
+		{Currency: "USD", Total: 35.35, Renewals: 3},
+	}
+	if !reflect.DeepEqual(totals, wantTotals) {
+		t.Errorf("totals = %+v, want %+v", totals, wantTotals)
+	}
+}
+
+func TestRenewalCostsCountsEachRenewal(t *testing.T) {
+	models.SetLocation(time.UTC)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	domains := []models.Domain{
+		costDomain("a.com", now.AddDate(0, 1, 0), 12, "USD"),
+		costDomain("b.de", now.AddDate(0, 2, 0), 8, "EUR"),
+	}
+
+	months, totals := renewalCosts(domains, now, 3*365)
+	if len(months) != 6 {
+		t.Errorf("got %d month totals, want 6: %+v", len(months), months)
+	}
+	wantTotals := []renewalCostTotal{
+		{Currency: "EUR", Total: 24, Renewals: 3},
+		{Currency: "USD", Total: 36, Renewals: 3},
+	}
+	if !reflect.DeepEqual(totals, wantTotals) {
+		t.Errorf("totals = %+v, want %+v", totals, wantTotals)
+	}
+}
+
+func TestGetRenewalCost(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	models.SetLocation(time.UTC)
+	now := time.Now()
+	for _, domain := range []models.Domain{
+		costDomain("soon.com", now.Add(48*time.Hour), 10, "USD"),
+		costDomain("soon.de", now.Add(48*time.Hour), 7.5, "EUR"),
+		costDomain("also.de", now.Add(72*time.Hour), 2.5, "EUR"),
+		costDomain("later.com", now.Add(100*24*time.Hour), 15, "USD"),
+	} {
+		if err := database.GetDB().Create(&domain).Error; err != nil {
+			t.Fatalf("create domain: %v", err)
+		}
+	}
+
+	type report struct {
+		Days   int                `json:"days"`
+		Months []renewalCostTotal `json:"months"`
+		Totals []renewalCostTotal `json:"totals"`
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/dashboard/renewal-cost?days=30", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	got := decode[report](t, w)
+	if got.Days != 30 {
+		t.Errorf("days = %d, want 30", got.Days)
+	}
+	want := []renewalCostTotal{
+		{Currency: "EUR", Total: 10, Renewals: 2},
+		{Currency: "USD", Total: 10, Renewals: 1},
+	}
+	if !reflect.DeepEqual(got.Totals, want) {
+		t.Errorf("totals = %+v, want %+v", got.Totals, want)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/dashboard/renewal-cost", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	got = decode[report](t, w)
+	if got.Days != defaultRenewalCostDays {
+		t.Errorf("default days = %d, want %d", got.Days, defaultRenewalCostDays)
+	}
+	want = []renewalCostTotal{
+		{Currency: "EUR", Total: 10, Renewals: 2},
+		{Currency: "USD", Total: 25, Renewals: 2},
+	}
+	if !reflect.DeepEqual(got.Totals, want) {
+		t.Errorf("totals = %+v, want %+v", got.Totals, want)
+	}
+
+	w = s.do(http.MethodGet, "/api/v1/dashboard/renewal-cost?days=abc", token, nil)
+	expectStatus(t, w, http.StatusBadRequest)
+}
+
+func TestUpdateDomainRenewalCost(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	path := "/api/v1/domains/" + strconv.Itoa(int(domain.ID))
+
+	w := s.do(http.MethodPut, path, token, map[string]any{"renewal_cost": 12.5, "currency": " eur "})
+	expectStatus(t, w, http.StatusOK)
+	got := decode[models.Domain](t, w)
+	if got.RenewalCost != 12.5 || got.Currency != "EUR" {
+		t.Errorf("renewal cost = %v %q, want 12.5 \"EUR\"", got.RenewalCost, got.Currency)
+	}
+
+	for _, body := range []map[string]any{
+		{"renewal_cost": -1, "currency": "USD"},
+		{"renewal_cost": 10, "currency": "euro"},
+		{"renewal_cost": 10, "currency": ""},
+	} {
+		w := s.do(http.MethodPut, path, token, body)
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+
+	var stored models.Domain
+	database.GetDB().First(&stored, domain.ID)
+	if stored.RenewalCost != 12.5 || stored.Currency != "EUR" {
+		t.Errorf("stored renewal cost = %v %q, want 12.5 \"EUR\"", stored.RenewalCost, stored.Currency)
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"crypto/rand"
+	"domain-monitor/internal/logging"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID, taken from the request if present
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits inbound request IDs
+const maxRequestIDLength = 128
+
+// RequestLogger assigns each request an ID, logs it when done and turns panics into a
+// JSON 500 response. The ID is attached to the request context for downstream logs.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		ctx := c.Request.Context()
+
+		defer func() {
+			if r := recover(); r != nil {
+				slog.ErrorContext(ctx, "Request panicked", "panic", r, "stack", string(debug.Stack()))
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error", "request_id": id})
+			}
+
+			status := c.Writer.Status()
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+			slog.Log(ctx, level, "Request",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"status", status,
+				"latency", time.Since(start).String(),
+				"client_ip", c.ClientIP(),
+			)
+		}()
+
+		c.Next()
+	}
+}
+
+// validRequestID accepts short printable ASCII IDs from clients
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 16 byte hex ID
+func newRequestID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"domain-monitor/internal/logging"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newLoggedRouter returns a router using RequestLogger with a handler at /ok and one
+// that panics at /panic
+func newLoggedRouter() *gin.Engine {
+	router := gin.New()
+	router.Use(RequestLogger())
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"request_id": logging.RequestID(c.Request.Context())})
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return router
+}
+
+func TestRequestLoggerAssignsRequestID(t *testing.T) {
+	router := newLoggedRouter()
+
+	tests := []struct {
+		name    string
+		inbound string
+		keep    bool
+	}{
+		{"generated", "", false},
+		{"propagated", "upstream-id-123", true},
+		{"invalid replaced", "has spaces", false},
+		{"too long replaced", strings.Repeat("a", maxRequestIDLength+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+			if tt.inbound != "" {
+				req.Header.Set(RequestIDHeader, tt.inbound)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			expectStatus(t, w, http.StatusOK)
+			id := w.Header().Get(RequestIDHeader)
+			if tt.keep && id != tt.inbound {
+				t.Errorf("request ID = %q, want the inbound %q", id, tt.inbound)
+			}
+			if !tt.keep && !regexp.MustCompile(`^[0-9a-f]{32}$`).MatchString(id) {
+				t.Errorf("request ID = %q, want a generated hex ID", id)
+			}
+			// Downstream code sees the same ID through the request context
+			if got := decode[map[string]string](t, w)["request_id"]; got != id {
+				t.Errorf("context request ID = %q, want %q", got, id)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerRecoversPanic(t *testing.T) {
+	router := newLoggedRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	expectStatus(t, w, http.StatusInternalServerError)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want JSON", ct)
+	}
+	body := decode[map[string]string](t, w)
+	if body["error"] == "" {
+		t.Errorf("body = %v, want an error message", body)
+	}
+	if id := w.Header().Get(RequestIDHeader); id == "" || body["request_id"] != id {
+		t.Errorf("body request_id = %q, header = %q, want both set and equal", body["request_id"], id)
+	}
+	if strings.Contains(w.Body.String(), "boom") {
+		t.Error("panic value leaked into the response")
+	}
+}
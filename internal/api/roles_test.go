@@ -0,0 +1,60 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestViewerIsReadOnly(t *testing.T) {
+	s := newTestServer(t)
+	token := s.token(s.createUser("viewer", models.RoleViewer))
+	domain := createDomain(t, "example.com")
+	path := fmt.Sprintf("/api/v1/domains/%d", domain.ID)
+
+	expectStatus(t, s.do(http.MethodGet, path, token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/domains", token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusForbidden)
+
+	if err := database.GetDB().First(&models.Domain{}, domain.ID).Error; err != nil {
+		t.Errorf("domain deleted by a viewer: %v", err)
+	}
+
+	tests := []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPost, "/api/v1/domains", map[string]string{"name": "example.org"}},
+		{http.MethodPut, path, map[string]any{"notes": "changed"}},
+		{http.MethodPut, "/api/v1/settings", map[string]string{"monitor.alert_days": "7"}},
+		{http.MethodPost, "/api/v1/users", map[string]string{"username": "bob", "password": "secret123"}},
+		{http.MethodGet, "/api/v1/users", nil},
+	}
+	for _, tt := range tests {
+		if w := s.do(tt.method, tt.path, token, tt.body); w.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403", tt.method, tt.path, w.Code)
+		}
+	}
+}
+
+func TestAdminCanDelete(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+
+	expectStatus(t, s.do(http.MethodDelete, fmt.Sprintf("/api/v1/domains/%d", domain.ID), token, nil), http.StatusOK)
+}
+
+func TestRoleChangeAppliesToIssuedTokens(t *testing.T) {
+	s := newTestServer(t)
+	s.createUser("admin", models.RoleAdmin)
+	user := s.createUser("alice", models.RoleAdmin)
+	token := s.token(user)
+	domain := createDomain(t, "example.com")
+
+	// Demoted after login: the token still claims admin, but the current role applies
+	database.GetDB().Model(user).Update("role", models.RoleViewer)
+	expectStatus(t, s.do(http.MethodDelete, fmt.Sprintf("/api/v1/domains/%d", domain.ID), token, nil), http.StatusForbidden)
+}
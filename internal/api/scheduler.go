@@ -0,0 +1,48 @@
+package api
+
+import (
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/scheduler"
+	"domain-monitor/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSchedulerStatus returns the check schedule, the next run and whether a check is running
+func (h *Handler) GetSchedulerStatus(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduler is not available"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.scheduler.Status())
+}
+
+// RunScheduler starts the scheduled check of all domains immediately
+func (h *Handler) RunScheduler(c *gin.Context) {
+	if h.scheduler == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Scheduler is not available"})
+		return
+	}
+
+	if err := h.scheduler.RunNow(); err != nil {
+		if errors.Is(err, scheduler.ErrSweepRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Scheduled check started"})
+}
+
+// startRefresh starts a refresh job through the scheduler, so it doesn't overlap a sweep
+func (h *Handler) startRefresh(domains []models.Domain) (*services.RefreshJob, error) {
+	if h.scheduler == nil {
+		return h.monitorService.StartRefresh(domains, nil)
+	}
+	return h.scheduler.StartRefresh(domains)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/scheduler"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerStatus(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	expectStatus(t, s.do(http.MethodGet, "/api/v1/scheduler/status", token, nil), http.StatusServiceUnavailable)
+
+	s.startScheduler("0 9 * * *")
+	w := s.do(http.MethodGet, "/api/v1/scheduler/status", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	status := decode[scheduler.Status](t, w)
+	if status.Interval != "0 9 * * *" || !status.Running || status.SweepRunning {
+		t.Errorf("status = %+v", status)
+	}
+	if status.NextRun == nil || !status.NextRun.After(time.Now()) || status.NextRun.After(time.Now().Add(24*time.Hour)) {
+		t.Errorf("next run = %v, want the next 09:00", status.NextRun)
+	}
+}
+
+func TestRunSchedulerNoOverlap(t *testing.T) {
+	// The WHOIS API holds the sweep until released
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+	unblock := func() { releaseOnce.Do(func() { close(release) }) }
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer whoisAPI.Close()
+	defer unblock()
+
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Whois.APIURL = whoisAPI.URL })
+	token := s.adminToken()
+	createDomain(t, "example.com")
+	sched := s.startScheduler("0 9 * * *")
+
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/scheduler/run", token, nil), http.StatusAccepted)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/scheduler/run", token, nil), http.StatusConflict)
+
+	w := s.do(http.MethodGet, "/api/v1/scheduler/status", token, nil)
+	if status := decode[scheduler.Status](t, w); !status.SweepRunning {
+		t.Errorf("status = %+v, want a running sweep", status)
+	}
+
+	unblock()
+	waitFor(t, func() bool { return !sched.Status().SweepRunning })
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/scheduler/run", token, nil), http.StatusAccepted)
+}
+
+func TestUpdateSettingsReschedules(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	sched := s.startScheduler("0 9 * * *")
+
+	w := s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{"monitor.check_interval": "30 6 * * *"})
+	expectStatus(t, w, http.StatusOK)
+	if got := sched.Status().Interval; got != "30 6 * * *" {
+		t.Errorf("interval = %q, want the new setting applied", got)
+	}
+
+	w = s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{"monitor.check_interval": "not a cron"})
+	expectStatus(t, w, http.StatusBadRequest)
+	if got := sched.Status().Interval; got != "30 6 * * *" {
+		t.Errorf("interval = %q, want the previous schedule kept", got)
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"testing"
+)
+
+func TestUpdateSettingsRejectsInvalidFields(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	w := s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{
+		"monitor.check_interval": "every 5 min",
+		"email.smtp_port":        "70000",
+		"webhook.url":            "not a url",
+		"monitor.alert_days":     "30,7,x",
+		"email.smtp_host":        "smtp.example.com",
+	})
+	expectStatus(t, w, http.StatusBadRequest)
+	body := decode[struct {
+		Fields map[string]string `json:"fields"`
+	}](t, w)
+	for _, key := range []string{"monitor.check_interval", "email.smtp_port", "webhook.url", "monitor.alert_days"} {
+		if body.Fields[key] == "" {
+			t.Errorf("no error reported for %s: %v", key, body.Fields)
+		}
+	}
+	if _, ok := body.Fields["email.smtp_host"]; ok {
+		t.Errorf("valid field reported as invalid: %v", body.Fields)
+	}
+
+	// Nothing is stored, not even the valid field
+	var count int64
+	database.GetDB().Model(&models.Setting{}).Where("key = ?", "email.smtp_host").Count(&count)
+	if count != 0 {
+		t.Error("valid field saved despite invalid fields in the update")
+	}
+}
+
+func TestUpdateSettingsSavesValidFields(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	w := s.do(http.MethodPut, "/api/v1/settings", token, map[string]string{
+		"monitor.check_interval": "*/30 * * * *",
+		"email.smtp_port":        "465",
+	})
+	expectStatus(t, w, http.StatusOK)
+
+	var setting models.Setting
+	if err := database.GetDB().First(&setting, "key = ?", "email.smtp_port").Error; err != nil || setting.Value != "465" {
+		t.Errorf("email.smtp_port = %q (%v), want 465", setting.Value, err)
+	}
+}
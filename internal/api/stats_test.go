@@ -0,0 +1,172 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatsExcludeParseErrors(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	past := time.Now().AddDate(0, 0, -3)
+	future := time.Now().AddDate(0, 0, 200)
+	domains := []models.Domain{
+		{Name: "expired.com", ExpiryDate: &past, CheckStatus: models.CheckStatusOK},
+		{Name: "fine.com", ExpiryDate: &future, CheckStatus: models.CheckStatusOK},
+		{Name: "unparsed.com", CheckStatus: models.CheckStatusParseError, LastCheckError: "no expiry date"},
+		// An unparseable answer after an earlier expiry date had been stored
+		{Name: "stale.com", ExpiryDate: &past, CheckStatus: models.CheckStatusParseError, LastCheckError: "no expiry date"},
+	}
+	for i := range domains {
+		domains[i].IsActive = true
+		if err := database.GetDB().Create(&domains[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := s.do(http.MethodGet, "/api/v1/dashboard/stats", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	stats := decode[map[string]any](t, w)
+	if stats["expired"] != float64(1) {
+		t.Errorf("expired = %v, want 1", stats["expired"])
+	}
+	if stats["needs_attention"] != float64(2) {
+		t.Errorf("needs_attention = %v, want 2", stats["needs_attention"])
+	}
+	if stats["total"] != float64(4) {
+		t.Errorf("total = %v, want 4", stats["total"])
+	}
+}
+
+// createDomainsExpiringIn creates an active domain expiring in each number of days
+func createDomainsExpiringIn(t *testing.T, days ...int) {
+	t.Helper()
+	for _, d := range days {
+		createExpiringDomain(t, fmt.Sprintf("in%d.com", d), time.Now().AddDate(0, 0, d), false)
+	}
+}
+
+// expiringStats is the part of the dashboard stats concerning expiring domains
+type expiringStats struct {
+	ExpiringDays int `json:"expiring_days"`
+	ExpiringSoon int `json:"expiring_soon"`
+	Expiring     []struct {
+		Days  int `json:"days"`
+		Count int `json:"count"`
+	} `json:"expiring"`
+	Expired int `json:"expired"`
+}
+
+// bucketCounts returns the expiring counts keyed by their bucket
+func (s expiringStats) bucketCounts() map[int]int {
+	counts := make(map[int]int)
+	for _, bucket := range s.Expiring {
+		counts[bucket.Days] = bucket.Count
+	}
+	return counts
+}
+
+func TestStatsExpiringWindow(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createDomainsExpiringIn(t, -5, 0, 5, 20, 45, 60, 61, 200)
+	// Unknown expiry dates are neither expiring nor expired
+	createDomain(t, "unknown.com")
+
+	tests := []struct {
+		query    string
+		wantDays int
+		wantSoon int
+	}{
+		{"", 30, 2},
+		{"?days=60", 60, 4},
+		{"?days=7", 7, 1},
+		{"?days=100000", maxExpiringDays, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := s.do(http.MethodGet, "/api/v1/dashboard/stats"+tt.query, token, nil)
+			expectStatus(t, w, http.StatusOK)
+			stats := decode[expiringStats](t, w)
+			if stats.ExpiringDays != tt.wantDays || stats.ExpiringSoon != tt.wantSoon {
+				t.Errorf("expiring soon = %d within %d days, want %d within %d",
+					stats.ExpiringSoon, stats.ExpiringDays, tt.wantSoon, tt.wantDays)
+			}
+			if stats.Expired != 2 {
+				t.Errorf("expired = %d, want 2", stats.Expired)
+			}
+			want := map[int]int{7: 1, 30: 2, 90: 5}
+			if got := stats.bucketCounts(); !reflect.DeepEqual(got, want) {
+				t.Errorf("buckets = %v, want %v", got, want)
+			}
+		})
+	}
+
+	for _, query := range []string{"?days=abc", "?days=-1"} {
+		w := s.do(http.MethodGet, "/api/v1/dashboard/stats"+query, token, nil)
+		expectStatus(t, w, http.StatusBadRequest)
+	}
+}
+
+func TestStatsConfiguredWindow(t *testing.T) {
+	s := newTestServer(t, func(cfg *config.Config) {
+		cfg.Monitor.ExpiringDays = 60
+		cfg.Monitor.ExpiringBuckets = []int{60, 14, 0, 14}
+	})
+	token := s.adminToken()
+	createDomainsExpiringIn(t, 5, 20, 45, 61)
+
+	w := s.do(http.MethodGet, "/api/v1/dashboard/stats", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	stats := decode[expiringStats](t, w)
+	if stats.ExpiringDays != 60 || stats.ExpiringSoon != 3 {
+		t.Errorf("expiring soon = %d within %d days, want 3 within 60", stats.ExpiringSoon, stats.ExpiringDays)
+	}
+	var days []int
+	for _, bucket := range stats.Expiring {
+		days = append(days, bucket.Days)
+	}
+	if want := []int{14, 60}; !reflect.DeepEqual(days, want) {
+		t.Errorf("buckets = %v, want %v", days, want)
+	}
+	if want := map[int]int{14: 1, 60: 3}; !reflect.DeepEqual(stats.bucketCounts(), want) {
+		t.Errorf("bucket counts = %v, want %v", stats.bucketCounts(), want)
+	}
+}
+
+func TestExpiringWindow(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createDomainsExpiringIn(t, -5, 0, 45, 5, 20, 61)
+	createExpiringDomain(t, "retiring.com", time.Now().AddDate(0, 0, 3), true)
+	createDomain(t, "unknown.com")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"in5.com", "in20.com"}},
+		{"?days=60", []string{"in5.com", "in20.com", "in45.com"}},
+		{"?days=5", []string{"in5.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := s.do(http.MethodGet, "/api/v1/dashboard/expiring"+tt.query, token, nil)
+			expectStatus(t, w, http.StatusOK)
+			var names []string
+			for _, domain := range decode[[]models.Domain](t, w) {
+				names = append(names, domain.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("expiring = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
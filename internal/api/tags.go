@@ -0,0 +1,87 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AddDomainTags adds one tag ({"tag": "..."}) or several ({"tags": [...]}) to a domain
+func (h *Handler) AddDomainTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	var request struct {
+		Tag  string   `json:"tag"`
+		Tags []string `json:"tags"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	tags := request.Tags
+	if request.Tag != "" {
+		tags = append(tags, request.Tag)
+	}
+	if len(tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag or tags is required"})
+		return
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || strings.Contains(tag, ",") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tags must not be empty or contain commas"})
+			return
+		}
+	}
+
+	h.updateDomainTags(c, uint(id), func(domain *models.Domain) {
+		for _, tag := range tags {
+			domain.AddTag(tag)
+		}
+	})
+}
+
+// RemoveDomainTag removes a tag from a domain (case-insensitive); removing a tag the
+// domain doesn't have succeeds without changes
+func (h *Handler) RemoveDomainTag(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	tag := c.Param("tag")
+	h.updateDomainTags(c, uint(id), func(domain *models.Domain) {
+		domain.RemoveTag(tag)
+	})
+}
+
+// updateDomainTags applies change to the tags of a domain and saves only the tags column
+func (h *Handler) updateDomainTags(c *gin.Context, id uint, change func(domain *models.Domain)) {
+	db := database.GetDB()
+
+	var domain models.Domain
+	if err := db.First(&domain, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+
+	change(&domain)
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", id).UpdateColumn("tags", domain.Tags).Error
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// createTaggedDomain stores an active domain with the tags
+func createTaggedDomain(t *testing.T, name string, tags ...string) *models.Domain {
+	t.Helper()
+	domain := &models.Domain{Name: name, IsActive: true}
+	domain.SetTags(tags)
+	if err := database.GetDB().Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+	return domain
+}
+
+func TestListDomainsTagFilter(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	createTaggedDomain(t, "a.com", "prod", "client")
+	createTaggedDomain(t, "b.com", "prod")
+	createTaggedDomain(t, "c.com")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"a.com", "b.com", "c.com"}},
+		{"?tag=prod", []string{"a.com", "b.com"}},
+		{"?tag=PROD", []string{"a.com", "b.com"}},
+		{"?tag=prod&tag=client", []string{"a.com"}},
+		{"?tag=missing", []string{}},
+	}
+	for _, tt := range tests {
+		w := s.do(http.MethodGet, "/api/v1/domains"+tt.query, token, nil)
+		expectStatus(t, w, http.StatusOK)
+		names := []string{}
+		for _, d := range decode[[]models.Domain](t, w) {
+			names = append(names, d.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("GET /domains%s = %v, want %v", tt.query, names, tt.want)
+		}
+	}
+}
+
+func TestAddRemoveDomainTags(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createTaggedDomain(t, "example.com", "prod")
+	path := fmt.Sprintf("/api/v1/domains/%d/tags", domain.ID)
+
+	w := s.do(http.MethodPost, path, token, map[string]any{"tag": "client"})
+	expectStatus(t, w, http.StatusOK)
+	w = s.do(http.MethodPost, path, token, map[string]any{"tags": []string{"billing", "Prod"}})
+	expectStatus(t, w, http.StatusOK)
+	updated := decode[models.Domain](t, w)
+	if got := updated.TagList(); !reflect.DeepEqual(got, []string{"prod", "client", "billing"}) {
+		t.Errorf("tags = %v, want prod, client, billing", got)
+	}
+
+	expectStatus(t, s.do(http.MethodDelete, path+"/CLIENT", token, nil), http.StatusOK)
+	// Removing a tag the domain doesn't have is not an error
+	expectStatus(t, s.do(http.MethodDelete, path+"/missing", token, nil), http.StatusOK)
+
+	var stored models.Domain
+	database.GetDB().First(&stored, domain.ID)
+	if got := stored.TagList(); !reflect.DeepEqual(got, []string{"prod", "billing"}) {
+		t.Errorf("stored tags = %v, want prod, billing", got)
+	}
+}
+
+func TestAddDomainTagsInvalid(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createTaggedDomain(t, "example.com")
+	path := fmt.Sprintf("/api/v1/domains/%d/tags", domain.ID)
+
+	expectStatus(t, s.do(http.MethodPost, path, token, map[string]any{}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, path, token, map[string]any{"tag": "a,b"}), http.StatusBadRequest)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/domains/999/tags", token, map[string]any{"tag": "prod"}), http.StatusNotFound)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errNameInUse is returned when restoring a domain whose name was added again
+var errNameInUse = errors.New("name in use")
+
+// ListTrash returns the deleted domains, most recently deleted first
+func (h *Handler) ListTrash(c *gin.Context) {
+	db := database.GetDB()
+
+	var domains []models.Domain
+	if err := db.Unscoped().Omit("raw_whois").Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&domains).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domains)
+}
+
+// RestoreDomain moves a domain out of the trash
+func (h *Handler) RestoreDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	var domain models.Domain
+	err = database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&domain, id).Error; err != nil {
+				return err
+			}
+
+			// The pending deletion request was confirmed by moving the domain to the trash.
+			// The unique index rejects the restore if the name was added again.
+			if err := tx.Unscoped().Model(&models.Domain{}).Where("id = ?", id).Updates(map[string]interface{}{
+				"deleted_at":          nil,
+				"delete_requested_by": "",
+				"delete_requested_at": nil,
+			}).Error; database.IsDuplicateKey(err) {
+				return errNameInUse
+			} else if err != nil {
+				return err
+			}
+			return tx.First(&domain, id).Error
+		})
+	})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found in trash"})
+		return
+	case errors.Is(err, errNameInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Domain %s already exists", domain.Name)})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain)
+}
+
+// PurgeDomain permanently removes a domain in the trash together with its check history
+func (h *Handler) PurgeDomain(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid domain ID"})
+		return
+	}
+
+	// Only trashed domains can be purged, so deletion confirmation can't be bypassed
+	var domain models.Domain
+	err = database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Unscoped().Select("id", "name").Where("deleted_at IS NOT NULL").First(&domain, id).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("domain_id = ?", id).Delete(&models.DomainCheckHistory{}).Error; err != nil {
+				return err
+			}
+			return tx.Unscoped().Delete(&models.Domain{}, id).Error
+		})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit(c, models.AuditDomainPurge, "domain:"+domain.Name, nil, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Domain permanently deleted"})
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// trashNames returns the names of the domains in the trash
+func (s *testServer) trashNames(token string) []string {
+	s.t.Helper()
+	w := s.do(http.MethodGet, "/api/v1/domains/trash", token, nil)
+	expectStatus(s.t, w, http.StatusOK)
+	names := []string{}
+	for _, d := range decode[[]models.Domain](s.t, w) {
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func TestDeleteAndRestoreDomain(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	path := fmt.Sprintf("/api/v1/domains/%d", domain.ID)
+
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodGet, path, token, nil), http.StatusNotFound)
+	if names := s.trashNames(token); len(names) != 1 || names[0] != "example.com" {
+		t.Errorf("trash = %v, want example.com", names)
+	}
+
+	w := s.do(http.MethodPost, path+"/restore", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if restored := decode[models.Domain](t, w); restored.ID != domain.ID || restored.Name != "example.com" {
+		t.Errorf("restored = %+v, want example.com", restored)
+	}
+	expectStatus(t, s.do(http.MethodGet, path, token, nil), http.StatusOK)
+	if names := s.trashNames(token); len(names) != 0 {
+		t.Errorf("trash = %v after restore, want empty", names)
+	}
+
+	// Only trashed domains can be restored
+	expectStatus(t, s.do(http.MethodPost, path+"/restore", token, nil), http.StatusNotFound)
+}
+
+func TestDeleteAndPurgeDomain(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	path := fmt.Sprintf("/api/v1/domains/%d", domain.ID)
+	database.GetDB().Create(&models.DomainCheckHistory{DomainID: domain.ID, CheckedAt: time.Now(), Success: true})
+
+	// Domains outside the trash can't be purged
+	expectStatus(t, s.do(http.MethodDelete, path+"/purge", token, nil), http.StatusNotFound)
+
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodDelete, path+"/purge", token, nil), http.StatusOK)
+
+	if err := database.GetDB().Unscoped().First(&models.Domain{}, domain.ID).Error; err == nil {
+		t.Error("purged domain still stored")
+	}
+	var history int64
+	database.GetDB().Model(&models.DomainCheckHistory{}).Where("domain_id = ?", domain.ID).Count(&history)
+	if history != 0 {
+		t.Errorf("%d history entries left after purge, want 0", history)
+	}
+	if names := s.trashNames(token); len(names) != 0 {
+		t.Errorf("trash = %v after purge, want empty", names)
+	}
+	expectStatus(t, s.do(http.MethodPost, path+"/restore", token, nil), http.StatusNotFound)
+}
+
+func TestTrashedNameReusable(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+
+	expectStatus(t, s.do(http.MethodDelete, fmt.Sprintf("/api/v1/domains/%d", domain.ID), token, nil), http.StatusOK)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/domains", token, map[string]any{"name": "example.com"}), http.StatusCreated)
+
+	// The trashed copy can't come back while the name is in use
+	expectStatus(t, s.do(http.MethodPost, fmt.Sprintf("/api/v1/domains/%d/restore", domain.ID), token, nil), http.StatusConflict)
+}
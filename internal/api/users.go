@@ -0,0 +1,252 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// minPasswordLength is the minimum length of user passwords
+const minPasswordLength = 6
+
+// errLastActiveAdmin is returned when a change would leave no active admin to manage the system
+var errLastActiveAdmin = errors.New("cannot remove, deactivate or demote the last active admin")
+
+// ListUsers returns all user accounts
+func (h *Handler) ListUsers(c *gin.Context) {
+	db := database.GetDB()
+
+	var users []models.User
+	if err := db.Order("username asc").Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
+
+// CreateUser adds a user account
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req struct {
+		Username string `json:"username" binding:"required"`
+		Email    string `json:"email"`
+		Password string `json:"password" binding:"required"`
+		Role     string `json:"role"`      // default viewer
+		IsActive *bool  `json:"is_active"` // default true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "username is required"})
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password must be at least 6 characters"})
+		return
+	}
+	if !validEmail(req.Email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+	if req.Role == "" {
+		req.Role = models.RoleViewer
+	}
+	if !models.ValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be admin or viewer"})
+		return
+	}
+
+	db := database.GetDB()
+
+	var count int64
+	if err := db.Model(&models.User{}).Where("username = ?", req.Username).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Username already exists"})
+		return
+	}
+
+	hashedPassword, err := h.authService.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := models.User{
+		Username:  req.Username,
+		Password:  hashedPassword,
+		Email:     req.Email,
+		Role:      req.Role,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	active := req.IsActive == nil || *req.IsActive
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+			// A false IsActive would be replaced by the column default on create
+			if !active {
+				user.IsActive = false
+				return tx.Model(&user).Update("is_active", false).Error
+			}
+			return nil
+		})
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	h.audit(c, models.AuditUserCreate, "user:"+user.Username, nil, userSummary(&user))
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// UpdateUser changes the email, role or active state of a user account
+func (h *Handler) UpdateUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req struct {
+		Email    *string `json:"email"`
+		Role     *string `json:"role"`
+		IsActive *bool   `json:"is_active"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Email != nil && !validEmail(*req.Email) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+	if req.Role != nil && !models.ValidRole(*req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role must be admin or viewer"})
+		return
+	}
+
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if req.Email != nil {
+		updates["email"] = *req.Email
+	}
+	if req.Role != nil {
+		updates["role"] = *req.Role
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	var user, previous models.User
+	err = database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&user, id).Error; err != nil {
+				return err
+			}
+			previous = user
+			deactivated := req.IsActive != nil && !*req.IsActive
+			demoted := req.Role != nil && *req.Role != models.RoleAdmin
+			if user.IsActive && user.Role == models.RoleAdmin && (deactivated || demoted) {
+				if err := ensureOtherActiveAdmin(tx, user.ID); err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&user).Updates(updates).Error; err != nil {
+				return err
+			}
+			return tx.First(&user, id).Error
+		})
+	})
+	if !h.userWriteOK(c, err) {
+		return
+	}
+	if before, after := changedValues(userSummary(&previous), userSummary(&user)); len(after) > 0 {
+		h.audit(c, models.AuditUserUpdate, "user:"+user.Username, before, after)
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DeleteUser removes a user account, refusing to delete the last active admin
+func (h *Handler) DeleteUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var user models.User
+	err = database.WithRetry(func(db *gorm.DB) error {
+		return db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.First(&user, id).Error; err != nil {
+				return err
+			}
+			if user.IsActive && user.Role == models.RoleAdmin {
+				if err := ensureOtherActiveAdmin(tx, user.ID); err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&user).Error
+		})
+	})
+	if !h.userWriteOK(c, err) {
+		return
+	}
+	h.audit(c, models.AuditUserDelete, "user:"+user.Username, userSummary(&user), nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// userWriteOK writes the error response of a failed user update, if any
+func (h *Handler) userWriteOK(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, errLastActiveAdmin):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return false
+}
+
+// ensureOtherActiveAdmin returns errLastActiveAdmin unless another active admin exists
+func ensureOtherActiveAdmin(tx *gorm.DB, id uint) error {
+	var count int64
+	if err := tx.Model(&models.User{}).Where("is_active = ? AND role = ? AND id <> ?", true, models.RoleAdmin, id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errLastActiveAdmin
+	}
+	return nil
+}
+
+// validEmail accepts an empty or well-formed email address
+func validEmail(email string) bool {
+	if email == "" {
+		return true
+	}
+	_, err := mail.ParseAddress(email)
+	return err == nil
+}
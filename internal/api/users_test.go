@@ -0,0 +1,121 @@
+package api
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCreateUser(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	w := s.do(http.MethodPost, "/api/v1/users", token, map[string]string{
+		"username": " alice ",
+		"email":    "alice@example.com",
+		"password": "secret123",
+	})
+	expectStatus(t, w, http.StatusCreated)
+	created := decode[models.User](t, w)
+	if created.Username != "alice" || created.Email != "alice@example.com" || created.Role != models.RoleViewer || !created.IsActive {
+		t.Errorf("created user = %+v, want an active viewer alice", created)
+	}
+
+	var stored models.User
+	if err := database.GetDB().First(&stored, created.ID).Error; err != nil {
+		t.Fatalf("load user: %v", err)
+	}
+	if stored.Password == "secret123" || !s.auth.CheckPassword(stored.Password, "secret123") {
+		t.Error("password not stored as a hash of the given password")
+	}
+
+	// The new user can log in
+	w = s.do(http.MethodPost, "/api/v1/auth/login", "", map[string]string{"username": "alice", "password": "secret123"})
+	expectStatus(t, w, http.StatusOK)
+
+	w = s.do(http.MethodGet, "/api/v1/users", token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if users := decode[[]models.User](t, w); len(users) != 2 || users[0].Username != "admin" || users[1].Username != "alice" {
+		t.Errorf("users = %+v, want admin and alice", users)
+	}
+}
+
+func TestCreateUserInvalid(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	tests := []map[string]string{
+		{"password": "secret123"},
+		{"username": "bob", "password": "short"},
+		{"username": "bob", "password": "secret123", "email": "not an email"},
+		{"username": "bob", "password": "secret123", "role": "owner"},
+	}
+	for _, body := range tests {
+		w := s.do(http.MethodPost, "/api/v1/users", token, body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("create %v: status = %d, want 400", body, w.Code)
+		}
+	}
+}
+
+func TestCreateUserDuplicate(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+
+	body := map[string]string{"username": "alice", "password": "secret123"}
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/users", token, body), http.StatusCreated)
+	expectStatus(t, s.do(http.MethodPost, "/api/v1/users", token, body), http.StatusConflict)
+
+	var count int64
+	database.GetDB().Model(&models.User{}).Where("username = ?", "alice").Count(&count)
+	if count != 1 {
+		t.Errorf("%d users named alice, want 1", count)
+	}
+}
+
+func TestLastActiveAdminProtected(t *testing.T) {
+	s := newTestServer(t)
+	admin := s.createUser("admin", models.RoleAdmin)
+	token := s.token(admin)
+	s.createUser("viewer", models.RoleViewer)
+	path := fmt.Sprintf("/api/v1/users/%d", admin.ID)
+
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPut, path, token, map[string]any{"is_active": false}), http.StatusConflict)
+	expectStatus(t, s.do(http.MethodPut, path, token, map[string]any{"role": models.RoleViewer}), http.StatusConflict)
+
+	var stored models.User
+	database.GetDB().First(&stored, admin.ID)
+	if !stored.IsActive || stored.Role != models.RoleAdmin {
+		t.Errorf("last admin changed to %+v", stored)
+	}
+
+	// An inactive admin doesn't count
+	other := s.createUser("other", models.RoleAdmin)
+	database.GetDB().Model(other).Update("is_active", false)
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusConflict)
+
+	// With another active admin the first one can be removed
+	database.GetDB().Model(other).Update("is_active", true)
+	expectStatus(t, s.do(http.MethodDelete, path, token, nil), http.StatusOK)
+	if err := database.GetDB().First(&models.User{}, admin.ID).Error; err == nil {
+		t.Error("admin not deleted")
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	viewer := s.createUser("viewer", models.RoleViewer)
+	path := fmt.Sprintf("/api/v1/users/%d", viewer.ID)
+
+	w := s.do(http.MethodPut, path, token, map[string]any{"email": "viewer@example.com", "is_active": false})
+	expectStatus(t, w, http.StatusOK)
+	if got := decode[models.User](t, w); got.Email != "viewer@example.com" || got.IsActive {
+		t.Errorf("updated user = %+v, want the new email and inactive", got)
+	}
+
+	expectStatus(t, s.do(http.MethodPut, "/api/v1/users/999", token, map[string]any{"is_active": true}), http.StatusNotFound)
+}
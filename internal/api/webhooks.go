@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/subtle"
+	"domain-monitor/internal/services"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxWebhookBodySize limits the size of inbound webhook payloads
+const maxWebhookBodySize = 1 << 20
+
+// RegistrarWebhook receives domain events pushed by a registrar
+func (h *Handler) RegistrarWebhook(c *gin.Context) {
+	webhookCfg := h.cfg.RegistrarWebhooks
+	if !webhookCfg.Enabled || webhookCfg.Token == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Registrar webhooks are disabled"})
+		return
+	}
+
+	token := c.GetHeader("X-Webhook-Token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(webhookCfg.Token)) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid webhook token"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := services.ParseRegistrarEvent(c.Param("provider"), body, webhookCfg.Generic)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, err := h.monitorService.ApplyRegistrarEvent(event)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Domain not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event":  event,
+		"domain": domain,
+	})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetWhois(t *testing.T) {
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"data":{"registrar":"Example Registrar","expirationDate":"2030-05-01T00:00:00Z","nameServers":["ns1.example.net"],"extra":"kept"}}`)
+	}))
+	defer whoisAPI.Close()
+
+	s := newTestServer(t, func(cfg *config.Config) { cfg.Whois.APIURL = whoisAPI.URL })
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	path := fmt.Sprintf("/api/v1/domains/%d/whois", domain.ID)
+
+	// Nothing is stored before the first check
+	expectStatus(t, s.do(http.MethodGet, path, token, nil), http.StatusNotFound)
+
+	expectStatus(t, s.do(http.MethodGet, fmt.Sprintf("/api/v1/domains/%d/refresh", domain.ID), token, nil), http.StatusOK)
+
+	w := s.do(http.MethodGet, path, token, nil)
+	expectStatus(t, w, http.StatusOK)
+	body := decode[struct {
+		Domain string         `json:"domain"`
+		Format string         `json:"format"`
+		Raw    map[string]any `json:"raw"`
+		Parsed struct {
+			Registrar   string    `json:"registrar"`
+			ExpiryDate  time.Time `json:"expiry_date"`
+			NameServers []string  `json:"name_servers"`
+		} `json:"parsed"`
+	}](t, w)
+
+	if body.Domain != "example.com" || body.Format != "json" {
+		t.Errorf("domain = %q, format = %q", body.Domain, body.Format)
+	}
+	if body.Raw["extra"] != "kept" || body.Raw["registrar"] != "Example Registrar" {
+		t.Errorf("raw = %v, want the full API answer", body.Raw)
+	}
+	if body.Parsed.Registrar != "Example Registrar" || !body.Parsed.ExpiryDate.Equal(time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)) || len(body.Parsed.NameServers) != 1 {
+		t.Errorf("parsed = %+v", body.Parsed)
+	}
+
+	// The domain list doesn't carry the raw response
+	w = s.do(http.MethodGet, "/api/v1/domains", token, nil)
+	if strings.Contains(w.Body.String(), "kept") {
+		t.Error("raw WHOIS included in the domain list")
+	}
+}
+
+func TestDownloadWhois(t *testing.T) {
+	s := newTestServer(t)
+	token := s.adminToken()
+	domain := createDomain(t, "example.com")
+	checked := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	database.GetDB().Model(&models.Domain{}).Where("id = ?", domain.ID).UpdateColumns(map[string]interface{}{
+		"raw_whois":    "Domain Name: EXAMPLE.COM\nRegistrar: Example Registrar\n",
+		"last_checked": checked,
+	})
+
+	w := s.do(http.MethodGet, fmt.Sprintf("/api/v1/domains/%d/whois/download", domain.ID), token, nil)
+	expectStatus(t, w, http.StatusOK)
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text for a native WHOIS response", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "example.com-whois-20260301") || !strings.Contains(cd, ".txt") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+	if !strings.Contains(w.Body.String(), "Registrar: Example Registrar") {
+		t.Errorf("body = %q, want the raw response", w.Body.String())
+	}
+}
@@ -1,24 +1,43 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server        ServerConfig        `yaml:"server"`
-	Database      DatabaseConfig      `yaml:"database"`
-	Whois         WhoisConfig         `yaml:"whois"`
-	Monitor       MonitorConfig       `yaml:"monitor"`
-	Notifications NotificationsConfig `yaml:"notifications"`
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	Whois    WhoisConfig    `yaml:"whois"`
+	Monitor  MonitorConfig  `yaml:"monitor"`
+	Cert     CertConfig     `yaml:"cert"`
+	DNS      DNSConfig      `yaml:"dns"`
+	Uptime   UptimeConfig   `yaml:"uptime"`
+
+	RegistrarWebhooks RegistrarWebhooksConfig `yaml:"registrar_webhooks"`
+	Notifications     NotificationsConfig     `yaml:"notifications"`
+	Security          SecurityConfig          `yaml:"security"`
+	Metrics           MetricsConfig           `yaml:"metrics"`
+	Log               LogConfig               `yaml:"log"`
+
+	// IANA time zone of days remaining, displayed dates and the check schedule,
+	// e.g. Asia/Shanghai (default: the server's local time)
+	Timezone string `yaml:"timezone"`
 }
 
 // ServerConfig represents server configuration
 type ServerConfig struct {
-	Port string `yaml:"port"`
-	Mode string `yaml:"mode"` // debug/release
+	Port      string `yaml:"port"`
+	Mode      string `yaml:"mode"`       // debug/release
+	JWTSecret string `yaml:"jwt_secret"` // Token signing key, overridden by JIANKONG_JWT_SECRET
+
+	TokenRefreshGrace string `yaml:"token_refresh_grace"` // How long after expiry a token can still be refreshed (default 24h)
+	ShutdownTimeout   string `yaml:"shutdown_timeout"`    // How long to wait for requests and checks to finish on shutdown (default 30s)
 }
 
 // DatabaseConfig represents database configuration
@@ -30,26 +49,183 @@ type DatabaseConfig struct {
 	User     string `yaml:"user"`
 	Password string `yaml:"password"`
 	DBName   string `yaml:"dbname"`
+
+	// Connection pool
+	MaxOpenConns    int    `yaml:"max_open_conns"`    // Maximum open connections (0 = unlimited)
+	MaxIdleConns    int    `yaml:"max_idle_conns"`    // Maximum idle connections (0 = driver default)
+	ConnMaxLifetime string `yaml:"conn_max_lifetime"` // Maximum connection lifetime, e.g. "1h"
+
+	// MySQL connection options
+	Charset string `yaml:"charset"` // Connection charset (default utf8mb4)
+
+	// PostgreSQL connection options
+	SSLMode string `yaml:"sslmode"` // disable/require/verify-ca/verify-full (default disable)
+
+	// SQLite connection tuning
+	BusyTimeout int      `yaml:"busy_timeout"` // Milliseconds to wait on a locked database (default 5000)
+	JournalMode string   `yaml:"journal_mode"` // SQLite journal mode (default WAL)
+	Pragmas     []string `yaml:"pragmas"`      // Extra PRAGMA statements, e.g. "synchronous = NORMAL"
 }
 
 // WhoisConfig represents WHOIS API configuration
 type WhoisConfig struct {
+	Mode    string `yaml:"mode"` // api (HTTP WHOIS API, default) or native (port 43 registry WHOIS)
 	APIURL  string `yaml:"api_url"`
 	Timeout string `yaml:"timeout"`
+
+	EnableRDAP bool   `yaml:"enable_rdap"` // Fall back to RDAP when the API fails or returns no expiry date
+	RDAPURL    string `yaml:"rdap_url"`    // RDAP domain endpoint (default https://rdap.org/domain/)
+
+	CacheTTL   string `yaml:"cache_ttl"`    // Reuse WHOIS results kept in memory for this long (default 12h, 0 disables)
+	DBCacheTTL string `yaml:"db_cache_ttl"` // Reuse WHOIS results stored in the database for this long (e.g. 6h, empty disables)
+
+	MaxResponseSize int64 `yaml:"max_response_size"` // Maximum WHOIS/RDAP response size in bytes (default 1 MiB)
+
+	RateLimit float64 `yaml:"rate_limit"` // Maximum live WHOIS queries per second (0 = unlimited)
+
+	// Non-zero API codes, or message substrings (case-insensitive), meaning the domain is not
+	// registered. Other non-zero codes are treated as errors and retried on the next check.
+	NotFoundCodes    []int    `yaml:"not_found_codes"`
+	NotFoundMessages []string `yaml:"not_found_messages"`
+
+	// TLD check when adding domains: off (default), warn or block. Uses SupportedTLDs,
+	// or in native mode whether a registry WHOIS server can be found for the TLD.
+	TLDValidation string   `yaml:"tld_validation"`
+	SupportedTLDs []string `yaml:"supported_tlds"` // e.g. [com, net, com.cn]
 }
 
 // MonitorConfig represents monitoring configuration
 type MonitorConfig struct {
 	CheckInterval string `yaml:"check_interval"` // Cron expression
 	AlertDays     []int  `yaml:"alert_days"`
+	AlertMode     string `yaml:"alert_mode"`  // threshold (alert_days, default) or transition (urgency bucket changes)
+	DigestMode    bool   `yaml:"digest_mode"` // One consolidated expiry notification per scheduled run
+
+	AnomalyToleranceDays      int  `yaml:"anomaly_tolerance_days"`      // Allowed drop beyond elapsed time before warning (default 1)
+	CheckOverdueOnStartup     bool `yaml:"check_overdue_on_startup"`    // Check domains that missed a scheduled run while the server was down
+	FailureThreshold          int  `yaml:"failure_threshold"`           // Consecutive failed checks before a domain shows an error (default 3)
+	AutoDisableThreshold      int  `yaml:"auto_disable_threshold"`      // Consecutive failed checks before monitoring is turned off (0 = never)
+	Concurrency               int  `yaml:"concurrency"`                 // Domains checked in parallel (default 5)
+	HistoryRetentionDays      int  `yaml:"history_retention_days"`      // Days of check history kept per domain (default 365)
+	NotificationRetentionDays int  `yaml:"notification_retention_days"` // Days of notification history kept (default 365)
+
+	ParkingNameservers []string `yaml:"parking_nameservers"` // Name server patterns of parking/for-sale providers (substring or glob)
+
+	ExpiringDays    int   `yaml:"expiring_days"`    // Days remaining counted as expiring soon on the dashboard (default 30)
+	ExpiringBuckets []int `yaml:"expiring_buckets"` // Days remaining buckets of the dashboard stats (default 7, 30, 90)
+}
+
+// CertConfig represents TLS certificate monitoring configuration
+type CertConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Port        int    `yaml:"port"`        // TLS port (default 443)
+	Timeout     string `yaml:"timeout"`     // Per-connection dial/handshake timeout (default 10s)
+	Concurrency int    `yaml:"concurrency"` // Parallel certificate checks (default 10)
+}
+
+// DNSConfig represents DNS record monitoring configuration
+type DNSConfig struct {
+	Enabled     bool     `yaml:"enabled"`
+	RecordTypes []string `yaml:"record_types"` // Record types watched for domains without their own list (default A, AAAA, MX, NS)
+	Resolver    string   `yaml:"resolver"`     // DNS server address (host:port), empty = system resolver
+	Timeout     string   `yaml:"timeout"`      // Timeout of the lookups of one domain (default 10s)
+}
+
+// UptimeConfig represents HTTP(S) uptime checking of the domains that enable it
+type UptimeConfig struct {
+	Enabled     bool   `yaml:"enabled"`
+	Interval    string `yaml:"interval"`    // Cron expression of the uptime checks (default every 5 minutes)
+	Method      string `yaml:"method"`      // GET (default) or HEAD
+	Timeout     string `yaml:"timeout"`     // Request timeout (default 10s)
+	Concurrency int    `yaml:"concurrency"` // Parallel uptime checks (default 10)
+
+	// Consecutive failed checks before a site is considered down (default 2)
+	FailureThreshold int `yaml:"failure_threshold"`
+}
+
+// SecurityConfig represents safeguards for destructive operations and logins
+type SecurityConfig struct {
+	// Deleting a domain only marks it pending; a different admin must confirm the deletion
+	RequireDeleteConfirmation bool `yaml:"require_delete_confirmation"`
+
+	// Failed logins per username or client IP within login_window before further attempts
+	// are refused for login_lockout (defaults 5, 15m and 15m; -1 disables)
+	LoginMaxAttempts int    `yaml:"login_max_attempts"`
+	LoginWindow      string `yaml:"login_window"`
+	LoginLockout     string `yaml:"login_lockout"`
+}
+
+// MetricsConfig represents the Prometheus metrics endpoint configuration
+type MetricsConfig struct {
+	Enabled   bool   `yaml:"enabled"`    // Serve GET /metrics
+	Token     string `yaml:"token"`      // Bearer token required to scrape (optional)
+	PerDomain bool   `yaml:"per_domain"` // Export days remaining per domain (one series per domain)
+}
+
+// LogConfig represents logging configuration
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug/info (default)/warn/error
+	Format string `yaml:"format"` // text (default) or json
+}
+
+// RegistrarWebhooksConfig represents inbound registrar webhook configuration
+type RegistrarWebhooksConfig struct {
+	Enabled bool                  `yaml:"enabled"`
+	Token   string                `yaml:"token"`   // Shared token, sent as X-Webhook-Token header or ?token=
+	Generic RegistrarFieldMapping `yaml:"generic"` // Field mapping for the "generic" provider
+}
+
+// RegistrarFieldMapping maps JSON fields (dot separated paths) to registrar event fields
+type RegistrarFieldMapping struct {
+	DomainField    string `yaml:"domain_field"`    // default "domain"
+	EventField     string `yaml:"event_field"`     // default "event"
+	ExpiryField    string `yaml:"expiry_field"`    // default "expiry_date"
+	RegistrarField string `yaml:"registrar_field"` // default "registrar"
 }
 
 // NotificationsConfig represents notification configuration
 type NotificationsConfig struct {
-	Email     EmailConfig     `yaml:"email"`
-	Webhook   WebhookConfig   `yaml:"webhook"`
-	Telegram  TelegramConfig  `yaml:"telegram"`
-	DingDing  DingDingConfig  `yaml:"dingding"`
+	Email    EmailConfig    `yaml:"email"`
+	Webhook  WebhookConfig  `yaml:"webhook"`
+	Telegram TelegramConfig `yaml:"telegram"`
+	DingDing DingDingConfig `yaml:"dingding"`
+	Feishu   FeishuConfig   `yaml:"feishu"`
+	WeCom    WeComConfig    `yaml:"wecom"`
+	Bark     BarkConfig     `yaml:"bark"`
+
+	ServerChan ServerChanConfig `yaml:"serverchan"`
+	Pushover   PushoverConfig   `yaml:"pushover"`
+	Gotify     GotifyConfig     `yaml:"gotify"`
+	Teams      TeamsConfig      `yaml:"teams"`
+
+	// Language of the notification text: zh-CN (default) or en
+	Language string `yaml:"language"`
+
+	// Directory holding the email templates (default "templates"). Only set in the file,
+	// so email.template can't be pointed at arbitrary files through the settings API.
+	TemplateDir string `yaml:"template_dir"`
+
+	// Channel type (e.g. "telegram") that receives system health alerts instead of
+	// domain alerts; system alerts are only logged when empty
+	SystemChannel string `yaml:"system_channel"`
+
+	// Number of channels an alert is sent to in parallel (default 1 = sequential)
+	Concurrency int `yaml:"concurrency"`
+
+	// Retries per channel before a notification is recorded as failed (default 3, -1 disables),
+	// starting after retry_delay (default 1s) and doubling each attempt
+	MaxRetries int    `yaml:"max_retries"`
+	RetryDelay string `yaml:"retry_delay"`
+
+	// Per-threshold severities and messages, matched against the triggered alert day
+	Thresholds []ThresholdConfig `yaml:"thresholds"`
+}
+
+// ThresholdConfig maps an alert threshold to a severity and message
+type ThresholdConfig struct {
+	Days     int    `yaml:"days"`
+	Severity string `yaml:"severity"` // info/warning/critical
+	Message  string `yaml:"message"`  // text/template, e.g. "{{.Domain}} 将于 {{.ExpiryDate}} 到期"
 }
 
 // EmailConfig represents email notification configuration
@@ -60,12 +236,20 @@ type EmailConfig struct {
 	From     string   `yaml:"from"`
 	Password string   `yaml:"password"`
 	To       []string `yaml:"to"`
+	UseSSL   bool     `yaml:"use_ssl"`  // Implicit TLS (SMTPS); always used on port 465
+	Format   string   `yaml:"format"`   // text (default) or html (multipart/alternative)
+	Template string   `yaml:"template"` // Optional html/template file in template_dir overriding the built-in HTML email
 }
 
 // WebhookConfig represents webhook notification configuration
 type WebhookConfig struct {
-	Enabled bool   `yaml:"enabled"`
-	URL     string `yaml:"url"`
+	Enabled  bool   `yaml:"enabled"`
+	URL      string `yaml:"url"`
+	Method   string `yaml:"method"`   // HTTP method (default POST)
+	Template string `yaml:"template"` // Optional text/template for the request body (default JSON payload when empty)
+
+	Headers map[string]string `yaml:"headers"` // Extra request headers, e.g. a static Authorization header
+	Secret  string            `yaml:"secret"`  // Signs the body with HMAC-SHA256 in X-Jiankong-Signature (optional)
 }
 
 // TelegramConfig represents Telegram notification configuration
@@ -77,13 +261,75 @@ type TelegramConfig struct {
 
 // DingDingConfig represents DingTalk notification configuration
 type DingDingConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Webhook    string `yaml:"webhook"`
+	Secret     string `yaml:"secret"`
+	MaxRetries int    `yaml:"max_retries"` // Retries on rate limiting and transient errors (default 3, -1 disables)
+	RetryDelay string `yaml:"retry_delay"` // Initial delay between retries, doubled each attempt (default 3s)
+}
+
+// FeishuConfig represents Feishu/Lark bot notification configuration
+type FeishuConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	Webhook string `yaml:"webhook"`
-	Secret  string `yaml:"secret"`
+	Secret  string `yaml:"secret"` // Signature verification secret (optional)
+}
+
+// WeComConfig represents Enterprise WeChat (WeCom) group robot configuration
+type WeComConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// BarkConfig represents Bark (iOS push) notification configuration
+type BarkConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	ServerURL string `yaml:"server_url"` // Bark server (default https://api.day.app)
+	DeviceKey string `yaml:"device_key"`
+	Group     string `yaml:"group"` // Notification group on the device (optional)
+	Sound     string `yaml:"sound"` // Notification sound, e.g. alarm (optional)
+}
+
+// ServerChanConfig represents ServerChan (Server酱) WeChat push configuration
+type ServerChanConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	SendKey string `yaml:"send_key"`
 }
 
-// LoadConfig loads configuration from a YAML file
+// PushoverConfig represents Pushover notification configuration
+type PushoverConfig struct {
+	Enabled  bool   `yaml:"enabled"`
+	Token    string `yaml:"token"`    // Application API token
+	User     string `yaml:"user"`     // User or group key
+	Priority int    `yaml:"priority"` // Priority of non-urgent alerts, -2 to 1 (default 0); urgent ones use 2 (emergency)
+}
+
+// GotifyConfig represents Gotify notification configuration
+type GotifyConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	ServerURL string `yaml:"server_url"` // e.g. https://gotify.example.com
+	AppToken  string `yaml:"app_token"`  // Token of the Gotify application
+	Priority  int    `yaml:"priority"`   // Priority of informational alerts, 0-10 (default 0); raised to 5 for warnings and 8 for urgent alerts
+}
+
+// TeamsConfig represents Microsoft Teams incoming webhook configuration
+type TeamsConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// LoadConfig loads configuration from a YAML file.
+// If APP_ENV is set, the matching profile is layered over the base file.
+// JIANKONG_* environment variables override both (see EnvName).
 func LoadConfig(path string) (*Config, error) {
+	return LoadConfigForEnv(path, os.Getenv("APP_ENV"))
+}
+
+// LoadConfigForEnv loads the base configuration and layers the profile for env over it.
+// For path "config/config.yaml" and env "prod" the profile is "config/config.prod.yaml".
+// Only keys present in the profile override the base values; lists are replaced as a whole.
+// Environment variables are applied last and take precedence over both files.
+func LoadConfigForEnv(path, env string) (*Config, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
@@ -94,5 +340,27 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, err
 	}
 
+	if env != "" {
+		profilePath := ProfilePath(path, env)
+		profileData, err := os.ReadFile(profilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s profile: %w", env, err)
+		}
+
+		if err := yaml.Unmarshal(profileData, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s profile: %w", env, err)
+		}
+	}
+
+	if err := ApplyEnv(&config); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
+
+// ProfilePath returns the path of the profile file for env next to the base config file
+func ProfilePath(path, env string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + env + ext
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variables that override config fields
+const EnvPrefix = "JIANKONG_"
+
+// envSections shortens the environment variable names of config sections.
+// The notification channels drop the NOTIFICATIONS_ level, e.g. JIANKONG_EMAIL_PASSWORD;
+// server.jwt_secret keeps its documented name JIANKONG_JWT_SECRET.
+var envSections = map[string]string{
+	"server.jwt_secret":        "JWT_SECRET",
+	"database":                 "DB",
+	"notifications.email":      "EMAIL",
+	"notifications.webhook":    "WEBHOOK",
+	"notifications.telegram":   "TELEGRAM",
+	"notifications.dingding":   "DINGDING",
+	"notifications.feishu":     "FEISHU",
+	"notifications.wecom":      "WECOM",
+	"notifications.bark":       "BARK",
+	"notifications.serverchan": "SERVERCHAN",
+	"notifications.pushover":   "PUSHOVER",
+	"notifications.gotify":     "GOTIFY",
+	"notifications.teams":      "TEAMS",
+}
+
+// EnvName returns the environment variable overriding the config field at the YAML path,
+// e.g. "database.password" -> JIANKONG_DB_PASSWORD and "server.port" -> JIANKONG_SERVER_PORT
+func EnvName(path string) string {
+	name := path
+	for section, short := range envSections {
+		if path == section || strings.HasPrefix(path, section+".") {
+			name = short + strings.TrimPrefix(path, section)
+			break
+		}
+	}
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
+
+// ApplyEnv overrides config fields with the environment variables named by EnvName.
+// Lists are comma separated; maps and lists of sections can only be set in the file.
+func ApplyEnv(cfg *Config) error {
+	return applyEnv(reflect.ValueOf(cfg).Elem(), "")
+}
+
+func applyEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name := EnvName(path)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(field, value); err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// setField parses value into a scalar or list field; other kinds are left unchanged
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		elemKind := field.Type().Elem().Kind()
+		if elemKind != reflect.String && elemKind != reflect.Int {
+			return nil
+		}
+		list := reflect.MakeSlice(field.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item == "" {
+				continue
+			}
+			elem := reflect.New(field.Type().Elem()).Elem()
+			if err := setField(elem, item); err != nil {
+				return err
+			}
+			list = reflect.Append(list, elem)
+		}
+		field.Set(list)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := map[string]string{
+		"server.port":                      "JIANKONG_SERVER_PORT",
+		"server.jwt_secret":                "JIANKONG_JWT_SECRET",
+		"database.password":                "JIANKONG_DB_PASSWORD",
+		"notifications.email.password":     "JIANKONG_EMAIL_PASSWORD",
+		"notifications.telegram.bot_token": "JIANKONG_TELEGRAM_BOT_TOKEN",
+		"notifications.language":           "JIANKONG_NOTIFICATIONS_LANGUAGE",
+		"monitor.alert_days":               "JIANKONG_MONITOR_ALERT_DAYS",
+	}
+	for path, want := range tests {
+		if got := EnvName(path); got != want {
+			t.Errorf("EnvName(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+// writeConfig writes a config file into a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigEnvOverrides(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  port: "8080"
+  jwt_secret: file-secret
+database:
+  type: mysql
+  password: file-password
+monitor:
+  alert_days: [30, 7, 1]
+notifications:
+  email:
+    smtp_host: smtp.example.com
+    password: file-password
+  telegram:
+    bot_token: file-token
+`)
+	t.Setenv("JIANKONG_SERVER_PORT", "9090")
+	t.Setenv("JIANKONG_JWT_SECRET", "env-secret")
+	t.Setenv("JIANKONG_DB_PASSWORD", "env-db-password")
+	t.Setenv("JIANKONG_EMAIL_PASSWORD", "env-email-password")
+	t.Setenv("JIANKONG_TELEGRAM_BOT_TOKEN", "env-token")
+	t.Setenv("JIANKONG_MONITOR_ALERT_DAYS", "14, 3")
+	t.Setenv("JIANKONG_MONITOR_DIGEST_MODE", "true")
+
+	cfg, err := LoadConfigForEnv(path, "")
+	if err != nil {
+		t.Fatalf("LoadConfigForEnv: %v", err)
+	}
+
+	if cfg.Server.Port != "9090" {
+		t.Errorf("server.port = %q, want 9090", cfg.Server.Port)
+	}
+	if cfg.Server.JWTSecret != "env-secret" {
+		t.Errorf("server.jwt_secret = %q, want env-secret", cfg.Server.JWTSecret)
+	}
+	if cfg.Database.Password != "env-db-password" {
+		t.Errorf("database.password = %q, want env-db-password", cfg.Database.Password)
+	}
+	if cfg.Notifications.Email.Password != "env-email-password" {
+		t.Errorf("email.password = %q, want env-email-password", cfg.Notifications.Email.Password)
+	}
+	if cfg.Notifications.Telegram.BotToken != "env-token" {
+		t.Errorf("telegram.bot_token = %q, want env-token", cfg.Notifications.Telegram.BotToken)
+	}
+	if !reflect.DeepEqual(cfg.Monitor.AlertDays, []int{14, 3}) {
+		t.Errorf("monitor.alert_days = %v, want [14 3]", cfg.Monitor.AlertDays)
+	}
+	if !cfg.Monitor.DigestMode {
+		t.Error("monitor.digest_mode = false, want true")
+	}
+
+	// Fields without an environment variable keep the file values
+	if cfg.Database.Type != "mysql" {
+		t.Errorf("database.type = %q, want mysql", cfg.Database.Type)
+	}
+	if cfg.Notifications.Email.SMTPHost != "smtp.example.com" {
+		t.Errorf("email.smtp_host = %q, want smtp.example.com", cfg.Notifications.Email.SMTPHost)
+	}
+}
+
+func TestLoadConfigEnvInvalid(t *testing.T) {
+	path := writeConfig(t, "database:\n  port: 3306\n")
+	t.Setenv("JIANKONG_DB_PORT", "mysql")
+
+	if _, err := LoadConfigForEnv(path, ""); err == nil {
+		t.Error("LoadConfigForEnv succeeded with a non-numeric JIANKONG_DB_PORT")
+	}
+}
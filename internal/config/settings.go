@@ -0,0 +1,361 @@
+package config
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MaskedValue replaces secrets in exported configuration
+const MaskedValue = "******"
+
+// ApplySettings overrides configuration values with settings stored in the database
+func ApplySettings(cfg *Config, settings map[string]string) {
+	// Override monitor settings
+	if val, ok := settings["monitor.check_interval"]; ok && val != "" {
+		cfg.Monitor.CheckInterval = val
+	}
+	if val, ok := settings["monitor.alert_days"]; ok && val != "" {
+		// Parse comma-separated days
+		days := []int{}
+		for _, d := range strings.Split(val, ",") {
+			if day, err := strconv.Atoi(strings.TrimSpace(d)); err == nil {
+				days = append(days, day)
+			}
+		}
+		if len(days) > 0 {
+			cfg.Monitor.AlertDays = days
+		}
+	}
+
+	// Override notification routing
+	if val, ok := settings["notifications.system_channel"]; ok {
+		cfg.Notifications.SystemChannel = val
+	}
+	if val, ok := settings["notifications.language"]; ok && val != "" {
+		cfg.Notifications.Language = val
+	}
+
+	// Override email settings
+	if val, ok := settings["email.enabled"]; ok {
+		cfg.Notifications.Email.Enabled = val == "true"
+	}
+	if val, ok := settings["email.smtp_host"]; ok {
+		cfg.Notifications.Email.SMTPHost = val
+	}
+	if val, ok := settings["email.smtp_port"]; ok {
+		if port, err := strconv.Atoi(val); err == nil {
+			cfg.Notifications.Email.SMTPPort = port
+		}
+	}
+	if val, ok := settings["email.from"]; ok {
+		cfg.Notifications.Email.From = val
+	}
+	if val, ok := settings["email.password"]; ok {
+		cfg.Notifications.Email.Password = val
+	}
+	if val, ok := settings["email.use_ssl"]; ok {
+		cfg.Notifications.Email.UseSSL = val == "true"
+	}
+	if val, ok := settings["email.format"]; ok {
+		cfg.Notifications.Email.Format = val
+	}
+	if val, ok := settings["email.template"]; ok {
+		cfg.Notifications.Email.Template = val
+	}
+	if val, ok := settings["email.to"]; ok && val != "" {
+		cfg.Notifications.Email.To = strings.Split(val, ",")
+	}
+
+	// Override webhook settings
+	if val, ok := settings["webhook.enabled"]; ok {
+		cfg.Notifications.Webhook.Enabled = val == "true"
+	}
+	if val, ok := settings["webhook.url"]; ok {
+		cfg.Notifications.Webhook.URL = val
+	}
+	if val, ok := settings["webhook.method"]; ok {
+		cfg.Notifications.Webhook.Method = val
+	}
+	if val, ok := settings["webhook.template"]; ok {
+		cfg.Notifications.Webhook.Template = val
+	}
+	if val, ok := settings["webhook.headers"]; ok {
+		headers := map[string]string{}
+		if val == "" || json.Unmarshal([]byte(val), &headers) == nil {
+			cfg.Notifications.Webhook.Headers = headers
+		}
+	}
+	if val, ok := settings["webhook.secret"]; ok {
+		cfg.Notifications.Webhook.Secret = val
+	}
+
+	// Override telegram settings
+	if val, ok := settings["telegram.enabled"]; ok {
+		cfg.Notifications.Telegram.Enabled = val == "true"
+	}
+	if val, ok := settings["telegram.bot_token"]; ok {
+		cfg.Notifications.Telegram.BotToken = val
+	}
+	if val, ok := settings["telegram.chat_id"]; ok {
+		cfg.Notifications.Telegram.ChatID = val
+	}
+
+	// Override dingding settings
+	if val, ok := settings["dingding.enabled"]; ok {
+		cfg.Notifications.DingDing.Enabled = val == "true"
+	}
+	if val, ok := settings["dingding.webhook"]; ok {
+		cfg.Notifications.DingDing.Webhook = val
+	}
+	if val, ok := settings["dingding.secret"]; ok {
+		cfg.Notifications.DingDing.Secret = val
+	}
+
+	// Override feishu settings
+	if val, ok := settings["feishu.enabled"]; ok {
+		cfg.Notifications.Feishu.Enabled = val == "true"
+	}
+	if val, ok := settings["feishu.webhook"]; ok {
+		cfg.Notifications.Feishu.Webhook = val
+	}
+	if val, ok := settings["feishu.secret"]; ok {
+		cfg.Notifications.Feishu.Secret = val
+	}
+
+	// Override wecom settings
+	if val, ok := settings["wecom.enabled"]; ok {
+		cfg.Notifications.WeCom.Enabled = val == "true"
+	}
+	if val, ok := settings["wecom.webhook_url"]; ok {
+		cfg.Notifications.WeCom.WebhookURL = val
+	}
+
+	// Override bark settings
+	if val, ok := settings["bark.enabled"]; ok {
+		cfg.Notifications.Bark.Enabled = val == "true"
+	}
+	if val, ok := settings["bark.server_url"]; ok {
+		cfg.Notifications.Bark.ServerURL = val
+	}
+	if val, ok := settings["bark.device_key"]; ok {
+		cfg.Notifications.Bark.DeviceKey = val
+	}
+	if val, ok := settings["bark.group"]; ok {
+		cfg.Notifications.Bark.Group = val
+	}
+	if val, ok := settings["bark.sound"]; ok {
+		cfg.Notifications.Bark.Sound = val
+	}
+
+	// Override serverchan settings
+	if val, ok := settings["serverchan.enabled"]; ok {
+		cfg.Notifications.ServerChan.Enabled = val == "true"
+	}
+	if val, ok := settings["serverchan.send_key"]; ok {
+		cfg.Notifications.ServerChan.SendKey = val
+	}
+
+	// Override pushover settings
+	if val, ok := settings["pushover.enabled"]; ok {
+		cfg.Notifications.Pushover.Enabled = val == "true"
+	}
+	if val, ok := settings["pushover.token"]; ok {
+		cfg.Notifications.Pushover.Token = val
+	}
+	if val, ok := settings["pushover.user"]; ok {
+		cfg.Notifications.Pushover.User = val
+	}
+	if val, ok := settings["pushover.priority"]; ok {
+		if priority, err := strconv.Atoi(val); err == nil {
+			cfg.Notifications.Pushover.Priority = priority
+		}
+	}
+
+	// Override gotify settings
+	if val, ok := settings["gotify.enabled"]; ok {
+		cfg.Notifications.Gotify.Enabled = val == "true"
+	}
+	if val, ok := settings["gotify.server_url"]; ok {
+		cfg.Notifications.Gotify.ServerURL = val
+	}
+	if val, ok := settings["gotify.app_token"]; ok {
+		cfg.Notifications.Gotify.AppToken = val
+	}
+	if val, ok := settings["gotify.priority"]; ok {
+		if priority, err := strconv.Atoi(val); err == nil {
+			cfg.Notifications.Gotify.Priority = priority
+		}
+	}
+
+	// Override teams settings
+	if val, ok := settings["teams.enabled"]; ok {
+		cfg.Notifications.Teams.Enabled = val == "true"
+	}
+	if val, ok := settings["teams.webhook_url"]; ok {
+		cfg.Notifications.Teams.WebhookURL = val
+	}
+}
+
+// SettingsFromConfig converts the database-backed part of the configuration into settings.
+// It is the inverse of ApplySettings.
+func SettingsFromConfig(cfg *Config) map[string]string {
+	days := make([]string, 0, len(cfg.Monitor.AlertDays))
+	for _, day := range cfg.Monitor.AlertDays {
+		days = append(days, strconv.Itoa(day))
+	}
+
+	return map[string]string{
+		"monitor.check_interval": cfg.Monitor.CheckInterval,
+		"monitor.alert_days":     strings.Join(days, ","),
+
+		"notifications.system_channel": cfg.Notifications.SystemChannel,
+		"notifications.language":       cfg.Notifications.Language,
+
+		"email.enabled":   strconv.FormatBool(cfg.Notifications.Email.Enabled),
+		"email.smtp_host": cfg.Notifications.Email.SMTPHost,
+		"email.smtp_port": strconv.Itoa(cfg.Notifications.Email.SMTPPort),
+		"email.from":      cfg.Notifications.Email.From,
+		"email.password":  cfg.Notifications.Email.Password,
+		"email.to":        strings.Join(cfg.Notifications.Email.To, ","),
+		"email.use_ssl":   strconv.FormatBool(cfg.Notifications.Email.UseSSL),
+		"email.format":    cfg.Notifications.Email.Format,
+		"email.template":  cfg.Notifications.Email.Template,
+
+		"webhook.enabled":  strconv.FormatBool(cfg.Notifications.Webhook.Enabled),
+		"webhook.url":      cfg.Notifications.Webhook.URL,
+		"webhook.method":   cfg.Notifications.Webhook.Method,
+		"webhook.template": cfg.Notifications.Webhook.Template,
+		"webhook.headers":  webhookHeaders(cfg.Notifications.Webhook.Headers),
+		"webhook.secret":   cfg.Notifications.Webhook.Secret,
+
+		"telegram.enabled":   strconv.FormatBool(cfg.Notifications.Telegram.Enabled),
+		"telegram.bot_token": cfg.Notifications.Telegram.BotToken,
+		"telegram.chat_id":   cfg.Notifications.Telegram.ChatID,
+
+		"dingding.enabled": strconv.FormatBool(cfg.Notifications.DingDing.Enabled),
+		"dingding.webhook": cfg.Notifications.DingDing.Webhook,
+		"dingding.secret":  cfg.Notifications.DingDing.Secret,
+
+		"feishu.enabled": strconv.FormatBool(cfg.Notifications.Feishu.Enabled),
+		"feishu.webhook": cfg.Notifications.Feishu.Webhook,
+		"feishu.secret":  cfg.Notifications.Feishu.Secret,
+
+		"wecom.enabled":     strconv.FormatBool(cfg.Notifications.WeCom.Enabled),
+		"wecom.webhook_url": cfg.Notifications.WeCom.WebhookURL,
+
+		"bark.enabled":    strconv.FormatBool(cfg.Notifications.Bark.Enabled),
+		"bark.server_url": cfg.Notifications.Bark.ServerURL,
+		"bark.device_key": cfg.Notifications.Bark.DeviceKey,
+		"bark.group":      cfg.Notifications.Bark.Group,
+		"bark.sound":      cfg.Notifications.Bark.Sound,
+
+		"serverchan.enabled":  strconv.FormatBool(cfg.Notifications.ServerChan.Enabled),
+		"serverchan.send_key": cfg.Notifications.ServerChan.SendKey,
+
+		"pushover.enabled":  strconv.FormatBool(cfg.Notifications.Pushover.Enabled),
+		"pushover.token":    cfg.Notifications.Pushover.Token,
+		"pushover.user":     cfg.Notifications.Pushover.User,
+		"pushover.priority": strconv.Itoa(cfg.Notifications.Pushover.Priority),
+
+		"gotify.enabled":    strconv.FormatBool(cfg.Notifications.Gotify.Enabled),
+		"gotify.server_url": cfg.Notifications.Gotify.ServerURL,
+		"gotify.app_token":  cfg.Notifications.Gotify.AppToken,
+		"gotify.priority":   strconv.Itoa(cfg.Notifications.Gotify.Priority),
+
+		"teams.enabled":     strconv.FormatBool(cfg.Notifications.Teams.Enabled),
+		"teams.webhook_url": cfg.Notifications.Teams.WebhookURL,
+	}
+}
+
+// webhookHeaders encodes the webhook headers as a JSON object setting
+func webhookHeaders(headers map[string]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// secretFields maps the keys of the credentials to their fields in cfg. Notification
+// credentials use their setting keys; the others can only be set in the file and use
+// their YAML paths.
+func secretFields(cfg *Config) map[string]*string {
+	return map[string]*string{
+		"server.jwt_secret":        &cfg.Server.JWTSecret,
+		"database.password":        &cfg.Database.Password,
+		"registrar_webhooks.token": &cfg.RegistrarWebhooks.Token,
+		"metrics.token":            &cfg.Metrics.Token,
+		"email.password":           &cfg.Notifications.Email.Password,
+		"webhook.secret":           &cfg.Notifications.Webhook.Secret,
+		"telegram.bot_token":       &cfg.Notifications.Telegram.BotToken,
+		"dingding.secret":          &cfg.Notifications.DingDing.Secret,
+		"feishu.secret":            &cfg.Notifications.Feishu.Secret,
+		"bark.device_key":          &cfg.Notifications.Bark.DeviceKey,
+		"serverchan.send_key":      &cfg.Notifications.ServerChan.SendKey,
+		"pushover.token":           &cfg.Notifications.Pushover.Token,
+		"gotify.app_token":         &cfg.Notifications.Gotify.AppToken,
+		// Webhook URLs embed their access token or signature
+		"dingding.webhook":  &cfg.Notifications.DingDing.Webhook,
+		"feishu.webhook":    &cfg.Notifications.Feishu.Webhook,
+		"wecom.webhook_url": &cfg.Notifications.WeCom.WebhookURL,
+		"teams.webhook_url": &cfg.Notifications.Teams.WebhookURL,
+	}
+}
+
+// secretSettings are the settings holding credentials, derived from secretFields
+var secretSettings = func() map[string]bool {
+	secrets := map[string]bool{
+		"webhook.headers": true, // May carry an Authorization header
+	}
+	for key := range secretFields(&Config{}) {
+		secrets[key] = true
+	}
+	return secrets
+}()
+
+// IsSecretSetting reports whether a setting holds a credential that must not be shown
+func IsSecretSetting(key string) bool {
+	return secretSettings[key]
+}
+
+// MaskSecrets replaces all non-empty secrets in the configuration with MaskedValue,
+// including the values of the webhook headers
+func MaskSecrets(cfg *Config) {
+	for _, secret := range secretFields(cfg) {
+		if *secret != "" {
+			*secret = MaskedValue
+		}
+	}
+
+	if headers := cfg.Notifications.Webhook.Headers; len(headers) > 0 {
+		masked := make(map[string]string, len(headers))
+		for name, value := range headers {
+			if value != "" {
+				value = MaskedValue
+			}
+			masked[name] = value
+		}
+		cfg.Notifications.Webhook.Headers = masked
+	}
+}
+
+// Clone returns a deep copy of the configuration
+func (c *Config) Clone() (*Config, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone Config
+	if err := yaml.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+
+	return &clone, nil
+}
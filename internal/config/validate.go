@@ -0,0 +1,187 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/robfig/cron/v3"
+)
+
+// settingValidators check the value of a setting; settings without one accept any value.
+// Empty values fall back to the config file and are accepted where optional.
+var settingValidators = map[string]func(string) error{
+	"monitor.check_interval": optional(validateCron),
+	"monitor.alert_days":     optional(validateAlertDays),
+
+	"notifications.language": optional(oneOf("zh-CN", "en")),
+
+	"email.enabled":   validateBool,
+	"email.smtp_port": optional(validatePort),
+	"email.from":      optional(validateAddress),
+	"email.to":        optional(validateAddressList),
+	"email.use_ssl":   validateBool,
+	"email.format":    optional(oneOf("text", "html")),
+	"email.template":  optional(validateTemplateName),
+
+	"webhook.enabled": validateBool,
+	"webhook.url":     optional(validateURL),
+	"webhook.method":  optional(oneOf("POST", "PUT")),
+	"webhook.headers": optional(validateHeaders),
+
+	"telegram.enabled": validateBool,
+
+	"dingding.enabled": validateBool,
+	"dingding.webhook": optional(validateURL),
+
+	"feishu.enabled": validateBool,
+	"feishu.webhook": optional(validateURL),
+
+	"wecom.enabled":     validateBool,
+	"wecom.webhook_url": optional(validateURL),
+
+	"bark.enabled":    validateBool,
+	"bark.server_url": optional(validateURL),
+
+	"serverchan.enabled": validateBool,
+
+	"pushover.enabled":  validateBool,
+	"pushover.priority": optional(oneOf("-2", "-1", "0", "1")),
+
+	"gotify.enabled":    validateBool,
+	"gotify.server_url": optional(validateURL),
+	"gotify.priority":   optional(oneOf("0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10")),
+
+	"teams.enabled":     validateBool,
+	"teams.webhook_url": optional(validateURL),
+}
+
+// ValidateSettings checks settings before they are stored and returns an error message
+// per invalid key (nil when all are valid). Unknown keys are rejected.
+func ValidateSettings(settings map[string]string) map[string]string {
+	known := SettingsFromConfig(&Config{})
+
+	errs := make(map[string]string)
+	for key, value := range settings {
+		if _, ok := known[key]; !ok {
+			errs[key] = "unknown setting"
+			continue
+		}
+		if validate, ok := settingValidators[key]; ok {
+			if err := validate(value); err != nil {
+				errs[key] = err.Error()
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// optional skips the validator for empty values
+func optional(validate func(string) error) func(string) error {
+	return func(value string) error {
+		if value == "" {
+			return nil
+		}
+		return validate(value)
+	}
+}
+
+// oneOf accepts only the given values
+func oneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+// validateCron checks a standard five-field cron expression
+func validateCron(value string) error {
+	if _, err := cron.ParseStandard(value); err != nil {
+		return fmt.Errorf("invalid cron expression: %v", err)
+	}
+	return nil
+}
+
+// validateAlertDays checks a comma separated list of days
+func validateAlertDays(value string) error {
+	for _, d := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(strings.TrimSpace(d))
+		if err != nil || day < 0 {
+			return fmt.Errorf("must be a comma separated list of days, e.g. 30,15,7,3,1")
+		}
+	}
+	return nil
+}
+
+// validateBool accepts "true" and "false"
+func validateBool(value string) error {
+	if value != "true" && value != "false" {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+// validatePort checks a TCP port number
+func validatePort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("must be a port number between 1 and 65535")
+	}
+	return nil
+}
+
+// validateURL checks an absolute http(s) URL
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("must be an http(s) URL")
+	}
+	return nil
+}
+
+// validateAddress checks an email address
+func validateAddress(value string) error {
+	if _, err := mail.ParseAddress(value); err != nil {
+		return fmt.Errorf("invalid email address: %v", err)
+	}
+	return nil
+}
+
+// validateAddressList checks a comma separated list of email addresses
+func validateAddressList(value string) error {
+	for _, address := range strings.Split(value, ",") {
+		if err := validateAddress(strings.TrimSpace(address)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateTemplateName checks a file name relative to the template directory
+func validateTemplateName(value string) error {
+	if !filepath.IsLocal(value) {
+		return fmt.Errorf("must be a file name inside notifications.template_dir")
+	}
+	return nil
+}
+
+// validateHeaders checks a JSON object of header names to values
+func validateHeaders(value string) error {
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		return fmt.Errorf(`must be a JSON object such as {"X-Api-Key": "..."}`)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import "testing"
+
+func TestValidateSettings(t *testing.T) {
+	tests := []struct {
+		key, value string
+		valid      bool
+	}{
+		{"monitor.check_interval", "0 9 * * *", true},
+		{"monitor.check_interval", "every day", false},
+		{"monitor.check_interval", "61 * * * *", false},
+		{"monitor.check_interval", "", true},
+		{"monitor.alert_days", "30,7,1", true},
+		{"monitor.alert_days", "30, 7, 1", true},
+		{"monitor.alert_days", "30,x", false},
+		{"monitor.alert_days", "30,-1", false},
+		{"notifications.language", "en", true},
+		{"notifications.language", "fr", false},
+		{"email.enabled", "true", true},
+		{"email.enabled", "false", true},
+		{"email.enabled", "yes", false},
+		{"email.enabled", "", false},
+		{"email.smtp_port", "587", true},
+		{"email.smtp_port", "", true},
+		{"email.smtp_port", "0", false},
+		{"email.smtp_port", "70000", false},
+		{"email.smtp_port", "smtp", false},
+		{"email.from", "Monitor <monitor@example.com>", true},
+		{"email.from", "monitor", false},
+		{"email.to", "a@example.com, b@example.com", true},
+		{"email.to", "a@example.com,b", false},
+		{"webhook.url", "https://example.com/hook", true},
+		{"webhook.url", "ftp://example.com", false},
+		{"webhook.url", "https://", false},
+		{"webhook.url", "example.com/hook", false},
+		{"webhook.method", "PUT", true},
+		{"webhook.method", "GET", false},
+		{"webhook.headers", `{"X-Api-Key": "k"}`, true},
+		{"webhook.headers", `["X-Api-Key"]`, false},
+		{"webhook.headers", "X-Api-Key: k", false},
+		{"telegram.bot_token", "anything", true},
+		{"email.template", "alert.html", true},
+		{"email.template", "custom/alert.html", true},
+		{"email.template", "../config/config.yaml", false},
+		{"email.template", "/etc/passwd", false},
+		{"email.template", "", true},
+		{"no.such_setting", "x", false},
+	}
+	for _, tt := range tests {
+		errs := ValidateSettings(map[string]string{tt.key: tt.value})
+		if valid := errs == nil; valid != tt.valid {
+			t.Errorf("ValidateSettings(%s=%q) = %v, want valid=%v", tt.key, tt.value, errs, tt.valid)
+		}
+	}
+}
+
+func TestValidateSettingsReportsEachField(t *testing.T) {
+	errs := ValidateSettings(map[string]string{
+		"monitor.check_interval": "every 5 min",
+		"email.smtp_port":        "smtp",
+		"webhook.url":            "https://example.com/hook",
+	})
+	if len(errs) != 2 || errs["monitor.check_interval"] == "" || errs["email.smtp_port"] == "" {
+		t.Errorf("errors = %v, want one per invalid field", errs)
+	}
+}
@@ -1,11 +1,21 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"domain-monitor/internal/config"
 	"domain-monitor/internal/models"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	_ "modernc.org/sqlite" // Pure Go SQLite driver
@@ -21,7 +31,7 @@ func InitDB(cfg *config.DatabaseConfig) error {
 	switch cfg.Type {
 	case "sqlite":
 		// Use pure Go SQLite driver (modernc.org/sqlite)
-		sqlDB, err := sql.Open("sqlite", cfg.Path)
+		sqlDB, err := sql.Open("sqlite", sqliteDSN(cfg))
 		if err != nil {
 			return fmt.Errorf("failed to open database: %w", err)
 		}
@@ -32,9 +42,16 @@ func InitDB(cfg *config.DatabaseConfig) error {
 		if err != nil {
 			return fmt.Errorf("failed to initialize GORM: %w", err)
 		}
-	// Add support for MySQL and PostgreSQL in the future
-	// case "mysql":
-	// case "postgres":
+	case "mysql":
+		DB, err = gorm.Open(mysql.Open(mysqlDSN(cfg)), &gorm.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+	case "postgres":
+		DB, err = gorm.Open(postgres.Open(postgresDSN(cfg)), &gorm.Config{})
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
 	default:
 		return fmt.Errorf("unsupported database type: %s", cfg.Type)
 	}
@@ -43,20 +60,287 @@ func InitDB(cfg *config.DatabaseConfig) error {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := configurePool(cfg); err != nil {
+		return err
+	}
+
+	// Domain names were unique on their own before soft deletes, then briefly together
+	// with deleted_at, which didn't stop duplicate live domains; see domainNameIndex
+	for _, index := range []string{"idx_domains_name", "idx_domain_name_deleted"} {
+		if DB.Migrator().HasIndex(&models.Domain{}, index) {
+			if err := DB.Migrator().DropIndex(&models.Domain{}, index); err != nil {
+				return fmt.Errorf("failed to drop domain name index: %w", err)
+			}
+		}
+	}
+
 	// Auto migrate the schema
 	if err := DB.AutoMigrate(
 		&models.Domain{},
 		&models.Notification{},
+		&models.DomainCheckHistory{},
 		&models.Setting{},
 		&models.User{},
+		&models.APIKey{},
+		&models.WhoisSnapshot{},
+		&models.DomainGroup{},
+		&models.AlertPolicy{},
+		&models.AuditLog{},
 	); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := createDomainNameIndex(cfg.Type); err != nil {
+		return fmt.Errorf("failed to create domain name index (remove duplicate domains first): %w", err)
+	}
+
+	if err := migrateTags(); err != nil {
+		return fmt.Errorf("failed to migrate domain tags: %w", err)
+	}
+
+	if err := migrateZeroDates(); err != nil {
+		return fmt.Errorf("failed to migrate unknown dates: %w", err)
+	}
+
+	if err := migrateNotificationTypes(); err != nil {
+		return fmt.Errorf("failed to migrate notification types: %w", err)
+	}
+
+	return nil
+}
+
+// domainNameIndex makes domain names unique among the domains not in the trash, so a
+// trashed domain's name can be added again
+const domainNameIndex = "idx_domains_live_name"
+
+// createDomainNameIndex creates the unique index on the names of live domains. SQLite and
+// PostgreSQL support partial indexes; MySQL indexes a generated column that is NULL for
+// trashed domains, as NULLs never collide.
+func createDomainNameIndex(dbType string) error {
+	if dbType != "mysql" {
+		return DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + domainNameIndex + " ON domains (name) WHERE deleted_at IS NULL").Error
+	}
+
+	if !DB.Migrator().HasColumn(&models.Domain{}, "live_name") {
+		if err := DB.Exec("ALTER TABLE domains ADD COLUMN live_name VARCHAR(255) AS (IF(deleted_at IS NULL, name, NULL)) STORED").Error; err != nil {
+			return err
+		}
+	}
+	if DB.Migrator().HasIndex(&models.Domain{}, domainNameIndex) {
+		return nil
+	}
+	return DB.Exec("CREATE UNIQUE INDEX " + domainNameIndex + " ON domains (live_name)").Error
+}
+
+// migrateTags converts comma separated domain tags written by older versions to JSON arrays
+func migrateTags() error {
+	var domains []models.Domain
+	if err := DB.Select("id", "tags").Where("tags <> ? AND tags NOT LIKE ?", "", "[%").Find(&domains).Error; err != nil {
+		return err
+	}
+
+	for _, domain := range domains {
+		domain.SetTags(domain.TagList())
+		if err := DB.Model(&models.Domain{}).Where("id = ?", domain.ID).UpdateColumn("tags", domain.Tags).Error; err != nil {
+			return err
+		}
+	}
+	if len(domains) > 0 {
+		slog.Info("Converted domain tags to JSON arrays", "count", len(domains))
+	}
+	return nil
+}
+
+// nullableDates lists the date columns per table that are NULL when unknown
+var nullableDates = map[string][]string{
+	"domains": {
+		"expiry_date", "created_date", "updated_date", "last_checked", "paused_until", "delete_requested_at",
+		"cert_expiry", "cert_checked", "dns_checked", "last_uptime_check",
+	},
+	"domain_check_histories": {"expiry_date"},
+	"api_keys":               {"last_used"},
+}
+
+// migrateZeroDates replaces the zero dates written by older versions for unknown dates
+// with NULL. MySQL rejects zero dates in strict mode; no real date precedes year 1000.
+func migrateZeroDates() error {
+	var converted int64
+	for table, columns := range nullableDates {
+		for _, column := range columns {
+			result := DB.Table(table).Where(column+" < ?", "1000-01-01").UpdateColumn(column, nil)
+			if result.Error != nil {
+				return result.Error
+			}
+			converted += result.RowsAffected
+		}
+	}
+	if converted > 0 {
+		slog.Info("Replaced zero dates with NULL", "count", converted)
+	}
+	return nil
+}
+
+// legacyNotificationTypes maps the Go type names recorded by older versions to channel identifiers
+var legacyNotificationTypes = map[string]string{
+	"*services.EmailNotifier":    "email",
+	"*services.WebhookNotifier":  "webhook",
+	"*services.TelegramNotifier": "telegram",
+	"*services.DingDingNotifier": "dingding",
+	"*services.FeishuNotifier":   "feishu",
+	"*services.WeComNotifier":    "wecom",
+}
+
+// migrateNotificationTypes replaces the Go type names in the notification history with channel identifiers
+func migrateNotificationTypes() error {
+	var converted int64
+	for legacy, channel := range legacyNotificationTypes {
+		result := DB.Model(&models.Notification{}).Where("type = ?", legacy).UpdateColumn("type", channel)
+		if result.Error != nil {
+			return result.Error
+		}
+		converted += result.RowsAffected
+	}
+	if converted > 0 {
+		slog.Info("Converted notification types to channel identifiers", "count", converted)
+	}
+	return nil
+}
+
+// configurePool applies the connection pool settings
+func configurePool(cfg *config.DatabaseConfig) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %w", err)
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime != "" {
+		lifetime, err := time.ParseDuration(cfg.ConnMaxLifetime)
+		if err != nil {
+			return fmt.Errorf("invalid conn_max_lifetime %q: %w", cfg.ConnMaxLifetime, err)
+		}
+		sqlDB.SetConnMaxLifetime(lifetime)
+	}
+
 	return nil
 }
 
+// mysqlDSN builds the MySQL connection string. parseTime and loc make DATETIME
+// columns scan into time.Time in the server's local time zone.
+func mysqlDSN(cfg *config.DatabaseConfig) string {
+	host := cfg.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 3306
+	}
+	charset := cfg.Charset
+	if charset == "" {
+		charset = "utf8mb4"
+	}
+
+	mysqlCfg := mysqldriver.NewConfig()
+	mysqlCfg.User = cfg.User
+	mysqlCfg.Passwd = cfg.Password
+	mysqlCfg.Net = "tcp"
+	mysqlCfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	mysqlCfg.DBName = cfg.DBName
+	mysqlCfg.ParseTime = true
+	mysqlCfg.Loc = time.Local
+	mysqlCfg.Params = map[string]string{"charset": charset}
+
+	return mysqlCfg.FormatDSN()
+}
+
+// postgresDSN builds the PostgreSQL connection URL
+func postgresDSN(cfg *config.DatabaseConfig) string {
+	host := cfg.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	sslMode := cfg.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(cfg.User, cfg.Password),
+		Host:     net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:     "/" + cfg.DBName,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	return dsn.String()
+}
+
+// sqliteDSN builds the SQLite connection string with the configured pragmas.
+// Pragmas are passed through the DSN so every pooled connection applies them.
+func sqliteDSN(cfg *config.DatabaseConfig) string {
+	busyTimeout := cfg.BusyTimeout
+	if busyTimeout <= 0 {
+		busyTimeout = 5000
+	}
+
+	journalMode := cfg.JournalMode
+	if journalMode == "" {
+		journalMode = "WAL"
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout))
+	params.Add("_pragma", fmt.Sprintf("journal_mode(%s)", journalMode))
+	for _, pragma := range cfg.Pragmas {
+		if pragma = strings.TrimSpace(pragma); pragma != "" {
+			params.Add("_pragma", pragma)
+		}
+	}
+
+	separator := "?"
+	if strings.Contains(cfg.Path, "?") {
+		separator = "&"
+	}
+
+	return cfg.Path + separator + params.Encode()
+}
+
 // GetDB returns the database instance
 func GetDB() *gorm.DB {
 	return DB
 }
+
+// Close closes the database connections
+func Close() error {
+	if DB == nil {
+		return nil
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Ping checks that the database is reachable
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return ErrUnavailable
+	}
+
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
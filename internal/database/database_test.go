@@ -0,0 +1,128 @@
+package database
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+)
+
+func TestMySQLDSN(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         config.DatabaseConfig
+		wantAddr    string
+		wantCharset string
+	}{
+		{"defaults", config.DatabaseConfig{User: "monitor", Password: "p@ss:w/rd", DBName: "jiankong"}, "127.0.0.1:3306", "utf8mb4"},
+		{"custom", config.DatabaseConfig{Host: "db.internal", Port: 3307, User: "monitor", Password: "secret", DBName: "jiankong", Charset: "utf8"}, "db.internal:3307", "utf8"},
+		{"ipv6", config.DatabaseConfig{Host: "::1", User: "monitor", DBName: "jiankong"}, "[::1]:3306", "utf8mb4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn := mysqlDSN(&tt.cfg)
+			parsed, err := mysqldriver.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("ParseDSN(%q): %v", dsn, err)
+			}
+			if parsed.Addr != tt.wantAddr || parsed.Net != "tcp" {
+				t.Errorf("address = %s(%s), want tcp(%s)", parsed.Net, parsed.Addr, tt.wantAddr)
+			}
+			if parsed.User != tt.cfg.User || parsed.Passwd != tt.cfg.Password || parsed.DBName != tt.cfg.DBName {
+				t.Errorf("credentials = %s:%s/%s", parsed.User, parsed.Passwd, parsed.DBName)
+			}
+			if !parsed.ParseTime || parsed.Loc != time.Local {
+				t.Errorf("parseTime = %v, loc = %v; want true and Local", parsed.ParseTime, parsed.Loc)
+			}
+			if parsed.Params["charset"] != tt.wantCharset {
+				t.Errorf("charset = %q, want %q", parsed.Params["charset"], tt.wantCharset)
+			}
+		})
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.DatabaseConfig
+		wantHost string
+		wantSSL  string
+	}{
+		{"defaults", config.DatabaseConfig{User: "monitor", Password: "p@ss:w/rd", DBName: "jiankong"}, "127.0.0.1:5432", "disable"},
+		{"custom", config.DatabaseConfig{Host: "db.internal", Port: 5433, User: "monitor", Password: "secret", DBName: "jiankong", SSLMode: "verify-full"}, "db.internal:5433", "verify-full"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn := postgresDSN(&tt.cfg)
+			parsed, err := url.Parse(dsn)
+			if err != nil {
+				t.Fatalf("parse %q: %v", dsn, err)
+			}
+			if parsed.Scheme != "postgres" || parsed.Host != tt.wantHost || parsed.Path != "/"+tt.cfg.DBName {
+				t.Errorf("dsn = %q, want host %s and database %s", dsn, tt.wantHost, tt.cfg.DBName)
+			}
+			password, _ := parsed.User.Password()
+			if parsed.User.Username() != tt.cfg.User || password != tt.cfg.Password {
+				t.Errorf("credentials = %s:%s", parsed.User.Username(), password)
+			}
+			if got := parsed.Query().Get("sslmode"); got != tt.wantSSL {
+				t.Errorf("sslmode = %q, want %q", got, tt.wantSSL)
+			}
+		})
+	}
+}
+
+func TestMigrateTags(t *testing.T) {
+	if err := InitDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { Close() })
+
+	// Older versions stored comma separated tags; UpdateColumn skips the save hook
+	legacy := models.Domain{Name: "example.com"}
+	current := models.Domain{Name: "example.org", Tags: "client"}
+	for _, d := range []*models.Domain{&legacy, &current} {
+		if err := DB.Create(d).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	DB.Model(&legacy).UpdateColumn("tags", "prod, client")
+
+	if err := migrateTags(); err != nil {
+		t.Fatalf("migrateTags: %v", err)
+	}
+
+	for id, want := range map[uint]string{legacy.ID: `["prod","client"]`, current.ID: `["client"]`} {
+		var d models.Domain
+		DB.First(&d, id)
+		if d.Tags != want {
+			t.Errorf("%s tags = %s, want %s", d.Name, d.Tags, want)
+		}
+	}
+}
+
+func TestMigrateNotificationTypes(t *testing.T) {
+	if err := InitDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { Close() })
+
+	for _, typ := range []string{"*services.EmailNotifier", "*services.WeComNotifier", "teams"} {
+		DB.Create(&models.Notification{Type: typ})
+	}
+
+	if err := migrateNotificationTypes(); err != nil {
+		t.Fatalf("migrateNotificationTypes: %v", err)
+	}
+
+	var types []string
+	DB.Model(&models.Notification{}).Order("id").Pluck("type", &types)
+	if want := []string{"email", "wecom", "teams"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("types = %v, want %v", types, want)
+	}
+}
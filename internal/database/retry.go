@@ -0,0 +1,96 @@
+package database
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned when the database has not been initialized
+var ErrUnavailable = errors.New("database is not available")
+
+// Retry settings for transient database errors
+const (
+	retryAttempts  = 5
+	retryBaseDelay = 50 * time.Millisecond
+)
+
+// transientErrors lists error fragments that indicate a temporary failure
+var transientErrors = []string{
+	"database is locked",
+	"database table is locked",
+	"sqlite_busy",
+	"bad connection",
+	"connection refused",
+	"connection reset",
+	"broken pipe",
+	"deadlock found",
+	"lock wait timeout exceeded",
+	"deadlock detected",
+	"could not serialize access",
+}
+
+// IsTransient reports whether err looks like a temporary database failure
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, fragment := range transientErrors {
+		if strings.Contains(errMsg, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// duplicateKeyErrors lists error fragments of unique constraint violations
+var duplicateKeyErrors = []string{
+	"unique constraint failed",                       // SQLite
+	"duplicate entry",                                // MySQL
+	"duplicate key value violates unique constraint", // PostgreSQL
+}
+
+// IsDuplicateKey reports whether err is a unique constraint violation
+func IsDuplicateKey(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	for _, fragment := range duplicateKeyErrors {
+		if strings.Contains(errMsg, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithRetry runs op against the database, retrying transient failures with exponential backoff
+func WithRetry(op func(db *gorm.DB) error) error {
+	db := GetDB()
+	if db == nil {
+		return ErrUnavailable
+	}
+
+	delay := retryBaseDelay
+	for attempt := 1; ; attempt++ {
+		err := op(db)
+		if err == nil || !IsTransient(err) || attempt >= retryAttempts {
+			return err
+		}
+
+		slog.Warn("Transient database error, retrying", "attempt", attempt, "max_attempts", retryAttempts, "delay", delay, "error", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
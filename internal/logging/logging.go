@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// NewHandler creates a slog handler writing to w with the configured level and format
+func NewHandler(w io.Writer, cfg *config.LogConfig) (slog.Handler, error) {
+	var level slog.Level
+	if cfg.Level != "" {
+		if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		return contextHandler{slog.NewTextHandler(w, opts)}, nil
+	case "json":
+		return contextHandler{slog.NewJSONHandler(w, opts)}, nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (expected text or json)", cfg.Format)
+	}
+}
+
+// Setup makes the configured handler the default logger. Messages written with the
+// standard log package go through it at info level.
+func Setup(cfg *config.LogConfig) error {
+	handler, err := NewHandler(os.Stderr, cfg)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// requestIDKey is the context key holding the request ID
+type requestIDKey struct{}
+
+// WithRequestID returns a context carrying the request ID, which is added to records
+// logged with it (slog.InfoContext etc.)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID of the context, empty if none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// contextHandler adds the request ID of the context to each record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,90 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"domain-monitor/internal/config"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewHandlerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := NewHandler(&buf, &config.LogConfig{Level: "warn", Format: "text"})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	logger := slog.New(handler)
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.Warn("warn message", "domain", "example.com")
+	logger.Error("error message", "channel", "webhook")
+
+	out := buf.String()
+	for _, msg := range []string{"debug message", "info message"} {
+		if strings.Contains(out, msg) {
+			t.Errorf("%q logged below the warn level:\n%s", msg, out)
+		}
+	}
+	for _, want := range []string{"level=WARN", `msg="warn message"`, "domain=example.com", "level=ERROR", "channel=webhook"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestNewHandlerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := NewHandler(&buf, &config.LogConfig{Level: "debug", Format: "json"})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	ctx := WithRequestID(context.Background(), "req-1")
+	slog.New(handler).DebugContext(ctx, "Domain checked", "domain", "example.com", "days_remaining", 7)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	want := map[string]any{
+		"level":          "DEBUG",
+		"msg":            "Domain checked",
+		"domain":         "example.com",
+		"days_remaining": float64(7),
+		"request_id":     "req-1",
+	}
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("%s = %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+func TestNewHandlerDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	handler, err := NewHandler(&buf, &config.LogConfig{})
+	if err != nil {
+		t.Fatalf("NewHandler: %v", err)
+	}
+	logger := slog.New(handler)
+	logger.Debug("debug message")
+	logger.Info("info message")
+
+	if out := buf.String(); strings.Contains(out, "debug message") || !strings.Contains(out, "level=INFO") {
+		t.Errorf("default handler should log text at info level:\n%s", out)
+	}
+}
+
+func TestNewHandlerInvalid(t *testing.T) {
+	for _, cfg := range []config.LogConfig{
+		{Level: "verbose"},
+		{Format: "xml"},
+	} {
+		if _, err := NewHandler(&bytes.Buffer{}, &cfg); err == nil {
+			t.Errorf("NewHandler(%+v) succeeded, want an error", cfg)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package metrics keeps process-wide counters and renders them in the Prometheus
+// text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Counters updated by the services
+var (
+	WhoisQueries  = NewCounterVec("jiankong_whois_queries_total", "Live WHOIS queries by result.", "result")
+	DomainChecks  = NewCounterVec("jiankong_domain_checks_total", "Domain checks by result.", "result")
+	Notifications = NewCounterVec("jiankong_notifications_total", "Notifications sent by channel and status.", "channel", "status")
+)
+
+// counters lists the counters written by WriteCounters
+var counters = []*CounterVec{WhoisQueries, DomainChecks, Notifications}
+
+// CounterVec is a monotonically increasing counter partitioned by labels
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64 // Keyed by the joined label values
+}
+
+// NewCounterVec creates a counter with the given label names
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: make(map[string]float64),
+	}
+}
+
+// Inc increments the counter for the label values, given in label order
+func (c *CounterVec) Inc(values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[strings.Join(values, "\x00")]++
+}
+
+// write renders the counter
+func (c *CounterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		WriteSample(w, c.name, Labels(c.labels, strings.Split(key, "\x00")), c.values[key])
+	}
+}
+
+// WriteCounters renders all counters
+func WriteCounters(w io.Writer) {
+	for _, c := range counters {
+		c.write(w)
+	}
+}
+
+// WriteGauge renders a gauge with a single unlabelled sample
+func WriteGauge(w io.Writer, name, help string, value float64) {
+	writeHeader(w, name, help, "gauge")
+	WriteSample(w, name, "", value)
+}
+
+// WriteGaugeHeader starts a labelled gauge whose samples follow via WriteSample
+func WriteGaugeHeader(w io.Writer, name, help string) {
+	writeHeader(w, name, help, "gauge")
+}
+
+// WriteSample renders one sample line; labels is the output of Labels
+func WriteSample(w io.Writer, name, labels string, value float64) {
+	fmt.Fprintf(w, "%s%s %v\n", name, labels, value)
+}
+
+// Labels formats label pairs as {name="value",...}, escaping the values
+func Labels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(value))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric
+func writeHeader(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, helpEscaper.Replace(help), name, kind)
+}
+
+// labelEscaper escapes label values as the exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// helpEscaper escapes HELP text, where quotes are left as is
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
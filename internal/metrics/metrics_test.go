@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelsEscapesValues(t *testing.T) {
+	got := Labels([]string{"domain", "window"}, []string{"a\\b\"c\nd", "7d"})
+	want := `{domain="a\\b\"c\nd",window="7d"}`
+	if got != want {
+		t.Errorf("Labels() = %s, want %s", got, want)
+	}
+}
+
+func TestLabelsMissingValue(t *testing.T) {
+	if got := Labels([]string{"result"}, nil); got != `{result=""}` {
+		t.Errorf("Labels() = %s", got)
+	}
+	if got := Labels(nil, nil); got != "" {
+		t.Errorf("Labels() without names = %q, want empty", got)
+	}
+}
+
+func TestCounterVecWrite(t *testing.T) {
+	c := NewCounterVec("test_total", "Test counter.\nSecond line.", "channel", "status")
+	c.Inc("email", "sent")
+	c.Inc("email", "sent")
+	c.Inc("bark", "failed")
+
+	var b strings.Builder
+	c.write(&b)
+
+	want := "# HELP test_total Test counter.\\nSecond line.\n" +
+		"# TYPE test_total counter\n" +
+		"test_total{channel=\"bark\",status=\"failed\"} 1\n" +
+		"test_total{channel=\"email\",status=\"sent\"} 2\n"
+	if b.String() != want {
+		t.Errorf("write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestWriteGauge(t *testing.T) {
+	var b strings.Builder
+	WriteGauge(&b, "test_gauge", "Test gauge.", 3)
+
+	want := "# HELP test_gauge Test gauge.\n# TYPE test_gauge gauge\ntest_gauge 3\n"
+	if b.String() != want {
+		t.Errorf("WriteGauge() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
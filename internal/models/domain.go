@@ -1,49 +1,473 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatusAvailable is the status of a domain the WHOIS API reports as not registered
+const StatusAvailable = "available"
+
+// Check outcomes recorded on the domain
+const (
+	CheckStatusOK         = "ok"
+	CheckStatusParseError = "parse_error" // WHOIS answered without a parseable expiry date
+	CheckStatusQueryError = "query_error" // WHOIS query failed
 )
 
 // Domain represents a domain record in the database
 type Domain struct {
-	ID            uint      `gorm:"primarykey" json:"id"`
-	Name          string    `gorm:"uniqueIndex;not null" json:"name"`        // Domain name
-	Registrar     string    `json:"registrar"`                                // Registrar
-	ExpiryDate    time.Time `json:"expiry_date"`                              // Expiration date
-	CreatedDate   time.Time `json:"created_date"`                             // Registration date
-	UpdatedDate   time.Time `json:"updated_date"`                             // Update date
-	Status        string    `json:"status"`                                   // Domain status
-	DaysRemaining int       `json:"days_remaining"`                           // Days remaining
-	Tags          string    `json:"tags"`                                     // Tags (JSON or comma separated)
-	LastChecked   time.Time `json:"last_checked"`                             // Last check time
-	IsActive      bool      `gorm:"default:true" json:"is_active"`            // Monitor enabled
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                  uint       `gorm:"primarykey" json:"id"`
+	Name                string     `gorm:"index:idx_domains_name_incl_trashed;size:255;not null" json:"name"` // Domain name (unique among domains not in the trash, see database.createDomainNameIndex)
+	Registrar           string     `json:"registrar"`                                                         // Registrar
+	ExpiryDate          *time.Time `json:"expiry_date"`                                                       // Expiration date
+	CreatedDate         *time.Time `json:"created_date"`                                                      // Registration date
+	AgeDays             int        `gorm:"-" json:"age_days"`                                                 // Days since registration (derived, 0 if unknown)
+	AgeYears            float64    `gorm:"-" json:"age_years"`                                                // Years since registration (derived)
+	UpdatedDate         *time.Time `json:"updated_date"`                                                      // Update date
+	Status              string     `json:"status"`                                                            // Domain status
+	DaysRemaining       int        `json:"days_remaining"`                                                    // Days remaining
+	NameServers         string     `json:"name_servers"`                                                      // Name servers (JSON array)
+	LastAlertThreshold  *int       `json:"last_alert_threshold"`                                              // Lowest alert threshold already notified (nil = none)
+	LastBucket          string     `json:"last_bucket"`                                                       // Last recorded urgency bucket (transition alert mode)
+	Parked              bool       `json:"parked"`                                                            // Name servers match a parking provider
+	AlertPolicyID       uint       `gorm:"index" json:"alert_policy_id"`                                      // Alert policy (0 = default policy)
+	GroupID             uint       `gorm:"index" json:"group_id"`                                             // Domain group (0 = none)
+	Tags                string     `json:"tags"`                                                              // Tags as a JSON array (comma separated input is converted on save)
+	Notes               string     `gorm:"type:text" json:"notes"`                                            // Free-form notes
+	RenewalCost         float64    `json:"renewal_cost"`                                                      // Annual renewal cost (0 = unknown)
+	Currency            string     `gorm:"size:3" json:"currency"`                                            // ISO 4217 code of the renewal cost, e.g. USD
+	RawWhois            string     `gorm:"type:text" json:"-"`                                                // Raw WHOIS/RDAP response of the last check
+	LastChecked         *time.Time `json:"last_checked"`                                                      // Last check time
+	CheckFrequencyDays  int        `json:"check_frequency_days"`                                              // Minimum days between scheduled checks (0 = every run)
+	LastError           string     `json:"last_error"`                                                        // Last check error, set after failure_threshold consecutive failures
+	ConsecutiveFailures int        `json:"consecutive_failures"`                                              // Failed checks in a row (reset on success)
+	CheckStatus         string     `gorm:"index" json:"check_status"`                                         // Outcome of the last check (ok/parse_error/query_error, empty = not checked)
+	LastCheckError      string     `json:"last_check_error"`                                                  // Error of the last check, empty if it succeeded
+	IsActive            bool       `gorm:"default:true" json:"is_active"`                                     // Monitor enabled
+	Retiring            bool       `gorm:"default:false;index" json:"retiring"`                               // Intentionally left to expire: no expiry alerts
+	PausedUntil         *time.Time `json:"paused_until"`                                                      // Scheduled checks are skipped until this time (nil = not paused)
+	PauseReason         string     `json:"pause_reason"`                                                      // Why the domain is paused
+	DeleteRequestedBy   string     `json:"delete_requested_by"`                                               // Admin who requested deletion (empty = not pending)
+	DeleteRequestedAt   *time.Time `json:"delete_requested_at"`                                               // When deletion was requested
+	NotifyEnabled       bool       `gorm:"default:true" json:"notify_enabled"`                                // Alerts enabled (checks still run when false)
+	CertExpiry          *time.Time `json:"cert_expiry"`                                                       // TLS certificate expiration date
+	CertIssuer          string     `json:"cert_issuer"`                                                       // TLS certificate issuer
+	CertStatus          string     `json:"cert_status"`                                                       // Certificate check state (ok/refused/timeout/dns_error/handshake_failed/error)
+	CertError           string     `json:"cert_error"`                                                        // Last certificate check error
+	CertChecked         *time.Time `json:"cert_checked"`                                                      // Last certificate check time
+	DNSRecordTypes      string     `json:"dns_record_types"`                                                  // Watched DNS record types, comma separated (empty = dns.record_types)
+	DNSRecords          string     `gorm:"type:text" json:"dns_records"`                                      // Snapshot of the watched DNS records (JSON object of type to values)
+	DNSChecked          *time.Time `json:"dns_checked"`                                                       // Last DNS check time
+	UptimeEnabled       bool       `gorm:"default:false" json:"uptime_enabled"`                               // Check that the site is reachable
+	UptimeURL           string     `json:"uptime_url"`                                                        // URL of the uptime check (empty = https://<name>/)
+	UptimeStatus        string     `json:"uptime_status"`                                                     // up/down, empty until the first uptime check
+	LastHTTPStatus      int        `json:"last_http_status"`                                                  // HTTP status code of the last uptime check (0 = no response)
+	LastLatencyMs       int64      `json:"last_latency_ms"`                                                   // Response time of the last uptime check
+	UptimeError         string     `json:"uptime_error"`                                                      // Why the last uptime check failed
+	UptimeFailures      int        `json:"uptime_failures"`                                                   // Failed uptime checks in a row (reset when the site responds)
+	LastUptimeCheck     *time.Time `json:"last_uptime_check"`                                                 // Last uptime check time
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"` // Moved to the trash (soft delete)
+
+	NextAlert *NextAlert `gorm:"-" json:"next_alert,omitempty"` // Preview of the next expiry alert (domain detail only)
+}
+
+// NextAlert previews the next expiry alert of a domain
+type NextAlert struct {
+	Threshold  int       `json:"threshold"`            // Alert day (days remaining) that triggers the alert
+	Date       time.Time `json:"date"`                 // Estimated date the alert fires
+	Due        bool      `json:"due"`                  // Threshold already crossed: fires on the next check
+	Channels   []string  `json:"channels"`             // Channels the alert is sent to
+	Suppressed string    `json:"suppressed,omitempty"` // Why the alert won't be sent (muted/retiring/paused), if so
+}
+
+// PendingDeletion reports whether the domain awaits a deletion confirmation
+func (d *Domain) PendingDeletion() bool {
+	return d.DeleteRequestedBy != ""
+}
+
+// NameServerList returns the stored name servers
+func (d *Domain) NameServerList() []string {
+	var nameServers []string
+	if d.NameServers != "" {
+		json.Unmarshal([]byte(d.NameServers), &nameServers)
+	}
+	return nameServers
+}
+
+// AfterFind fills in the derived fields after loading from the database and converts the
+// WHOIS dates to the configured time zone for API responses
+func (d *Domain) AfterFind(tx *gorm.DB) error {
+	for _, t := range []*time.Time{d.ExpiryDate, d.CreatedDate, d.UpdatedDate} {
+		if t != nil {
+			*t = InLocation(*t)
+		}
+	}
+	d.ComputeAge(time.Now())
+	return nil
+}
+
+// ComputeAge sets AgeDays and AgeYears from the registration date
+func (d *Domain) ComputeAge(now time.Time) {
+	if d.CreatedDate == nil || d.CreatedDate.After(now) {
+		d.AgeDays, d.AgeYears = 0, 0
+		return
+	}
+	d.AgeDays = int(now.Sub(*d.CreatedDate).Hours() / 24)
+	d.AgeYears = math.Round(float64(d.AgeDays)/365.25*10) / 10
+}
+
+// RefreshDaysRemaining recomputes DaysRemaining from the expiry date at now, so API responses
+// don't show the value stored by the last check. Unknown expiry dates keep the stored value.
+func (d *Domain) RefreshDaysRemaining(now time.Time) {
+	if d.ExpiryDate != nil {
+		d.DaysRemaining = DaysUntil(*d.ExpiryDate, now)
+	}
+}
+
+// IsPaused reports whether scheduled checks of the domain are paused at the given time
+func (d *Domain) IsPaused(now time.Time) bool {
+	return d.PausedUntil != nil && now.Before(*d.PausedUntil)
+}
+
+// checkDueSlack lets a check that ran a little after the scheduled time not push the
+// next one back by a whole run
+const checkDueSlack = time.Hour
+
+// CheckDue reports whether a scheduled run at the given time should check the domain.
+// A domain is due as soon as its days remaining reach one of the alert days that the
+// last check was still above, and domains expiring within their check frequency are
+// checked on every run, so that no alert threshold is missed.
+func (d *Domain) CheckDue(now time.Time, alertDays []int) bool {
+	if d.CheckFrequencyDays <= 0 || d.LastChecked == nil {
+		return true
+	}
+
+	frequency := time.Duration(d.CheckFrequencyDays) * 24 * time.Hour
+	if d.ExpiryDate != nil {
+		if d.ExpiryDate.Sub(now) <= frequency {
+			return true
+		}
+		remaining := DaysUntil(*d.ExpiryDate, now)
+		lastRemaining := DaysUntil(*d.ExpiryDate, *d.LastChecked)
+		for _, days := range alertDays {
+			if remaining <= days && lastRemaining > days {
+				return true
+			}
+		}
+	}
+
+	return !now.Before(d.LastChecked.Add(frequency - checkDueSlack))
+}
+
+// BeforeSave stores the tags as a normalized JSON array, whatever format the caller set
+func (d *Domain) BeforeSave(tx *gorm.DB) error {
+	d.SetTags(d.TagList())
+	return nil
+}
+
+// TagList returns the domain tags. Tags are stored as a JSON array; comma separated
+// values written by older versions or set by callers, and malformed arrays, are split
+// on commas instead.
+func (d *Domain) TagList() []string {
+	tags := make([]string, 0)
+	if strings.HasPrefix(strings.TrimSpace(d.Tags), "[") {
+		if err := json.Unmarshal([]byte(d.Tags), &tags); err == nil {
+			return tags
+		}
+		tags = tags[:0]
+	}
+	for _, tag := range strings.Split(d.Tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// HasTag reports whether the domain has the given tag (case-insensitive)
+func (d *Domain) HasTag(tag string) bool {
+	return containsTag(d.TagList(), tag)
+}
+
+// SetTags stores the tags as a JSON array, trimmed and without duplicates (case-insensitive)
+func (d *Domain) SetTags(tags []string) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || containsTag(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) == 0 {
+		d.Tags = ""
+		return
+	}
+	data, _ := json.Marshal(normalized)
+	d.Tags = string(data)
+}
+
+// AddTag adds a tag, reporting whether the domain didn't have it yet
+func (d *Domain) AddTag(tag string) bool {
+	tags := d.TagList()
+	if strings.TrimSpace(tag) == "" || containsTag(tags, strings.TrimSpace(tag)) {
+		return false
+	}
+	d.SetTags(append(tags, tag))
+	return true
+}
+
+// RemoveTag removes a tag (case-insensitive), reporting whether the domain had it
+func (d *Domain) RemoveTag(tag string) bool {
+	tags := d.TagList()
+	kept := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if !strings.EqualFold(t, strings.TrimSpace(tag)) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == len(tags) {
+		return false
+	}
+	d.SetTags(kept)
+	return true
+}
+
+// containsTag reports whether tags contains tag (case-insensitive)
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetNameServers stores the name servers as a JSON array
+func (d *Domain) SetNameServers(nameServers []string) {
+	if len(nameServers) == 0 {
+		d.NameServers = ""
+		return
+	}
+	data, _ := json.Marshal(nameServers)
+	d.NameServers = string(data)
+}
+
+// Uptime states recorded on the domain
+const (
+	UptimeUp   = "up"
+	UptimeDown = "down"
+)
+
+// UptimeCheckURL returns the URL checked by uptime monitoring
+func (d *Domain) UptimeCheckURL() string {
+	if d.UptimeURL != "" {
+		return d.UptimeURL
+	}
+	return "https://" + d.Name + "/"
+}
+
+// SupportedDNSRecordTypes are the DNS record types that can be watched for changes
+var SupportedDNSRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT"}
+
+// ParseDNSRecordTypes parses a comma separated list of DNS record types (case-insensitive),
+// removing duplicates. Unsupported types are an error.
+func ParseDNSRecordTypes(value string) ([]string, error) {
+	types := make([]string, 0)
+	for _, recordType := range strings.Split(value, ",") {
+		recordType = strings.ToUpper(strings.TrimSpace(recordType))
+		if recordType == "" || slices.Contains(types, recordType) {
+			continue
+		}
+		if !slices.Contains(SupportedDNSRecordTypes, recordType) {
+			return nil, fmt.Errorf("unsupported DNS record type %q (supported: %s)", recordType, strings.Join(SupportedDNSRecordTypes, ", "))
+		}
+		types = append(types, recordType)
+	}
+	return types, nil
+}
+
+// DNSRecordSet returns the stored DNS record snapshot, nil if there is none
+func (d *Domain) DNSRecordSet() map[string][]string {
+	if d.DNSRecords == "" {
+		return nil
+	}
+	var records map[string][]string
+	json.Unmarshal([]byte(d.DNSRecords), &records)
+	return records
+}
+
+// SetDNSRecords stores a DNS record snapshot as a JSON object
+func (d *Domain) SetDNSRecords(records map[string][]string) {
+	data, _ := json.Marshal(records)
+	d.DNSRecords = string(data)
+}
+
+// DomainGroup represents a group of domains, e.g. one client, with its own notification templates
+type DomainGroup struct {
+	ID               uint      `gorm:"primarykey" json:"id"`
+	Name             string    `gorm:"uniqueIndex;size:255;not null" json:"name"` // Group name
+	Template         string    `gorm:"type:text" json:"template"`                 // Expiry message template for all channels (text/template)
+	ChannelTemplates string    `gorm:"type:text" json:"channel_templates"`        // Per-channel templates (JSON object: channel type -> template)
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// TemplateFor returns the template for a notification channel, falling back to the group template
+func (g *DomainGroup) TemplateFor(channel string) string {
+	if g.ChannelTemplates != "" {
+		var templates map[string]string
+		if err := json.Unmarshal([]byte(g.ChannelTemplates), &templates); err == nil {
+			if tmpl := templates[channel]; tmpl != "" {
+				return tmpl
+			}
+		}
+	}
+	return g.Template
+}
+
+// AlertPolicy is a named set of alert thresholds, channels and template shared by domains
+type AlertPolicy struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;size:255;not null" json:"name"` // Policy name, e.g. critical/standard/low-priority
+	AlertDays string    `json:"alert_days"`                                // Comma separated alert thresholds, e.g. "60,30,7,1"
+	Channels  string    `json:"channels"`                                  // Comma separated channel types (empty = all enabled channels)
+	Template  string    `gorm:"type:text" json:"template"`                 // Expiry message template (text/template, optional)
+	IsDefault bool      `gorm:"default:false" json:"is_default"`           // Used by domains without a policy
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Days returns the alert thresholds of the policy
+func (p *AlertPolicy) Days() []int {
+	days := make([]int, 0)
+	for _, d := range strings.Split(p.AlertDays, ",") {
+		if day, err := strconv.Atoi(strings.TrimSpace(d)); err == nil {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// AllowsChannel reports whether alerts under the policy go to the given channel type
+func (p *AlertPolicy) AllowsChannel(channel string) bool {
+	if strings.TrimSpace(p.Channels) == "" {
+		return true
+	}
+	for _, c := range strings.Split(p.Channels, ",") {
+		if strings.TrimSpace(c) == channel {
+			return true
+		}
+	}
+	return false
 }
 
 // Notification represents a notification record
 type Notification struct {
 	ID       uint      `gorm:"primarykey" json:"id"`
-	DomainID uint      `json:"domain_id"`                      // Associated domain
-	Type     string    `json:"type"`                           // Notification type (email/webhook/telegram)
-	Content  string    `json:"content"`                        // Notification content
-	Status   string    `json:"status"`                         // Send status (success/failed)
+	DomainID uint      `json:"domain_id"` // Associated domain
+	Type     string    `json:"type"`      // Notification type (email/webhook/telegram)
+	Event    string    `json:"event"`     // Alert kind (expiry/anomaly/parking/change/disabled/system/digest)
+	Content  string    `json:"content"`   // Notification content
+	Status   string    `json:"status"`    // Send status (success/failed)
 	SentAt   time.Time `json:"sent_at"`
 }
 
+// DomainCheckHistory records the outcome of one domain check
+type DomainCheckHistory struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	DomainID      uint       `gorm:"index:idx_history_domain_checked" json:"domain_id"`
+	CheckedAt     time.Time  `gorm:"index:idx_history_domain_checked;index" json:"checked_at"`
+	DaysRemaining int        `json:"days_remaining"`
+	Status        string     `json:"status"`
+	ExpiryDate    *time.Time `json:"expiry_date"`
+	Success       bool       `json:"success"`
+	Error         string     `json:"error,omitempty"`
+}
+
 // Setting represents system configuration
 type Setting struct {
-	Key   string `gorm:"primarykey" json:"key"`
+	Key   string `gorm:"primarykey;size:255" json:"key"`
 	Value string `json:"value"`
 }
 
+// User roles
+const (
+	RoleAdmin  = "admin"  // Full access
+	RoleViewer = "viewer" // Read-only access
+)
+
 // User represents a user account
 type User struct {
 	ID        uint      `gorm:"primarykey" json:"id"`
-	Username  string    `gorm:"uniqueIndex;not null" json:"username"` // Username
-	Password  string    `gorm:"not null" json:"-"`                    // Hashed password (excluded from JSON)
-	Email     string    `json:"email"`                                // Email
-	IsActive  bool      `gorm:"default:true" json:"is_active"`        // Account status
+	Username  string    `gorm:"uniqueIndex;size:255;not null" json:"username"` // Username
+	Password  string    `gorm:"not null" json:"-"`                             // Hashed password (excluded from JSON)
+	Email     string    `json:"email"`                                         // Email
+	Role      string    `gorm:"size:20;default:admin" json:"role"`             // admin/viewer
+	IsActive  bool      `gorm:"default:true" json:"is_active"`                 // Account status
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// ValidRole reports whether role is a known user role
+func ValidRole(role string) bool {
+	return role == RoleAdmin || role == RoleViewer
+}
+
+// APIKey authenticates automation (CI, dashboards) through the X-API-Key header
+type APIKey struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	Label     string     `gorm:"size:255;not null" json:"label"`        // What the key is used for
+	KeyHash   string     `gorm:"uniqueIndex;size:64;not null" json:"-"` // SHA-256 of the key (hex)
+	Prefix    string     `gorm:"size:16" json:"prefix"`                 // First characters of the key, to recognize it
+	Role      string     `gorm:"size:20;default:viewer" json:"role"`    // admin/viewer
+	CreatedBy string     `json:"created_by"`                            // Admin who created the key
+	LastUsed  *time.Time `json:"last_used"`                             // Last successful authentication (nil = never)
+	IsActive  bool       `gorm:"default:true" json:"is_active"`         // false once revoked
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Audit log actions
+const (
+	AuditSettingsUpdate      = "settings.update"
+	AuditSettingsImport      = "settings.import"
+	AuditPasswordChange      = "password.change"
+	AuditUserCreate          = "user.create"
+	AuditUserUpdate          = "user.update"
+	AuditUserDelete          = "user.delete"
+	AuditDomainDelete        = "domain.delete"         // Moved to the trash
+	AuditDomainDeleteRequest = "domain.delete_request" // Deletion awaiting confirmation
+	AuditDomainDeleteConfirm = "domain.delete_confirm" // Confirmed deletion, moved to the trash
+	AuditDomainDeleteCancel  = "domain.delete_cancel"  // Deletion request withdrawn
+	AuditDomainPurge         = "domain.purge"          // Permanently deleted from the trash
+)
+
+// AuditLog records who changed settings, users or domains
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ActorID   uint      `gorm:"index" json:"actor_id"`       // User who made the change (0 for API keys)
+	Actor     string    `gorm:"size:255;index" json:"actor"` // Username, or apikey:<label>
+	Action    string    `gorm:"size:64;index" json:"action"` // See the Audit* constants
+	Target    string    `json:"target"`                      // What was changed, e.g. user:alice or domain:example.com
+	Before    string    `gorm:"type:text" json:"before"`     // JSON summary of the changed values before (empty on creation)
+	After     string    `gorm:"type:text" json:"after"`      // JSON summary of the changed values after (empty on deletion)
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// WhoisSnapshot caches the latest WHOIS result of a domain, shared by all replicas
+type WhoisSnapshot struct {
+	Domain    string    `gorm:"primarykey;size:255" json:"domain"` // Domain name
+	Data      string    `gorm:"type:text" json:"data"`             // DomainInfo as JSON
+	FetchedAt time.Time `gorm:"index" json:"fetched_at"`           // Time of the live query
+}
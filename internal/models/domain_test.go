@@ -0,0 +1,97 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCheckDue(t *testing.T) {
+	SetLocation(time.UTC)
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	alertDays := []int{90, 60, 7}
+
+	tests := []struct {
+		name        string
+		frequency   int
+		lastChecked time.Duration // Before now
+		expiresIn   time.Duration // From now; 0 for unknown
+		want        bool
+	}{
+		{"no frequency", 0, time.Hour, 200 * day, true},
+		{"checked recently", 30, 10 * day, 200 * day, false},
+		{"frequency elapsed", 30, 30 * day, 200 * day, true},
+		{"within slack", 30, 30*day - 30*time.Minute, 200 * day, true},
+		{"unknown expiry", 30, 10 * day, 0, false},
+		{"expires within frequency", 30, 10 * day, 20 * day, true},
+		// 75 days left at the last check, 65 now: the 60-day threshold is still ahead
+		{"before the next threshold", 30, 10 * day, 65 * day, false},
+		// 75 days left at the last check, 60 now: due long before the 30 days are up
+		{"threshold reached", 30, 15 * day, 60 * day, true},
+		// 60 days left at the last check, which already handled that threshold
+		{"threshold already handled", 30, 1 * day, 59 * day, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lastChecked := now.Add(-tt.lastChecked)
+			d := Domain{CheckFrequencyDays: tt.frequency, LastChecked: &lastChecked}
+			if tt.expiresIn != 0 {
+				expiry := now.Add(tt.expiresIn)
+				d.ExpiryDate = &expiry
+			}
+			if got := d.CheckDue(now, alertDays); got != tt.want {
+				t.Errorf("CheckDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDueNeverChecked(t *testing.T) {
+	d := Domain{CheckFrequencyDays: 30}
+	if !d.CheckDue(time.Now(), nil) {
+		t.Error("a domain that was never checked should be due")
+	}
+}
+
+func TestTagList(t *testing.T) {
+	tests := []struct {
+		tags string
+		want []string
+	}{
+		{"", []string{}},
+		{`["prod","client"]`, []string{"prod", "client"}},
+		{"prod, client,,", []string{"prod", "client"}},
+		{`["prod"`, []string{`["prod"`}},
+	}
+	for _, tt := range tests {
+		d := Domain{Tags: tt.tags}
+		if got := d.TagList(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TagList(%q) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestAddRemoveTag(t *testing.T) {
+	var d Domain
+	if !d.AddTag(" prod ") || !d.AddTag("client") {
+		t.Fatal("AddTag of new tags returned false")
+	}
+	if d.AddTag("PROD") || d.AddTag(" ") {
+		t.Error("AddTag of a duplicate or empty tag returned true")
+	}
+	if d.Tags != `["prod","client"]` {
+		t.Errorf("tags = %s, want a JSON array", d.Tags)
+	}
+	if !d.HasTag("Client") {
+		t.Error("HasTag is case-sensitive")
+	}
+
+	if !d.RemoveTag("PROD") || d.RemoveTag("missing") {
+		t.Error("RemoveTag reported the wrong result")
+	}
+	if !d.RemoveTag("client") || d.Tags != "" {
+		t.Errorf("tags = %q after removing all, want empty", d.Tags)
+	}
+}
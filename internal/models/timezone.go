@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Date layouts used in notifications and API responses
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// location is the time zone of calendar days and displayed dates, set once at startup
+var location = time.Local
+
+// SetLocation sets the time zone used for days remaining and date formatting
+func SetLocation(loc *time.Location) {
+	location = loc
+}
+
+// Location returns the configured time zone (server local time by default)
+func Location() *time.Location {
+	return location
+}
+
+// InLocation returns t in the configured time zone; the zero time is returned unchanged
+func InLocation(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(location)
+}
+
+// NullTime returns a pointer to t for a nullable date column, nil if t is zero. MySQL
+// rejects zero dates in strict mode, so unknown dates are stored as NULL.
+func NullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// TimeValue returns the time t points to, the zero time if t is nil
+func TimeValue(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// DaysUntil returns the number of calendar days from now until t in the configured time
+// zone: 0 when t falls on today, negative when it has passed
+func DaysUntil(t, now time.Time) int {
+	day := func(t time.Time) time.Time {
+		y, m, d := t.In(location).Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(day(t).Sub(day(now)).Hours() / 24)
+}
+
+// FormatDate formats t as YYYY-MM-DD in the configured time zone
+func FormatDate(t time.Time) string {
+	return t.In(location).Format(DateLayout)
+}
+
+// FormatDateTime formats t as YYYY-MM-DD HH:MM:SS in the configured time zone
+func FormatDateTime(t time.Time) string {
+	return t.In(location).Format(DateTimeLayout)
+}
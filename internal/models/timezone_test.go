@@ -0,0 +1,93 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// setTestLocation loads the named zone as the configured location for the test
+func setTestLocation(t *testing.T, name string) {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", name, err)
+	}
+	prev := Location()
+	SetLocation(loc)
+	t.Cleanup(func() { SetLocation(prev) })
+}
+
+func TestDaysUntilAcrossZones(t *testing.T) {
+	// 23:30 on March 1 in Shanghai is 10:30 on March 1 in New York
+	now := time.Date(2026, 3, 1, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		zone     string
+		expiry   time.Time
+		want     int
+		wantDate string
+	}{
+		// One hour later is past midnight in Shanghai but still March 1 in New York
+		{"after midnight east", "Asia/Shanghai", now.Add(time.Hour), 1, "2026-03-02"},
+		{"after midnight west", "America/New_York", now.Add(time.Hour), 0, "2026-03-01"},
+		{"later that day east", "Asia/Shanghai", now.Add(10 * time.Minute), 0, "2026-03-01"},
+		{"later that day west", "America/New_York", now.Add(10 * time.Minute), 0, "2026-03-01"},
+		// 14:00 UTC on March 2 is March 2 in both zones
+		{"next day east", "Asia/Shanghai", now.Add(22*time.Hour + 30*time.Minute), 1, "2026-03-02"},
+		{"next day west", "America/New_York", now.Add(22*time.Hour + 30*time.Minute), 1, "2026-03-02"},
+		// 04:00 UTC on March 1 is still February 28 in New York
+		{"passed east", "Asia/Shanghai", now.Add(-11*time.Hour - 30*time.Minute), 0, "2026-03-01"},
+		{"passed west", "America/New_York", now.Add(-11*time.Hour - 30*time.Minute), -1, "2026-02-28"},
+		{"utc", "UTC", now.Add(9 * time.Hour), 1, "2026-03-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestLocation(t, tt.zone)
+			if got := DaysUntil(tt.expiry, now); got != tt.want {
+				t.Errorf("DaysUntil() = %d, want %d", got, tt.want)
+			}
+			if got := FormatDate(tt.expiry); got != tt.wantDate {
+				t.Errorf("FormatDate() = %q, want %q", got, tt.wantDate)
+			}
+		})
+	}
+}
+
+func TestDaysUntilAcrossDSTChange(t *testing.T) {
+	setTestLocation(t, "America/New_York")
+	// Clocks go forward on March 8, 2026 in New York, so the days are not all 24 hours long
+	now := time.Date(2026, 3, 7, 23, 30, 0, 0, Location())
+	expiry := time.Date(2026, 3, 9, 0, 30, 0, 0, Location())
+	if got := DaysUntil(expiry, now); got != 2 {
+		t.Errorf("DaysUntil() = %d, want 2", got)
+	}
+}
+
+func TestRefreshDaysRemainingUsesLocation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 15, 30, 0, 0, time.UTC)
+	expiry := now.Add(time.Hour)
+	d := &Domain{ExpiryDate: &expiry}
+
+	setTestLocation(t, "Asia/Shanghai")
+	d.RefreshDaysRemaining(now)
+	if d.DaysRemaining != 1 {
+		t.Errorf("DaysRemaining in Asia/Shanghai = %d, want 1", d.DaysRemaining)
+	}
+
+	SetLocation(time.UTC)
+	d.RefreshDaysRemaining(now)
+	if d.DaysRemaining != 0 {
+		t.Errorf("DaysRemaining in UTC = %d, want 0", d.DaysRemaining)
+	}
+}
+
+func TestFormatDateTimeUsesLocation(t *testing.T) {
+	setTestLocation(t, "Asia/Shanghai")
+	ts := time.Date(2026, 3, 1, 15, 30, 0, 0, time.UTC)
+	if got, want := FormatDateTime(ts), "2026-03-01 23:30:00"; got != want {
+		t.Errorf("FormatDateTime() = %q, want %q", got, want)
+	}
+	if got := InLocation(time.Time{}); !got.IsZero() {
+		t.Errorf("InLocation(zero) = %v, want the zero time", got)
+	}
+}
@@ -1,48 +1,275 @@
 package scheduler
 
 import (
+	"context"
+	"domain-monitor/internal/models"
 	"domain-monitor/internal/services"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+// ErrSweepRunning is returned when a sweep is requested while one is in progress
+var ErrSweepRunning = errors.New("a domain check is already running")
+
 // Scheduler handles scheduled tasks
 type Scheduler struct {
 	cron           *cron.Cron
 	monitorService *services.MonitorService
+	certService    *services.CertService   // Optional, nil when certificate monitoring is disabled
+	uptimeService  *services.UptimeService // Optional, nil when uptime checks are disabled
+	uptimeRunning  atomic.Bool             // Uptime checks are in progress
+	ctx            context.Context         // Cancelled by Stop to abort running checks
+	cancel         context.CancelFunc
+	running        atomic.Bool
+
+	mu       sync.Mutex
+	interval string       // Cron expression of the sweep
+	entryID  cron.EntryID // Cron entry of the sweep
+	sweeping bool         // A sweep is in progress
+}
+
+// Status describes the schedule and the state of the sweep
+type Status struct {
+	Interval     string     `json:"interval"`
+	NextRun      *time.Time `json:"next_run"` // nil when the scheduler is not running
+	Running      bool       `json:"running"`  // Scheduler started
+	SweepRunning bool       `json:"sweep_running"`
 }
 
 // NewScheduler creates a new scheduler
-func NewScheduler(monitorService *services.MonitorService) *Scheduler {
+func NewScheduler(monitorService *services.MonitorService, certService *services.CertService, uptimeService *services.UptimeService) *Scheduler {
+	ctx, cancel := context.WithCancel(monitorService.Context())
 	return &Scheduler{
-		cron:           cron.New(),
+		cron:           cron.New(cron.WithLocation(models.Location())),
 		monitorService: monitorService,
+		certService:    certService,
+		uptimeService:  uptimeService,
+		ctx:            ctx,
+		cancel:         cancel,
 	}
 }
 
-// Start starts the scheduler
-func (s *Scheduler) Start(checkInterval string) error {
+// Start starts the scheduler. Uptime checks run on their own schedule, uptimeInterval.
+func (s *Scheduler) Start(checkInterval, uptimeInterval string) error {
 	// Add scheduled job to check all domains
-	_, err := s.cron.AddFunc(checkInterval, func() {
-		log.Println("Starting scheduled domain check...")
-		if err := s.monitorService.CheckAllDomains(); err != nil {
-			log.Printf("Scheduled check failed: %v", err)
-		}
-		log.Println("Scheduled domain check completed")
-	})
-
+	entryID, err := s.cron.AddFunc(checkInterval, s.sweep)
 	if err != nil {
 		return err
 	}
 
+	if s.uptimeService != nil {
+		if _, err := s.cron.AddFunc(uptimeInterval, s.checkUptime); err != nil {
+			return fmt.Errorf("invalid uptime interval %q: %w", uptimeInterval, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.interval = checkInterval
+	s.entryID = entryID
+	s.mu.Unlock()
+
 	s.cron.Start()
-	log.Printf("Scheduler started with interval: %s", checkInterval)
+	s.running.Store(true)
+	slog.Info("Scheduler started", "interval", checkInterval)
 	return nil
 }
 
-// Stop stops the scheduler
+// Stop stops the scheduler and cancels a running check
 func (s *Scheduler) Stop() {
+	s.cancel()
 	s.cron.Stop()
-	log.Println("Scheduler stopped")
+	s.running.Store(false)
+	slog.Info("Scheduler stopped")
+}
+
+// Shutdown stops scheduling jobs and waits for the running ones to finish. When ctx is done
+// first, the running checks are cancelled and ctx's error is returned.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.running.Store(false)
+	stopped := s.cron.Stop()
+
+	select {
+	case <-stopped.Done():
+		slog.Info("Scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		slog.Warn("Scheduler stopped before the running jobs finished", "error", ctx.Err())
+		return ctx.Err()
+	}
+}
+
+// ValidateInterval checks that a check interval is a valid cron expression
+func ValidateInterval(interval string) error {
+	if _, err := cron.ParseStandard(interval); err != nil {
+		return fmt.Errorf("invalid check interval %q: %w", interval, err)
+	}
+	return nil
+}
+
+// Reschedule replaces the sweep schedule. An invalid expression returns an error and
+// leaves the current schedule in place.
+func (s *Scheduler) Reschedule(interval string) error {
+	if err := ValidateInterval(interval); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if interval == s.interval {
+		return nil
+	}
+
+	entryID, err := s.cron.AddFunc(interval, s.sweep)
+	if err != nil {
+		return fmt.Errorf("invalid check interval %q: %w", interval, err)
+	}
+	s.cron.Remove(s.entryID)
+
+	slog.Info("Scheduler interval changed", "from", s.interval, "to", interval)
+	s.interval = interval
+	s.entryID = entryID
+	return nil
+}
+
+// Running reports whether the scheduler has been started and not stopped
+func (s *Scheduler) Running() bool {
+	return s.running.Load()
+}
+
+// Status returns the schedule and whether a sweep is in progress
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		Interval:     s.interval,
+		Running:      s.Running(),
+		SweepRunning: s.sweeping,
+	}
+	if next := s.cron.Entry(s.entryID).Next; status.Running && !next.IsZero() {
+		status.NextRun = &next
+	}
+	return status
+}
+
+// RunNow starts a sweep in the background unless one is already running
+func (s *Scheduler) RunNow() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sweeping {
+		return ErrSweepRunning
+	}
+	s.sweeping = true
+
+	go s.run()
+	return nil
+}
+
+// RunOverdue checks the domains that missed their scheduled check, e.g. during downtime,
+// in the background unless a sweep is already running
+func (s *Scheduler) RunOverdue() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sweeping {
+		return ErrSweepRunning
+	}
+	s.sweeping = true
+
+	interval := s.interval
+	go func() {
+		defer s.endSweep()
+		if err := s.monitorService.CheckOverdueDomains(interval); err != nil {
+			slog.Error("Overdue domain check failed", "error", err)
+		}
+	}()
+	return nil
+}
+
+// StartRefresh starts a refresh job of the monitor service unless a sweep is running.
+// The job counts as a sweep until it finishes, so scheduled ticks skip it.
+func (s *Scheduler) StartRefresh(domains []models.Domain) (*services.RefreshJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sweeping {
+		return nil, ErrSweepRunning
+	}
+	job, err := s.monitorService.StartRefresh(domains, s.endSweep)
+	if err != nil {
+		return job, err
+	}
+	s.sweeping = true
+	return job, nil
+}
+
+// sweep is the scheduled job. A tick is skipped while the previous sweep (or one started
+// with RunNow) is still running, so slow WHOIS responses can't stack up runs.
+func (s *Scheduler) sweep() {
+	s.mu.Lock()
+	if s.sweeping {
+		s.mu.Unlock()
+		slog.Warn("Skipping scheduled domain check: the previous check is still running")
+		return
+	}
+	s.sweeping = true
+	s.mu.Unlock()
+
+	s.run()
+}
+
+// checkUptime is the scheduled uptime job; a tick is skipped while the previous one runs
+func (s *Scheduler) checkUptime() {
+	if !s.uptimeRunning.CompareAndSwap(false, true) {
+		slog.Warn("Skipping scheduled uptime check: the previous check is still running")
+		return
+	}
+	defer s.uptimeRunning.Store(false)
+
+	if err := s.uptimeService.CheckAllUptime(s.ctx); err != nil {
+		slog.Error("Scheduled uptime check failed", "error", err)
+	}
+}
+
+// endSweep clears the sweeping flag
+func (s *Scheduler) endSweep() {
+	s.mu.Lock()
+	s.sweeping = false
+	s.mu.Unlock()
+}
+
+// run checks all domains and certificates, then clears the sweeping flag
+func (s *Scheduler) run() {
+	defer s.endSweep()
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("Scheduled check panicked", "panic", r)
+			m := s.monitorService.Messages()
+			s.monitorService.ReportSystemAlert(m.SchedulerPanicTitle, fmt.Sprintf(m.SchedulerPanicMessage, r))
+		}
+	}()
+
+	start := time.Now()
+	slog.Info("Starting scheduled domain check")
+	if err := s.monitorService.CheckAllDomainsContext(s.ctx); err != nil {
+		slog.Error("Scheduled check failed", "error", err)
+	}
+	slog.Info("Scheduled domain check completed", "duration", time.Since(start).Round(time.Millisecond).String())
+
+	if s.certService != nil {
+		if err := s.certService.CheckAllCertificates(); err != nil {
+			slog.Error("Scheduled certificate check failed", "error", err)
+			m := s.monitorService.Messages()
+			s.monitorService.ReportSystemAlert(m.CertCheckFailedTitle, fmt.Sprintf(m.CertCheckFailedMessage, err))
+		}
+	}
 }
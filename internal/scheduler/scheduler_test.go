@@ -0,0 +1,276 @@
+package scheduler
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"domain-monitor/internal/services"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// slowWhois is a WHOIS API that holds every query until released
+type slowWhois struct {
+	*httptest.Server
+	queries     atomic.Int32
+	release     chan struct{}
+	releaseOnce sync.Once
+}
+
+func newSlowWhois(t *testing.T) *slowWhois {
+	t.Helper()
+	w := &slowWhois{release: make(chan struct{})}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w.queries.Add(1)
+		select {
+		case <-w.release:
+		case <-r.Context().Done():
+		}
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(func() {
+		w.unblock()
+		w.Close()
+	})
+	return w
+}
+
+func (w *slowWhois) unblock() {
+	w.releaseOnce.Do(func() { close(w.release) })
+}
+
+// newTestScheduler returns a scheduler whose monitor checks one domain against the WHOIS API
+func newTestScheduler(t *testing.T, apiURL string) *Scheduler {
+	t.Helper()
+	if err := database.InitDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := database.DB.Create(&models.Domain{Name: "example.com", IsActive: true}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	whois := services.NewWhoisService(&config.WhoisConfig{APIURL: apiURL, CacheTTL: "0"})
+	monitor := services.NewMonitorService(whois, nil, &config.MonitorConfig{})
+	s := NewScheduler(monitor, nil, nil)
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// waitFor polls the condition until it holds or the test times out after 5 seconds
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 5s")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSweepSkipsWhileRunning(t *testing.T) {
+	whois := newSlowWhois(t)
+	s := newTestScheduler(t, whois.URL)
+
+	done := make(chan struct{})
+	go func() {
+		s.sweep()
+		close(done)
+	}()
+	waitFor(t, func() bool { return whois.queries.Load() == 1 })
+
+	// The second tick returns at once instead of starting another sweep
+	skipped := make(chan struct{})
+	go func() {
+		s.sweep()
+		close(skipped)
+	}()
+	select {
+	case <-skipped:
+	case <-time.After(time.Second):
+		t.Fatal("second tick waited for the running sweep")
+	}
+	if err := s.RunNow(); err != ErrSweepRunning {
+		t.Errorf("RunNow() = %v, want ErrSweepRunning", err)
+	}
+
+	whois.unblock()
+	<-done
+	if got := whois.queries.Load(); got != 1 {
+		t.Errorf("WHOIS queried %d times, want only the first sweep", got)
+	}
+
+	// Once finished, the next tick runs again
+	s.sweep()
+	if got := whois.queries.Load(); got != 2 {
+		t.Errorf("WHOIS queried %d times, want the next tick to run", got)
+	}
+}
+
+func TestRunOverdueHoldsTheSweep(t *testing.T) {
+	whois := newSlowWhois(t)
+	s := newTestScheduler(t, whois.URL)
+	if err := s.Start("0 9 1 1 *", "@every 1h"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+
+	// The domain was never checked, so it is overdue
+	if err := s.RunOverdue(); err != nil {
+		t.Fatalf("RunOverdue: %v", err)
+	}
+	waitFor(t, func() bool { return whois.queries.Load() == 1 })
+
+	if err := s.RunNow(); err != ErrSweepRunning {
+		t.Errorf("RunNow() = %v, want ErrSweepRunning", err)
+	}
+	if _, err := s.StartRefresh(nil); err != ErrSweepRunning {
+		t.Errorf("StartRefresh() = %v, want ErrSweepRunning", err)
+	}
+
+	whois.unblock()
+	waitFor(t, func() bool { return !s.Status().SweepRunning })
+	if got := whois.queries.Load(); got != 1 {
+		t.Errorf("WHOIS queried %d times, want only the overdue check", got)
+	}
+}
+
+func TestStartRefreshHoldsTheSweep(t *testing.T) {
+	whois := newSlowWhois(t)
+	s := newTestScheduler(t, whois.URL)
+
+	var domains []models.Domain
+	if err := database.DB.Find(&domains).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.StartRefresh(domains); err != nil {
+		t.Fatalf("StartRefresh: %v", err)
+	}
+	waitFor(t, func() bool { return whois.queries.Load() == 1 })
+
+	// Scheduled ticks and manual sweeps wait for the refresh
+	s.sweep()
+	if err := s.RunNow(); err != ErrSweepRunning {
+		t.Errorf("RunNow() = %v, want ErrSweepRunning", err)
+	}
+	if got := whois.queries.Load(); got != 1 {
+		t.Errorf("WHOIS queried %d times, want only the refresh", got)
+	}
+
+	whois.unblock()
+	waitFor(t, func() bool { return !s.Status().SweepRunning })
+	if err := s.RunNow(); err != nil {
+		t.Errorf("RunNow() after the refresh = %v", err)
+	}
+}
+
+func TestReschedule(t *testing.T) {
+	s := newTestScheduler(t, "http://127.0.0.1:0")
+	if err := s.Start("0 9 * * *", "*/5 * * * *"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	oldEntry := s.entryID
+
+	if err := s.Reschedule("30 6 * * *"); err != nil {
+		t.Fatalf("Reschedule: %v", err)
+	}
+	status := s.Status()
+	if status.Interval != "30 6 * * *" {
+		t.Errorf("interval = %q, want %q", status.Interval, "30 6 * * *")
+	}
+	if status.NextRun == nil || status.NextRun.Hour() != 6 || status.NextRun.Minute() != 30 {
+		t.Errorf("next run = %v, want 06:30", status.NextRun)
+	}
+	if s.cron.Entry(oldEntry).Valid() {
+		t.Error("old sweep entry still scheduled")
+	}
+	if got := len(s.cron.Entries()); got != 1 {
+		t.Errorf("cron has %d entries, want 1", got)
+	}
+}
+
+func TestRescheduleInvalid(t *testing.T) {
+	s := newTestScheduler(t, "http://127.0.0.1:0")
+	if err := s.Start("0 9 * * *", "*/5 * * * *"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	entryID := s.entryID
+
+	for _, interval := range []string{"", "not a cron", "61 * * * *", "* * *"} {
+		if err := s.Reschedule(interval); err == nil {
+			t.Errorf("Reschedule(%q) succeeded, want an error", interval)
+		}
+	}
+
+	// The old schedule is untouched
+	if status := s.Status(); status.Interval != "0 9 * * *" || status.NextRun == nil {
+		t.Errorf("status = %+v, want the 09:00 schedule", status)
+	}
+	if s.entryID != entryID || !s.cron.Entry(entryID).Valid() {
+		t.Error("sweep entry replaced by an invalid reschedule")
+	}
+	if got := len(s.cron.Entries()); got != 1 {
+		t.Errorf("cron has %d entries, want 1", got)
+	}
+}
+
+// startSweeping starts the scheduler with a sweep every second and waits for the first one
+// to query the WHOIS API
+func startSweeping(t *testing.T, s *Scheduler, whois *slowWhois) {
+	t.Helper()
+	if err := s.Start("@every 1s", "@every 1h"); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	waitFor(t, func() bool { return whois.queries.Load() == 1 })
+}
+
+func TestShutdownWaitsForSweep(t *testing.T) {
+	whois := newSlowWhois(t)
+	s := newTestScheduler(t, whois.URL)
+	startSweeping(t, s, whois)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Shutdown(context.Background()) }()
+	select {
+	case err := <-stopped:
+		t.Fatalf("Shutdown() = %v before the running sweep finished", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if s.Status().Running {
+		t.Error("scheduler still reported as running during shutdown")
+	}
+
+	whois.unblock()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Shutdown() = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown didn't return after the sweep finished")
+	}
+}
+
+func TestShutdownTimeoutCancelsSweep(t *testing.T) {
+	whois := newSlowWhois(t)
+	s := newTestScheduler(t, whois.URL)
+	startSweeping(t, s, whois)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want context.DeadlineExceeded", err)
+	}
+	if s.ctx.Err() == nil {
+		t.Error("running checks not cancelled after the timeout")
+	}
+	// The cancelled sweep ends without the WHOIS API answering
+	waitFor(t, func() bool { return !s.Status().SweepRunning })
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyPrefix starts every API key, telling keys apart from login tokens
+const APIKeyPrefix = "jk_"
+
+const (
+	apiKeyPrefixLength = 10 // Characters of the key kept in plain text for display
+
+	// apiKeyTouchInterval limits last_used writes to one per key and interval
+	apiKeyTouchInterval = time.Minute
+)
+
+// ErrInvalidAPIKey is returned for unknown or revoked API keys
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// GenerateAPIKey returns a new random API key and the record storing its hash. The key
+// itself is not stored and can only be shown once.
+func (s *AuthService) GenerateAPIKey(label, role, createdBy string) (string, *models.APIKey, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, err
+	}
+	key := APIKeyPrefix + hex.EncodeToString(buf)
+
+	return key, &models.APIKey{
+		Label:     label,
+		KeyHash:   hashAPIKey(key),
+		Prefix:    key[:apiKeyPrefixLength],
+		Role:      role,
+		CreatedBy: createdBy,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// AuthenticateAPIKey looks up an active API key and returns claims for its role
+func (s *AuthService) AuthenticateAPIKey(key string) (*Claims, error) {
+	db := database.GetDB()
+	if db == nil {
+		return nil, database.ErrUnavailable
+	}
+
+	var apiKey models.APIKey
+	if err := db.Where("key_hash = ? AND is_active = ?", hashAPIKey(key), true).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if now := time.Now(); apiKey.LastUsed == nil || now.Sub(*apiKey.LastUsed) >= apiKeyTouchInterval {
+		if err := database.WithRetry(func(db *gorm.DB) error {
+			return db.Model(&models.APIKey{}).Where("id = ?", apiKey.ID).Update("last_used", now).Error
+		}); err != nil {
+			slog.Warn("Failed to record API key use", "api_key", apiKey.Prefix, "error", err)
+		}
+	}
+
+	return &Claims{
+		Username: "apikey:" + apiKey.Label,
+		Role:     apiKey.Role,
+	}, nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are random, so a fast hash suffices.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
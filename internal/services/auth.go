@@ -9,22 +9,42 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// JWT secret key - 在生产环境中应该使用环境变量
-var jwtSecret = []byte("your-secret-key-change-this-in-production")
+// DefaultJWTSecret is the placeholder secret shipped in the example config
+const DefaultJWTSecret = "your-secret-key-change-this-in-production"
 
 // Claims represents JWT claims
 type Claims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
+	Role     string `json:"role"`
 	jwt.RegisteredClaims
 }
 
+// UserRole returns the role of the token's user. Tokens issued before roles were added
+// carry none and belong to admins, as every user was one.
+func (c *Claims) UserRole() string {
+	if c.Role == "" {
+		return models.RoleAdmin
+	}
+	return c.Role
+}
+
+// DefaultRefreshGrace is how long after expiry a token can still be refreshed by default
+const DefaultRefreshGrace = 24 * time.Hour
+
+// ErrRefreshExpired is returned when a token expired longer ago than the refresh grace period
+var ErrRefreshExpired = errors.New("token expired beyond the refresh grace period")
+
 // AuthService handles authentication
-type AuthService struct{}
+type AuthService struct {
+	secret       []byte        // JWT signing key
+	refreshGrace time.Duration // How long after expiry a token can still be refreshed
+}
 
-// NewAuthService creates a new auth service
-func NewAuthService() *AuthService {
-	return &AuthService{}
+// NewAuthService creates a new auth service signing tokens with the given secret.
+// Tokens can be refreshed up to refreshGrace after they expire.
+func NewAuthService(secret string, refreshGrace time.Duration) *AuthService {
+	return &AuthService{secret: []byte(secret), refreshGrace: refreshGrace}
 }
 
 // HashPassword hashes a password using bcrypt
@@ -47,6 +67,7 @@ func (s *AuthService) GenerateToken(user *models.User) (string, error) {
 	claims := &Claims{
 		UserID:   user.ID,
 		Username: user.Username,
+		Role:     user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -55,14 +76,14 @@ func (s *AuthService) GenerateToken(user *models.User) (string, error) {
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(s.secret)
 }
 
 // ValidateToken validates a JWT token and returns claims
 func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
@@ -74,3 +95,25 @@ func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// RefreshToken checks a token that is still valid or expired within the refresh grace
+// period and returns its claims. The caller re-issues a token with GenerateToken after
+// checking that the user may still log in.
+func (s *AuthService) RefreshToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithLeeway(s.refreshGrace))
+
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrRefreshExpired
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.ExpiresAt == nil {
+		return nil, errors.New("invalid token")
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"domain-monitor/internal/models"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestValidateTokenSecret(t *testing.T) {
+	user := &models.User{ID: 1, Username: "admin", Role: models.RoleAdmin}
+	token, err := NewAuthService("secret-a", DefaultRefreshGrace).GenerateToken(user)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	claims, err := NewAuthService("secret-a", DefaultRefreshGrace).ValidateToken(token)
+	if err != nil {
+		t.Fatalf("token rejected under its own secret: %v", err)
+	}
+	if claims.Username != "admin" || claims.UserID != 1 {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := NewAuthService("secret-b", DefaultRefreshGrace).ValidateToken(token); err == nil {
+		t.Error("token signed with another secret was accepted")
+	}
+}
+
+// signedToken returns a token for user signed with secret that expired expiredFor ago
+// (in the future when negative)
+func signedToken(t *testing.T, secret string, user *models.User, expiredFor time.Duration) string {
+	t.Helper()
+	claims := &Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-expiredFor)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-expiredFor - 7*24*time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestRefreshToken(t *testing.T) {
+	auth := NewAuthService("secret", 24*time.Hour)
+	user := &models.User{ID: 1, Username: "admin", Role: models.RoleAdmin}
+
+	tests := []struct {
+		name       string
+		token      string
+		wantErr    bool
+		wantExpiry bool // ErrRefreshExpired
+	}{
+		{"valid", signedToken(t, "secret", user, -time.Hour), false, false},
+		{"expired within grace", signedToken(t, "secret", user, 2*time.Hour), false, false},
+		{"expired beyond grace", signedToken(t, "secret", user, 25*time.Hour), true, true},
+		{"other secret", signedToken(t, "other", user, -time.Hour), true, false},
+		{"malformed", "not-a-token", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := auth.RefreshToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RefreshToken() error = %v, want error %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrRefreshExpired) != tt.wantExpiry {
+				t.Errorf("RefreshToken() error = %v, want ErrRefreshExpired %v", err, tt.wantExpiry)
+			}
+			if err == nil && claims.UserID != user.ID {
+				t.Errorf("claims = %+v, want user %d", claims, user.ID)
+			}
+		})
+	}
+
+	// Expired tokens still fail normal validation
+	if _, err := auth.ValidateToken(signedToken(t, "secret", user, 2*time.Hour)); err == nil {
+		t.Error("ValidateToken accepted an expired token")
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Certificate check states recorded on the domain
+const (
+	CertStatusOK              = "ok"
+	CertStatusRefused         = "refused"
+	CertStatusTimeout         = "timeout"
+	CertStatusDNSError        = "dns_error"
+	CertStatusHandshakeFailed = "handshake_failed"
+	CertStatusError           = "error"
+)
+
+// CertResult represents the outcome of a TLS certificate check
+type CertResult struct {
+	Status string
+	Expiry time.Time
+	Issuer string
+	Error  string
+}
+
+// CertService checks TLS certificates of monitored domains
+type CertService struct {
+	Port        int
+	Timeout     time.Duration
+	Concurrency int
+}
+
+// NewCertService creates a new certificate service
+func NewCertService(port int, timeout time.Duration, concurrency int) *CertService {
+	if port <= 0 {
+		port = 443
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	return &CertService{
+		Port:        port,
+		Timeout:     timeout,
+		Concurrency: concurrency,
+	}
+}
+
+// CheckCertificate connects to the host and inspects its TLS certificate
+func (s *CertService) CheckCertificate(host string) *CertResult {
+	addr := net.JoinHostPort(host, strconv.Itoa(s.Port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+
+	// Dial separately from the handshake so failures can be told apart
+	dialer := &net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return &CertResult{Status: classifyDialError(err), Error: err.Error()}
+	}
+	defer conn.Close()
+
+	tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		status := CertStatusHandshakeFailed
+		if isTimeout(err) {
+			status = CertStatusTimeout
+		}
+		return &CertResult{Status: status, Error: err.Error()}
+	}
+
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return &CertResult{Status: CertStatusError, Error: "no peer certificate presented"}
+	}
+
+	return &CertResult{
+		Status: CertStatusOK,
+		Expiry: certs[0].NotAfter,
+		Issuer: certs[0].Issuer.CommonName,
+	}
+}
+
+// CheckAllCertificates checks the certificates of all active domains using a bounded worker pool
+func (s *CertService) CheckAllCertificates() error {
+	var domains []models.Domain
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Where("is_active = ?", true).Find(&domains).Error
+	}); err != nil {
+		return fmt.Errorf("failed to fetch domains: %w", err)
+	}
+
+	slog.Info("Checking TLS certificates", "count", len(domains), "concurrency", s.Concurrency)
+
+	runPool(domains, s.Concurrency, func(domain models.Domain) {
+		result := s.CheckCertificate(domain.Name)
+
+		updates := map[string]interface{}{
+			"cert_status":  result.Status,
+			"cert_error":   result.Error,
+			"cert_checked": time.Now(),
+		}
+		if result.Status == CertStatusOK {
+			updates["cert_expiry"] = result.Expiry
+			updates["cert_issuer"] = result.Issuer
+		}
+
+		if err := database.WithRetry(func(db *gorm.DB) error {
+			return db.Model(&models.Domain{}).Where("id = ?", domain.ID).Updates(updates).Error
+		}); err != nil {
+			slog.Error("Failed to save certificate result", "domain", domain.Name, "error", err)
+			return
+		}
+
+		if result.Status != CertStatusOK {
+			slog.Warn("Certificate check failed", "domain", domain.Name, "status", result.Status, "error", result.Error)
+		}
+	})
+
+	return nil
+}
+
+// classifyDialError maps a dial error to a certificate check state
+func classifyDialError(err error) string {
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr):
+		return CertStatusDNSError
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return CertStatusRefused
+	case isTimeout(err):
+		return CertStatusTimeout
+	default:
+		return CertStatusError
+	}
+}
+
+// isTimeout reports whether err is a timeout or deadline error
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Channel field types understood by the settings UI
+const (
+	FieldString   = "string"
+	FieldNumber   = "number"
+	FieldBool     = "bool"
+	FieldPassword = "password"
+	FieldList     = "list" // Comma separated values
+)
+
+// ChannelField describes a single configuration field of a notification channel
+type ChannelField struct {
+	Key      string `json:"key"`   // Settings key, e.g. email.smtp_host
+	Label    string `json:"label"` // Display label
+	Type     string `json:"type"`  // Field type (string/number/bool/password/list)
+	Required bool   `json:"required"`
+}
+
+// ChannelInfo describes a supported notification channel
+type ChannelInfo struct {
+	Type   string         `json:"type"`   // Channel identifier
+	Name   string         `json:"name"`   // Display name
+	Fields []ChannelField `json:"fields"` // Configuration fields
+
+	enabled func(cfg *config.NotificationsConfig) bool
+	build   func(cfg *config.NotificationsConfig) Notifier
+}
+
+// channels is the registry of all supported notification channels
+var channels = []ChannelInfo{
+	{
+		Type: "email",
+		Name: "邮件",
+		Fields: []ChannelField{
+			{Key: "email.enabled", Label: "启用", Type: FieldBool},
+			{Key: "email.smtp_host", Label: "SMTP 服务器", Type: FieldString, Required: true},
+			{Key: "email.smtp_port", Label: "SMTP 端口", Type: FieldNumber, Required: true},
+			{Key: "email.use_ssl", Label: "SSL/TLS 加密（465 端口）", Type: FieldBool},
+			{Key: "email.from", Label: "发件人", Type: FieldString, Required: true},
+			{Key: "email.password", Label: "密码/授权码", Type: FieldPassword, Required: true},
+			{Key: "email.to", Label: "收件人", Type: FieldList, Required: true},
+			{Key: "email.format", Label: "邮件格式（text/html）", Type: FieldString},
+			{Key: "email.template", Label: "HTML 模板文件名（位于模板目录）", Type: FieldString},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Email.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewEmailNotifier(&cfg.Email, cfg.TemplateDir) },
+	},
+	{
+		Type: "webhook",
+		Name: "Webhook",
+		Fields: []ChannelField{
+			{Key: "webhook.enabled", Label: "启用", Type: FieldBool},
+			{Key: "webhook.url", Label: "Webhook 地址", Type: FieldString, Required: true},
+			{Key: "webhook.method", Label: "请求方法（默认 POST）", Type: FieldString},
+			{Key: "webhook.template", Label: "请求体模板", Type: FieldString},
+			{Key: "webhook.headers", Label: "自定义请求头（JSON）", Type: FieldString},
+			{Key: "webhook.secret", Label: "签名密钥", Type: FieldPassword},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Webhook.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewWebhookNotifier(&cfg.Webhook) },
+	},
+	{
+		Type: "telegram",
+		Name: "Telegram",
+		Fields: []ChannelField{
+			{Key: "telegram.enabled", Label: "启用", Type: FieldBool},
+			{Key: "telegram.bot_token", Label: "Bot Token", Type: FieldPassword, Required: true},
+			{Key: "telegram.chat_id", Label: "Chat ID", Type: FieldString, Required: true},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Telegram.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewTelegramNotifier(&cfg.Telegram) },
+	},
+	{
+		Type: "dingding",
+		Name: "钉钉",
+		Fields: []ChannelField{
+			{Key: "dingding.enabled", Label: "启用", Type: FieldBool},
+			{Key: "dingding.webhook", Label: "Webhook 地址", Type: FieldPassword, Required: true},
+			{Key: "dingding.secret", Label: "加签密钥", Type: FieldPassword},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.DingDing.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewDingDingNotifier(&cfg.DingDing) },
+	},
+	{
+		Type: "feishu",
+		Name: "飞书",
+		Fields: []ChannelField{
+			{Key: "feishu.enabled", Label: "启用", Type: FieldBool},
+			{Key: "feishu.webhook", Label: "Webhook 地址", Type: FieldPassword, Required: true},
+			{Key: "feishu.secret", Label: "签名校验密钥", Type: FieldPassword},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Feishu.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewFeishuNotifier(&cfg.Feishu) },
+	},
+	{
+		Type: "wecom",
+		Name: "企业微信",
+		Fields: []ChannelField{
+			{Key: "wecom.enabled", Label: "启用", Type: FieldBool},
+			{Key: "wecom.webhook_url", Label: "Webhook 地址", Type: FieldPassword, Required: true},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.WeCom.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewWeComNotifier(&cfg.WeCom) },
+	},
+	{
+		Type: "bark",
+		Name: "Bark",
+		Fields: []ChannelField{
+			{Key: "bark.enabled", Label: "启用", Type: FieldBool},
+			{Key: "bark.server_url", Label: "服务器地址（默认 https://api.day.app）", Type: FieldString},
+			{Key: "bark.device_key", Label: "Device Key", Type: FieldPassword, Required: true},
+			{Key: "bark.group", Label: "分组", Type: FieldString},
+			{Key: "bark.sound", Label: "提示音", Type: FieldString},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Bark.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewBarkNotifier(&cfg.Bark) },
+	},
+	{
+		Type: "serverchan",
+		Name: "Server酱",
+		Fields: []ChannelField{
+			{Key: "serverchan.enabled", Label: "启用", Type: FieldBool},
+			{Key: "serverchan.send_key", Label: "SendKey", Type: FieldPassword, Required: true},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.ServerChan.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewServerChanNotifier(&cfg.ServerChan) },
+	},
+	{
+		Type: "pushover",
+		Name: "Pushover",
+		Fields: []ChannelField{
+			{Key: "pushover.enabled", Label: "启用", Type: FieldBool},
+			{Key: "pushover.token", Label: "API Token", Type: FieldPassword, Required: true},
+			{Key: "pushover.user", Label: "User Key", Type: FieldString, Required: true},
+			{Key: "pushover.priority", Label: "优先级（-2 到 1，默认 0）", Type: FieldNumber},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Pushover.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewPushoverNotifier(&cfg.Pushover) },
+	},
+	{
+		Type: "gotify",
+		Name: "Gotify",
+		Fields: []ChannelField{
+			{Key: "gotify.enabled", Label: "启用", Type: FieldBool},
+			{Key: "gotify.server_url", Label: "服务器地址", Type: FieldString, Required: true},
+			{Key: "gotify.app_token", Label: "应用 Token", Type: FieldPassword, Required: true},
+			{Key: "gotify.priority", Label: "优先级（0-10，默认 0）", Type: FieldNumber},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Gotify.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewGotifyNotifier(&cfg.Gotify) },
+	},
+	{
+		Type: "teams",
+		Name: "Microsoft Teams",
+		Fields: []ChannelField{
+			{Key: "teams.enabled", Label: "启用", Type: FieldBool},
+			{Key: "teams.webhook_url", Label: "Webhook 地址", Type: FieldPassword, Required: true},
+		},
+		enabled: func(cfg *config.NotificationsConfig) bool { return cfg.Teams.Enabled },
+		build:   func(cfg *config.NotificationsConfig) Notifier { return NewTeamsNotifier(&cfg.Teams) },
+	},
+}
+
+// SupportedChannels returns metadata for all supported notification channels
+func SupportedChannels() []ChannelInfo {
+	return channels
+}
+
+// ErrUnknownChannel is returned for notification channel types that are not supported
+var ErrUnknownChannel = errors.New("unknown notification channel")
+
+// TestChannel sends a sample expiry alert for example.com through a single channel, built
+// from the configuration even if the channel is disabled, and returns the send error
+func TestChannel(channelType string, cfg *config.NotificationsConfig) error {
+	for _, channel := range channels {
+		if channel.Type != channelType {
+			continue
+		}
+
+		domain := &models.Domain{
+			Name:          "example.com",
+			Registrar:     "Example Registrar",
+			Status:        "clientTransferProhibited",
+			DaysRemaining: 7,
+			ExpiryDate:    models.NullTime(time.Now().AddDate(0, 0, 7)),
+		}
+		return channel.build(cfg).Send(&Alert{
+			Kind:          AlertExpiry,
+			Domain:        domain,
+			DaysRemaining: domain.DaysRemaining,
+			Severity:      severityForDays(domain.DaysRemaining),
+			Language:      cfg.Language,
+		})
+	}
+
+	return fmt.Errorf("%w: %s", ErrUnknownChannel, channelType)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"domain-monitor/internal/models"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DomainAlert is one domain of a digest notification
+type DomainAlert struct {
+	Domain        *models.Domain
+	DaysRemaining int
+	Threshold     int // Alert day that was crossed
+	Severity      string
+}
+
+// SendDigest sends each channel a single notification listing the given expiry alerts
+// that the domains' alert policies route to it. It returns the alerts that were delivered,
+// like single alerts: through at least one channel, or with no channel to send them to.
+func (s *NotifyService) SendDigest(items []DomainAlert) ([]DomainAlert, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	items = append([]DomainAlert(nil), items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DaysRemaining < items[j].DaysRemaining
+	})
+
+	policies := make([]*models.AlertPolicy, len(items))
+	for i, item := range items {
+		if item.Severity == "" {
+			items[i].Severity = s.buildAlert(item.Domain, item.DaysRemaining, item.Threshold).Severity
+		}
+		policies[i] = LoadAlertPolicy(item.Domain)
+	}
+
+	var (
+		mu        sync.Mutex
+		lastErr   error
+		attempted = make([]bool, len(items))
+		sent      = make([]bool, len(items))
+	)
+	runPool(s.notifiers, s.concurrency, func(notifier Notifier) {
+		var indexes []int
+		var channelItems []DomainAlert
+		for i, item := range items {
+			if policies[i] == nil || policies[i].AllowsChannel(notifier.Type()) {
+				indexes = append(indexes, i)
+				channelItems = append(channelItems, item)
+			}
+		}
+		if len(channelItems) == 0 {
+			return
+		}
+
+		err := s.sendTo(notifier, s.digestAlert(channelItems), nil, nil)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			lastErr = err
+		}
+		for _, i := range indexes {
+			attempted[i] = true
+			sent[i] = sent[i] || err == nil
+		}
+	})
+
+	delivered := make([]DomainAlert, 0, len(items))
+	for i, item := range items {
+		if sent[i] || !attempted[i] {
+			delivered = append(delivered, item)
+		}
+	}
+	return delivered, lastErr
+}
+
+// digestAlert builds the digest notification of the given expiry alerts
+func (s *NotifyService) digestAlert(items []DomainAlert) *Alert {
+	m := s.Messages()
+	alert := &Alert{
+		Kind:     AlertDigest,
+		Severity: SeverityInfo,
+		Title:    fmt.Sprintf(m.DigestTitle, len(items)),
+		Digest:   items,
+		Message:  digestTable(m, items, false),
+	}
+	for _, item := range items {
+		if severityRank[item.Severity] > severityRank[alert.Severity] {
+			alert.Severity = item.Severity
+		}
+	}
+	return alert
+}
+
+// severityRank orders severities from least to most urgent
+var severityRank = map[string]int{
+	SeverityInfo:     0,
+	SeverityWarning:  1,
+	SeverityCritical: 2,
+}
+
+// digestTable lists the digest domains as a markdown table or as plain text lines
+func digestTable(m *Messages, items []DomainAlert, markdown bool) string {
+	var b strings.Builder
+	if markdown {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n| --- | --- | --- |\n", m.Domain, m.DaysRemaining, m.ExpiryDate)
+	}
+	for _, item := range items {
+		expiry := models.FormatDate(models.TimeValue(item.Domain.ExpiryDate))
+		if markdown {
+			fmt.Fprintf(&b, "| %s %s | %d | %s |\n", severityEmoji(item.Severity), item.Domain.Name, item.DaysRemaining, expiry)
+		} else {
+			fmt.Fprintf(&b, m.DigestLine+"\n", severityEmoji(item.Severity), item.Domain.Name, item.DaysRemaining, expiry)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// markdownMessage returns the alert details for markdown channels, with digests as a table
+func (a *Alert) markdownMessage() string {
+	if a.Kind == AlertDigest {
+		return digestTable(a.messages(), a.Digest, true)
+	}
+	return a.Message
+}
+
+// sendExpiry sends an expiry notification, or adds it to the digest of the running check.
+// Queued notifications are recorded as sent by flushDigest once the digest is delivered.
+func (s *MonitorService) sendExpiry(domain *models.Domain, threshold int) (queued bool, err error) {
+	s.digestMu.Lock()
+	if s.digest != nil {
+		*s.digest = append(*s.digest, DomainAlert{
+			Domain:        domain,
+			DaysRemaining: domain.DaysRemaining,
+			Threshold:     threshold,
+		})
+		s.digestMu.Unlock()
+		return true, nil
+	}
+	s.digestMu.Unlock()
+
+	return false, s.notifyService.SendThresholdNotification(domain, threshold)
+}
+
+// beginDigest starts collecting expiry notifications when digest mode is enabled
+func (s *MonitorService) beginDigest() {
+	if !s.digestMode || s.notifyService == nil {
+		return
+	}
+
+	s.digestMu.Lock()
+	defer s.digestMu.Unlock()
+	if s.digest == nil {
+		s.digest = &[]DomainAlert{}
+	}
+}
+
+// flushDigest sends the collected expiry notifications as one digest
+func (s *MonitorService) flushDigest() {
+	s.digestMu.Lock()
+	digest := s.digest
+	s.digest = nil
+	s.digestMu.Unlock()
+
+	if digest == nil || len(*digest) == 0 {
+		return
+	}
+
+	slog.Info("Sending digest notification", "count", len(*digest))
+	delivered, err := s.notifyService.SendDigest(*digest)
+	if err != nil {
+		slog.Error("Failed to send digest notification", "delivered", len(delivered), "count", len(*digest), "error", err)
+	}
+
+	// Undelivered alerts stay pending and are queued again by the next check
+	for _, item := range delivered {
+		if s.alertMode == AlertModeTransition {
+			s.setLastBucket(item.Domain, urgencyBucket(item.DaysRemaining))
+		} else {
+			threshold := item.Threshold
+			s.setLastAlertThreshold(item.Domain, &threshold)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDigestGroupsExpiryAlerts(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{AlertDays: []int{30, 7, 1}, DigestMode: true})
+	api.expireIn(200)
+	for name, days := range map[string]int{"a.example": 25, "b.example": 5, "c.example": 200} {
+		createTestDomain(t, name)
+		api.expireDomainIn(name, days)
+	}
+
+	if err := monitor.CheckAllDomains(); err != nil {
+		t.Fatalf("CheckAllDomains: %v", err)
+	}
+
+	if got := len(channel.sentKind(AlertExpiry)); got != 0 {
+		t.Errorf("sent %d single expiry alerts in digest mode", got)
+	}
+	digests := channel.sentKind(AlertDigest)
+	if len(digests) != 1 {
+		t.Fatalf("sent %d digests, want 1", len(digests))
+	}
+
+	// Sorted by urgency; the domain far from expiry is left out
+	var names []string
+	for _, item := range digests[0].Digest {
+		names = append(names, item.Domain.Name)
+	}
+	if want := []string{"b.example", "a.example"}; !slices.Equal(names, want) {
+		t.Errorf("digest domains = %v, want %v", names, want)
+	}
+	if digests[0].Severity != SeverityCritical {
+		t.Errorf("digest severity = %q, want the most urgent item's", digests[0].Severity)
+	}
+	if !strings.Contains(digests[0].Message, "a.example") || !strings.Contains(digests[0].Message, "b.example") {
+		t.Errorf("digest message = %q", digests[0].Message)
+	}
+
+	// Delivered items are recorded, so the next run doesn't repeat them
+	if err := monitor.CheckAllDomains(); err != nil {
+		t.Fatalf("CheckAllDomains: %v", err)
+	}
+	if got := len(channel.sentKind(AlertDigest)); got != 1 {
+		t.Errorf("sent %d digests after a second run, want 1", got)
+	}
+}
+
+func TestDigestTableMarkdown(t *testing.T) {
+	s := NewNotifyService(&config.NotificationsConfig{})
+	alert := s.digestAlert([]DomainAlert{
+		{Domain: testExpiryAlert().Domain, DaysRemaining: 7, Severity: SeverityWarning},
+	})
+
+	table := alert.markdownMessage()
+	lines := strings.Split(table, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "| ---") || !strings.Contains(lines[2], "example.com |") || !strings.Contains(lines[2], "| 7 |") {
+		t.Errorf("markdown table = %q", table)
+	}
+}
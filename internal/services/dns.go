@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultDNSRecordTypes are watched when neither the domain nor dns.record_types lists any
+var defaultDNSRecordTypes = []string{"A", "AAAA", "MX", "NS"}
+
+// DNSResolver looks up DNS records; *net.Resolver implements it
+type DNSResolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DNSService resolves the watched DNS records of monitored domains
+type DNSService struct {
+	Resolver    DNSResolver
+	RecordTypes []string      // Record types watched for domains without their own list
+	Timeout     time.Duration // Timeout of the lookups of one domain
+}
+
+// NewDNSService creates a DNS service using the configured DNS server, or the system resolver
+func NewDNSService(cfg *config.DNSConfig) (*DNSService, error) {
+	recordTypes, err := models.ParseDNSRecordTypes(strings.Join(cfg.RecordTypes, ","))
+	if err != nil {
+		return nil, err
+	}
+	if len(recordTypes) == 0 {
+		recordTypes = defaultDNSRecordTypes
+	}
+
+	timeout := 10 * time.Second
+	if cfg.Timeout != "" {
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+	}
+
+	resolver := net.DefaultResolver
+	if cfg.Resolver != "" {
+		server := cfg.Resolver
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	return &DNSService{
+		Resolver:    resolver,
+		RecordTypes: recordTypes,
+		Timeout:     timeout,
+	}, nil
+}
+
+// RecordTypesFor returns the record types watched for a domain
+func (s *DNSService) RecordTypesFor(domain *models.Domain) []string {
+	if recordTypes, err := models.ParseDNSRecordTypes(domain.DNSRecordTypes); err == nil && len(recordTypes) > 0 {
+		return recordTypes
+	}
+	return s.RecordTypes
+}
+
+// Resolve looks up the watched records of a domain. Values are normalized and sorted so
+// that snapshots can be compared; a type without records maps to an empty list.
+func (s *DNSService) Resolve(ctx context.Context, domain *models.Domain) (map[string][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.Timeout)
+	defer cancel()
+
+	records := make(map[string][]string)
+	for _, recordType := range s.RecordTypesFor(domain) {
+		values, err := s.lookup(ctx, recordType, domain.Name)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+				return nil, fmt.Errorf("%s lookup failed: %w", recordType, err)
+			}
+			values = nil
+		}
+		records[recordType] = normalizeRecords(recordType, values)
+	}
+	return records, nil
+}
+
+// lookup returns the values of one record type
+func (s *DNSService) lookup(ctx context.Context, recordType, name string) ([]string, error) {
+	values := make([]string, 0)
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := s.Resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			values = append(values, ip.String())
+		}
+	case "CNAME":
+		cname, err := s.Resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		// Names without a CNAME record resolve to themselves
+		if !strings.EqualFold(strings.TrimSuffix(cname, "."), strings.TrimSuffix(name, ".")) {
+			values = append(values, cname)
+		}
+	case "MX":
+		mxs, err := s.Resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			values = append(values, fmt.Sprintf("%d %s", mx.Pref, mx.Host))
+		}
+	case "NS":
+		nss, err := s.Resolver.LookupNS(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			values = append(values, ns.Host)
+		}
+	case "TXT":
+		txts, err := s.Resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		values = txts
+	default:
+		return nil, fmt.Errorf("unsupported DNS record type %q", recordType)
+	}
+	return values, nil
+}
+
+// normalizeRecords sorts record values and removes duplicates. Host names are lower-cased
+// without the trailing dot; TXT values are case-sensitive and kept as is.
+func normalizeRecords(recordType string, values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if recordType != "TXT" {
+			value = strings.TrimSuffix(strings.ToLower(value), ".")
+		}
+		normalized = append(normalized, value)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// dnsChanges describes the record types whose values differ between two snapshots. Types
+// missing from the previous snapshot were just added to the watch list and aren't reported.
+func dnsChanges(m *Messages, previous, current map[string][]string) []string {
+	changes := make([]string, 0)
+	for _, recordType := range models.SupportedDNSRecordTypes {
+		before, watched := previous[recordType]
+		after, ok := current[recordType]
+		if !watched || !ok || slices.Equal(before, after) {
+			continue
+		}
+		changes = append(changes, m.Field(recordType, recordList(m, before)+" → "+recordList(m, after)))
+	}
+	return changes
+}
+
+// recordList formats record values for an alert
+func recordList(m *Messages, values []string) string {
+	if len(values) == 0 {
+		return m.NoRecords
+	}
+	return strings.Join(values, ", ")
+}
+
+// checkDNS resolves the watched records of a domain, stores the snapshot and alerts when
+// the records changed since the previous check. Lookup failures are only logged, since a
+// flaky resolver says nothing about the domain.
+func (s *MonitorService) checkDNS(ctx context.Context, domain *models.Domain) {
+	records, err := s.dnsService.Resolve(ctx, domain)
+	if err != nil {
+		slog.WarnContext(ctx, "DNS lookup failed", "domain", domain.Name, "error", err)
+		return
+	}
+
+	changes := dnsChanges(s.Messages(), domain.DNSRecordSet(), records)
+
+	domain.SetDNSRecords(records)
+	domain.DNSChecked = models.NullTime(time.Now())
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", domain.ID).UpdateColumns(map[string]interface{}{
+			"dns_records": domain.DNSRecords,
+			"dns_checked": domain.DNSChecked,
+		}).Error
+	}); err != nil {
+		slog.ErrorContext(ctx, "Failed to save DNS records", "domain", domain.Name, "error", err)
+		return
+	}
+
+	if len(changes) > 0 {
+		s.notifyDNSChange(domain, changes)
+	}
+}
+
+// notifyDNSChange sends an alert about changed DNS records
+func (s *MonitorService) notifyDNSChange(domain *models.Domain, changes []string) {
+	slog.Warn("Domain DNS records changed", "domain", domain.Name, "changes", strings.Join(changes, "; "))
+
+	if !s.canNotify(domain) {
+		return
+	}
+
+	m := s.Messages()
+	alert := &Alert{
+		Kind:          AlertDNS,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+		Severity:      SeverityCritical,
+		Title:         m.DNSChangeTitle,
+		Message:       fmt.Sprintf(m.DNSChangeMessage, strings.Join(changes, "\n")),
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send DNS change notification", "domain", domain.Name, "error", err)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubResolver answers DNS lookups from records that can be changed between checks.
+// Missing records are reported as not found.
+type stubResolver struct {
+	mu      sync.Mutex
+	records map[string][]string // Record type -> values
+	err     error               // Returned by every lookup when set
+}
+
+func (r *stubResolver) set(recordType string, values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records[recordType] = values
+}
+
+func (r *stubResolver) setError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.err = err
+}
+
+func (r *stubResolver) get(recordType, name string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	values, ok := r.records[recordType]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return values, nil
+}
+
+func (r *stubResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	recordType := "A"
+	if network == "ip6" {
+		recordType = "AAAA"
+	}
+	values, err := r.get(recordType, host)
+	ips := make([]net.IP, 0, len(values))
+	for _, v := range values {
+		ips = append(ips, net.ParseIP(v))
+	}
+	return ips, err
+}
+
+func (r *stubResolver) LookupCNAME(ctx context.Context, host string) (string, error) {
+	values, err := r.get("CNAME", host)
+	if err != nil || len(values) == 0 {
+		return host + ".", err
+	}
+	return values[0], nil
+}
+
+func (r *stubResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	values, err := r.get("MX", name)
+	mxs := make([]*net.MX, 0, len(values))
+	for _, v := range values {
+		mxs = append(mxs, &net.MX{Host: v, Pref: 10})
+	}
+	return mxs, err
+}
+
+func (r *stubResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	values, err := r.get("NS", name)
+	nss := make([]*net.NS, 0, len(values))
+	for _, v := range values {
+		nss = append(nss, &net.NS{Host: v})
+	}
+	return nss, err
+}
+
+func (r *stubResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.get("TXT", name)
+}
+
+// newStubDNSService returns a DNS service watching A and NS records through a stub resolver
+func newStubDNSService() (*DNSService, *stubResolver) {
+	resolver := &stubResolver{records: map[string][]string{
+		"A":  {"192.0.2.1"},
+		"NS": {"ns1.example.net."},
+	}}
+	return &DNSService{Resolver: resolver, RecordTypes: []string{"A", "NS"}, Timeout: time.Second}, resolver
+}
+
+func TestDNSResolve(t *testing.T) {
+	dns, resolver := newStubDNSService()
+	resolver.set("A", "192.0.2.2", "192.0.2.1", "192.0.2.2")
+	resolver.set("NS", "NS2.Example.NET.", "ns1.example.net.")
+
+	records, err := dns.Resolve(context.Background(), &models.Domain{Name: "example.com", DNSRecordTypes: "a, ns, mx, txt"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	want := map[string][]string{
+		"A":   {"192.0.2.1", "192.0.2.2"},
+		"NS":  {"ns1.example.net", "ns2.example.net"},
+		"MX":  {},
+		"TXT": {},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records = %v, want %v", records, want)
+	}
+
+	resolver.setError(errors.New("connection refused"))
+	if _, err := dns.Resolve(context.Background(), &models.Domain{Name: "example.com"}); err == nil {
+		t.Error("Resolve succeeded while the resolver fails")
+	}
+}
+
+func TestCheckDomainAlertsOnDNSChange(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(200)
+	dns, resolver := newStubDNSService()
+	monitor.SetDNSService(dns)
+	domain := createTestDomain(t, "example.com")
+
+	// The first check stores the snapshot without alerting
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if alerts := channel.sentKind(AlertDNS); len(alerts) != 0 {
+		t.Fatalf("%d DNS alerts on the first check, want 0", len(alerts))
+	}
+	var stored models.Domain
+	database.DB.First(&stored, domain.ID)
+	if got := stored.DNSRecordSet(); !reflect.DeepEqual(got["A"], []string{"192.0.2.1"}) || stored.DNSChecked == nil {
+		t.Fatalf("stored snapshot = %v (checked %v), want the A record", got, stored.DNSChecked)
+	}
+
+	resolver.set("A", "203.0.113.9")
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	alerts := channel.sentKind(AlertDNS)
+	if len(alerts) != 1 {
+		t.Fatalf("%d DNS alerts after the A record changed, want 1", len(alerts))
+	}
+	if msg := alerts[0].Message; !strings.Contains(msg, "192.0.2.1") || !strings.Contains(msg, "203.0.113.9") || strings.Contains(msg, "ns1.example.net") {
+		t.Errorf("alert message = %q, want only the A record change", msg)
+	}
+
+	// Unchanged records don't alert again
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if alerts := channel.sentKind(AlertDNS); len(alerts) != 1 {
+		t.Errorf("%d DNS alerts after an unchanged check, want 1", len(alerts))
+	}
+}
+
+func TestCheckDomainDNSLookupFailure(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(200)
+	dns, resolver := newStubDNSService()
+	monitor.SetDNSService(dns)
+	domain := createTestDomain(t, "example.com")
+
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+
+	// A failing resolver keeps the previous snapshot and doesn't alert
+	resolver.setError(errors.New("timeout"))
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain failed because of DNS: %v", err)
+	}
+	resolver.setError(nil)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if alerts := channel.sentKind(AlertDNS); len(alerts) != 0 {
+		t.Errorf("%d DNS alerts after a lookup failure, want 0", len(alerts))
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// ErrInvalidDomain is returned for names that are not a registrable domain
+var ErrInvalidDomain = errors.New("invalid domain name")
+
+// NormalizeDomain turns user input such as "http://Bücher.DE/path" into the registrable
+// domain name that is monitored ("xn--bcher-kva.de"). Subdomains are rejected.
+func NormalizeDomain(input string) (string, error) {
+	name := strings.TrimSpace(input)
+
+	// Accept URLs and host:port by keeping only the host
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	}
+	if i := strings.IndexAny(name, "/?#"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(name, ".")
+
+	if name == "" {
+		return "", fmt.Errorf("%w: name is empty", ErrInvalidDomain)
+	}
+
+	ascii, err := idna.Lookup.ToASCII(name)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", ErrInvalidDomain, input, err)
+	}
+
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(ascii)
+	if err != nil {
+		return "", fmt.Errorf("%w %q: not a registrable domain", ErrInvalidDomain, input)
+	}
+	if registrable != ascii {
+		return "", fmt.Errorf("%w %q: %s is a subdomain, monitor %s instead", ErrInvalidDomain, input, ascii, registrable)
+	}
+
+	return ascii, nil
+}
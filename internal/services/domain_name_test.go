@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // "" when the input is invalid
+	}{
+		{"example.com", "example.com"},
+		{"Example.COM", "example.com"},
+		{"  example.com.  ", "example.com"},
+		{"http://Example.COM/", "example.com"},
+		{"https://user@example.com:8443/path?q=1#top", "example.com"},
+		{"example.com:443", "example.com"},
+		{"Bücher.DE", "xn--bcher-kva.de"},
+		{"https://例子.中国/", "xn--fsqu00a.xn--fiqs8s"},
+		{"example.co.uk", "example.co.uk"},
+		{"", ""},
+		{"http://", ""},
+		{"com", ""},
+		{"co.uk", ""},
+		{"www.example.com", ""},
+		{"exa mple.com", ""},
+		{"-example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizeDomain(tt.input)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalidDomain) {
+					t.Errorf("NormalizeDomain(%q) = %q, %v; want ErrInvalidDomain", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizeDomain(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// setupTestDB points the database package at a fresh SQLite database
+func setupTestDB(t *testing.T) {
+	t.Helper()
+	if err := database.InitDB(&config.DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "test.db")}); err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+}
+
+// fakeNotifier records the alerts it is asked to send and fails while fail is set
+type fakeNotifier struct {
+	channel string
+
+	mu     sync.Mutex
+	alerts []*Alert
+	fail   bool
+}
+
+func (f *fakeNotifier) Type() string { return f.channel }
+
+func (f *fakeNotifier) Send(alert *Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fail {
+		return permanent(errors.New("channel down"))
+	}
+	f.alerts = append(f.alerts, alert)
+	return nil
+}
+
+// sent returns the alerts delivered so far
+func (f *fakeNotifier) sent() []*Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*Alert(nil), f.alerts...)
+}
+
+// fakeWhoisAPI serves WHOIS API answers whose expiry date can be changed between checks
+type fakeWhoisAPI struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	expiry    time.Time
+	expiries  map[string]time.Time // Per-domain expiry dates overriding expiry
+	registrar string
+	servers   []string // Name servers
+	failing   bool     // Answer 503 to every query
+	queries   int
+	delay     time.Duration // Time taken by each query
+
+	inFlight, maxInFlight int
+}
+
+func newFakeWhoisAPI(t *testing.T) *fakeWhoisAPI {
+	t.Helper()
+	f := &fakeWhoisAPI{registrar: "Example Registrar", expiries: map[string]time.Time{}}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.queries++
+		f.inFlight++
+		f.maxInFlight = max(f.maxInFlight, f.inFlight)
+		delay := f.delay
+		f.mu.Unlock()
+
+		time.Sleep(delay)
+
+		f.mu.Lock()
+		f.inFlight--
+		if f.failing {
+			f.mu.Unlock()
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		expiry, ok := f.expiries[r.URL.Query().Get("domain")]
+		if !ok {
+			expiry = f.expiry
+		}
+		data, _ := json.Marshal(map[string]any{
+			"registrar":      f.registrar,
+			"expirationDate": expiry.UTC().Format(time.RFC3339),
+			"nameServers":    f.servers,
+		})
+		f.mu.Unlock()
+		fmt.Fprintf(w, `{"code":0,"data":%s}`, data)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+// expireIn makes the API report an expiry date the given number of days from now
+func (f *fakeWhoisAPI) expireIn(days int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expiry = time.Now().AddDate(0, 0, days)
+}
+
+// expireDomainIn sets the expiry date reported for one domain
+func (f *fakeWhoisAPI) expireDomainIn(domain string, days int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expiries[domain] = time.Now().AddDate(0, 0, days)
+}
+
+func (f *fakeWhoisAPI) setRegistrar(registrar string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.registrar = registrar
+}
+
+func (f *fakeWhoisAPI) setFailing(failing bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing = failing
+}
+
+func (f *fakeWhoisAPI) setNameServers(servers ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.servers = servers
+}
+
+// setDelay makes each query take the given time
+func (f *fakeWhoisAPI) setDelay(delay time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delay = delay
+}
+
+// peakConcurrency returns the highest number of queries served at the same time
+func (f *fakeWhoisAPI) peakConcurrency() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.maxInFlight
+}
+
+// queryCount returns how many queries the API has answered
+func (f *fakeWhoisAPI) queryCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.queries
+}
+
+// newTestMonitor wires a monitor to a fake WHOIS API and a fake notification channel.
+// It needs a database from setupTestDB.
+func newTestMonitor(t *testing.T, cfg config.MonitorConfig) (*MonitorService, *fakeWhoisAPI, *fakeNotifier) {
+	t.Helper()
+	api := newFakeWhoisAPI(t)
+	whois := NewWhoisService(&config.WhoisConfig{APIURL: api.URL, CacheTTL: "0"})
+	notify := NewNotifyService(&config.NotificationsConfig{})
+	channel := &fakeNotifier{channel: "webhook"}
+	notify.notifiers = []Notifier{channel}
+
+	if cfg.AlertDays == nil {
+		cfg.AlertDays = []int{30, 7, 1}
+	}
+	monitor := NewMonitorService(whois, notify, &cfg)
+	t.Cleanup(func() { monitor.Shutdown(context.Background()) })
+	return monitor, api, channel
+}
+
+// createTestDomain stores an active domain with notifications enabled
+func createTestDomain(t *testing.T, name string) *models.Domain {
+	t.Helper()
+	domain := &models.Domain{Name: name, IsActive: true, NotifyEnabled: true}
+	if err := database.DB.Create(domain).Error; err != nil {
+		t.Fatalf("create domain: %v", err)
+	}
+	return domain
+}
+
+// sentKind returns the delivered alerts of one kind
+func (f *fakeNotifier) sentKind(kind string) []*Alert {
+	var alerts []*Alert
+	for _, alert := range f.sent() {
+		if alert.Kind == kind {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// capturedRequest is a request received by a captureServer
+type capturedRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// captureServer answers every request with a fixed response and records the last request
+type captureServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	request capturedRequest
+	count   int
+}
+
+func newCaptureServer(t *testing.T, status int, body string) *captureServer {
+	t.Helper()
+	s := &captureServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.request = capturedRequest{Method: r.Method, Path: r.URL.EscapedPath(), Query: r.URL.Query(), Header: r.Header.Clone(), Body: data}
+		s.count++
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// last returns the last request received
+func (s *captureServer) last() capturedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.request
+}
+
+// requests returns the number of requests received
+func (s *captureServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// parseForm decodes a URL-encoded request body
+func parseForm(t *testing.T, body []byte) url.Values {
+	t.Helper()
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatalf("parse form %q: %v", body, err)
+	}
+	return form
+}
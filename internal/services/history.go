@@ -0,0 +1,80 @@
+package services
+
+import (
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/metrics"
+	"domain-monitor/internal/models"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// recordHistory appends the outcome of a check to the domain's check history and metrics
+func (s *MonitorService) recordHistory(domain *models.Domain, checkErr error) {
+	entry := models.DomainCheckHistory{
+		DomainID:      domain.ID,
+		CheckedAt:     time.Now(),
+		DaysRemaining: domain.DaysRemaining,
+		Status:        domain.Status,
+		ExpiryDate:    domain.ExpiryDate,
+		Success:       checkErr == nil,
+	}
+	if checkErr != nil {
+		entry.Error = checkErr.Error()
+		metrics.DomainChecks.Inc("error")
+	} else {
+		metrics.DomainChecks.Inc("success")
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&entry).Error
+	}); err != nil {
+		slog.Error("Failed to record check history", "domain", domain.Name, "error", err)
+	}
+}
+
+// PruneHistory deletes check history older than monitor.history_retention_days
+func (s *MonitorService) PruneHistory() error {
+	cutoff := time.Now().AddDate(0, 0, -s.historyRetentionDays)
+
+	var deleted int64
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		result := db.Where("checked_at < ?", cutoff).Delete(&models.DomainCheckHistory{})
+		deleted = result.RowsAffected
+		return result.Error
+	}); err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		slog.Info("Pruned old check history", "count", deleted, "retention_days", s.historyRetentionDays)
+	}
+	return nil
+}
+
+// PruneNotifications deletes notification history older than monitor.notification_retention_days
+func (s *MonitorService) PruneNotifications() error {
+	cutoff := time.Now().AddDate(0, 0, -s.notificationRetention)
+
+	deleted, err := DeleteNotificationsBefore(cutoff)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		slog.Info("Pruned old notifications", "count", deleted, "retention_days", s.notificationRetention)
+	}
+	return nil
+}
+
+// DeleteNotificationsBefore deletes notifications sent before cutoff and returns how many were deleted
+func DeleteNotificationsBefore(cutoff time.Time) (int64, error) {
+	var deleted int64
+	err := database.WithRetry(func(db *gorm.DB) error {
+		result := db.Where("sent_at < ?", cutoff).Delete(&models.Notification{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCheckDomainRecordsHistory(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(100)
+	domain := createTestDomain(t, "example.com")
+
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	api.setFailing(true)
+	if err := monitor.CheckDomain(domain); err == nil {
+		t.Fatal("CheckDomain succeeded against a failing API")
+	}
+
+	var history []models.DomainCheckHistory
+	database.DB.Where("domain_id = ?", domain.ID).Order("id").Find(&history)
+	if len(history) != 2 {
+		t.Fatalf("%d history rows, want 2", len(history))
+	}
+	if !history[0].Success || history[0].DaysRemaining != 100 || history[0].ExpiryDate == nil {
+		t.Errorf("first entry = %+v, want a successful check with 100 days", history[0])
+	}
+	if history[1].Success || history[1].Error == "" {
+		t.Errorf("second entry = %+v, want the failure", history[1])
+	}
+}
+
+func TestPruneHistory(t *testing.T) {
+	setupTestDB(t)
+	monitor := NewMonitorService(nil, nil, &config.MonitorConfig{HistoryRetentionDays: 30})
+
+	now := time.Now()
+	for _, age := range []int{1, 29, 31, 400} {
+		entry := models.DomainCheckHistory{DomainID: 1, CheckedAt: now.AddDate(0, 0, -age), Success: true}
+		if err := database.DB.Create(&entry).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := monitor.PruneHistory(); err != nil {
+		t.Fatalf("PruneHistory: %v", err)
+	}
+	var remaining int64
+	database.DB.Model(&models.DomainCheckHistory{}).Count(&remaining)
+	if remaining != 2 {
+		t.Errorf("%d entries left, want the 2 within the retention", remaining)
+	}
+}
+
+func TestPruneNotifications(t *testing.T) {
+	setupTestDB(t)
+	monitor := NewMonitorService(nil, nil, &config.MonitorConfig{NotificationRetentionDays: 30})
+
+	now := time.Now()
+	for _, age := range []int{0, 29, 31, 400} {
+		n := models.Notification{DomainID: 1, Type: "email", Status: "success", SentAt: now.AddDate(0, 0, -age)}
+		if err := database.DB.Create(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := monitor.PruneNotifications(); err != nil {
+		t.Fatalf("PruneNotifications: %v", err)
+	}
+	var remaining []models.Notification
+	database.DB.Find(&remaining)
+	if len(remaining) != 2 {
+		t.Fatalf("%d notifications left, want the 2 within the retention", len(remaining))
+	}
+	for _, n := range remaining {
+		if n.SentAt.Before(now.AddDate(0, 0, -30)) {
+			t.Errorf("notification sent %v kept beyond the retention", n.SentAt)
+		}
+	}
+}
+
+func TestDeleteNotificationsBefore(t *testing.T) {
+	setupTestDB(t)
+	cutoff := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for _, sentAt := range []time.Time{cutoff.Add(-time.Second), cutoff, cutoff.Add(time.Hour)} {
+		database.DB.Create(&models.Notification{DomainID: 1, SentAt: sentAt})
+	}
+
+	deleted, err := DeleteNotificationsBefore(cutoff)
+	if err != nil {
+		t.Fatalf("DeleteNotificationsBefore: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted %d, want 1", deleted)
+	}
+}
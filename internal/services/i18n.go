@@ -0,0 +1,224 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Notification languages
+const (
+	LanguageChinese = "zh-CN"
+	LanguageEnglish = "en"
+)
+
+// DefaultLanguage is used when notifications.language is empty
+const DefaultLanguage = LanguageChinese
+
+// Messages is the text of notifications in one language. Fields ending in a format verb
+// are fmt formats; their arguments are listed in the comments.
+type Messages struct {
+	// Severity labels
+	SeverityCritical string
+	SeverityWarning  string
+	SeverityInfo     string
+
+	// Field labels of the notification bodies
+	Colon         string // Separator between a label and its value
+	Status        string
+	Domain        string
+	DaysRemaining string
+	ExpiryDate    string
+	Registrar     string
+	DomainStatus  string
+	LastChecked   string
+	Days          string // days
+
+	// Expiry reminders
+	ExpiryTitle   string
+	ExpirySubject string // domain, days
+	ExpiryClosing string
+
+	// Digests
+	DigestTitle string // count
+	DigestLine  string // emoji, domain, days, expiry date
+
+	// Domain events
+	ChangeTitle       string
+	ChangeMessage     string // changes
+	ChangeRegistrar   string // old, new
+	ChangeNameServers string // old, new
+	DNSChangeTitle    string
+	DNSChangeMessage  string // changes
+	NoRecords         string
+	ParkingTitle      string
+	ParkingMessage    string // name servers
+	DisabledTitle     string
+	DisabledMessage   string // failures, error
+	AnomalyTitle      string
+	AnomalyMessage    string // previous check, previous days, previous expiry, days, expiry, expected days
+	UptimeDownTitle   string
+	UptimeDownMessage string // url, error
+	UptimeUpTitle     string
+	UptimeUpMessage   string // url, status code, latency in ms
+
+	// System alerts
+	DatabaseErrorTitle     string
+	DatabaseErrorMessage   string // error
+	CheckFailedTitle       string
+	CheckFailedMessage     string // failed, total, domains
+	CheckFailedMore        string // failed
+	SchedulerPanicTitle    string
+	SchedulerPanicMessage  string // panic
+	CertCheckFailedTitle   string
+	CertCheckFailedMessage string // error
+}
+
+// catalog holds the messages of each supported language
+var catalog = map[string]*Messages{
+	LanguageChinese: {
+		SeverityCritical: "紧急",
+		SeverityWarning:  "警告",
+		SeverityInfo:     "正常",
+
+		Colon:         "：",
+		Status:        "状态",
+		Domain:        "域名",
+		DaysRemaining: "剩余天数",
+		ExpiryDate:    "到期日期",
+		Registrar:     "注册商",
+		DomainStatus:  "域名状态",
+		LastChecked:   "最后检查",
+		Days:          "%d 天",
+
+		ExpiryTitle:   "域名到期提醒",
+		ExpirySubject: "域名到期提醒：%s 还有 %d 天到期",
+		ExpiryClosing: "请及时续费以避免域名过期！",
+
+		DigestTitle: "域名到期汇总（%d 个域名）",
+		DigestLine:  "%s %s  剩余 %d 天（%s 到期）",
+
+		ChangeTitle:       "域名注册信息变更",
+		ChangeMessage:     "检测到域名注册信息变化：\n%s\n如非本人操作，域名可能已被转移或劫持，请尽快确认。",
+		ChangeRegistrar:   "注册商：%s → %s",
+		ChangeNameServers: "DNS 服务器：%s → %s",
+		DNSChangeTitle:    "域名解析记录变更",
+		DNSChangeMessage:  "检测到域名解析记录变化：\n%s\n如非本人操作，DNS 可能已被劫持或误修改，请尽快确认。",
+		NoRecords:         "（无）",
+		ParkingTitle:      "域名疑似被停放",
+		ParkingMessage:    "域名的 DNS 服务器指向停放/出售服务商：%s。域名可能已过期、被转移或被误修改，请尽快确认。",
+		DisabledTitle:     "域名监控已自动停用",
+		DisabledMessage:   "域名连续 %d 次检查失败，已自动停止监控。最近一次错误：%v。域名可能已在注册商处删除，或WHOIS接口不支持该后缀；确认后可在域名设置中重新启用监控。",
+		AnomalyTitle:      "域名剩余天数异常减少",
+		AnomalyMessage:    "上次检查（%s）剩余 %d 天，到期日 %s；本次检查剩余 %d 天，到期日 %s，按时间推算应约为 %d 天。请确认 WHOIS 数据是否异常或域名到期时间是否被缩短。",
+		UptimeDownTitle:   "网站无法访问",
+		UptimeDownMessage: "%s 无法访问：%s",
+		UptimeUpTitle:     "网站恢复访问",
+		UptimeUpMessage:   "%s 已恢复访问，状态码 %d，响应时间 %d ms。",
+
+		DatabaseErrorTitle:     "数据库错误",
+		DatabaseErrorMessage:   "定时检查无法读取域名列表：%v",
+		CheckFailedTitle:       "域名检查失败",
+		CheckFailedMessage:     "%d/%d 个域名检查失败：\n%s",
+		CheckFailedMore:        "\n……等共 %d 个",
+		SchedulerPanicTitle:    "定时任务异常",
+		SchedulerPanicMessage:  "定时检查任务异常中止：%v",
+		CertCheckFailedTitle:   "证书检查失败",
+		CertCheckFailedMessage: "定时证书检查失败：%v",
+	},
+	LanguageEnglish: {
+		SeverityCritical: "Urgent",
+		SeverityWarning:  "Warning",
+		SeverityInfo:     "Normal",
+
+		Colon:         ": ",
+		Status:        "Status",
+		Domain:        "Domain",
+		DaysRemaining: "Days remaining",
+		ExpiryDate:    "Expiry date",
+		Registrar:     "Registrar",
+		DomainStatus:  "Domain status",
+		LastChecked:   "Last checked",
+		Days:          "%d days",
+
+		ExpiryTitle:   "Domain Expiry Reminder",
+		ExpirySubject: "Domain expiry reminder: %s expires in %d days",
+		ExpiryClosing: "Please renew the domain before it expires!",
+
+		DigestTitle: "Domain expiry digest (%d domains)",
+		DigestLine:  "%s %s  %d days remaining (expires %s)",
+
+		ChangeTitle:       "Domain registration changed",
+		ChangeMessage:     "The registration of the domain changed:\n%s\nIf you didn't make this change, the domain may have been transferred or hijacked. Please check it as soon as possible.",
+		ChangeRegistrar:   "Registrar: %s → %s",
+		ChangeNameServers: "Name servers: %s → %s",
+		DNSChangeTitle:    "DNS records changed",
+		DNSChangeMessage:  "The DNS records of the domain changed:\n%s\nIf you didn't make this change, the DNS may have been hijacked or modified by mistake. Please check it as soon as possible.",
+		NoRecords:         "(none)",
+		ParkingTitle:      "Domain appears to be parked",
+		ParkingMessage:    "The name servers of the domain point to a parking/for-sale provider: %s. The domain may have expired, been transferred or been modified by mistake. Please check it as soon as possible.",
+		DisabledTitle:     "Domain monitoring disabled",
+		DisabledMessage:   "%d consecutive checks of the domain failed, so monitoring was stopped. Last error: %v. The domain may have been deleted at the registrar, or the WHOIS service doesn't support its TLD; re-enable monitoring in the domain settings once resolved.",
+		AnomalyTitle:      "Days remaining dropped unexpectedly",
+		AnomalyMessage:    "The previous check (%s) found %d days remaining, expiring %s; this check found %d days remaining, expiring %s, where about %d days were expected. Please check whether the WHOIS data is wrong or the expiry date was moved forward.",
+		UptimeDownTitle:   "Site is down",
+		UptimeDownMessage: "%s is unreachable: %s",
+		UptimeUpTitle:     "Site is back up",
+		UptimeUpMessage:   "%s is reachable again with status code %d in %d ms.",
+
+		DatabaseErrorTitle:     "Database error",
+		DatabaseErrorMessage:   "The scheduled check couldn't read the domain list: %v",
+		CheckFailedTitle:       "Domain checks failed",
+		CheckFailedMessage:     "%d of %d domain checks failed:\n%s",
+		CheckFailedMore:        "\n... %d in total",
+		SchedulerPanicTitle:    "Scheduled task crashed",
+		SchedulerPanicMessage:  "The scheduled check aborted: %v",
+		CertCheckFailedTitle:   "Certificate check failed",
+		CertCheckFailedMessage: "The scheduled certificate check failed: %v",
+	},
+}
+
+// NormalizeLanguage maps a language tag to a supported language: "zh", "zh_CN" and "zh-Hans"
+// become zh-CN, "en-US" and "en_GB" become en. Empty selects DefaultLanguage.
+func NormalizeLanguage(language string) (string, error) {
+	if language == "" {
+		return DefaultLanguage, nil
+	}
+	base, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(language), "_", "-"), "-")
+	switch base {
+	case "zh":
+		return LanguageChinese, nil
+	case "en":
+		return LanguageEnglish, nil
+	}
+	return "", fmt.Errorf("unsupported notification language %q (zh-CN or en)", language)
+}
+
+// MessagesFor returns the messages of a language, falling back to DefaultLanguage
+func MessagesFor(language string) *Messages {
+	if language, err := NormalizeLanguage(language); err == nil {
+		return catalog[language]
+	}
+	return catalog[DefaultLanguage]
+}
+
+// SeverityLabel returns the display label of a severity
+func (m *Messages) SeverityLabel(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return m.SeverityCritical
+	case SeverityWarning:
+		return m.SeverityWarning
+	default:
+		return m.SeverityInfo
+	}
+}
+
+// Field formats a "label: value" line with the language's separator
+func (m *Messages) Field(label, value string) string {
+	return label + m.Colon + value
+}
+
+// DaysText formats a number of days
+func (m *Messages) DaysText(days int) string {
+	return fmt.Sprintf(m.Days, days)
+}
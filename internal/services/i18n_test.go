@@ -0,0 +1,98 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmailLanguage(t *testing.T) {
+	tests := []struct {
+		language string
+		want     []string
+		unwanted []string
+	}{
+		{
+			language: "zh-CN",
+			want: []string{
+				"Subject: 域名到期提醒：example.com 还有 7 天到期",
+				"状态：🔴 紧急",
+				"域名：example.com",
+				"剩余天数：7 天",
+			},
+			unwanted: []string{"Days remaining", "Urgent"},
+		},
+		{
+			language: "en",
+			want: []string{
+				"Subject: Domain expiry reminder: example.com expires in 7 days",
+				"Status: 🔴 Urgent",
+				"Domain: example.com",
+				"Days remaining: 7 days",
+			},
+			unwanted: []string{"剩余天数", "紧急"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			setupTestDB(t)
+			stub := newSMTPStub(t)
+			smtpRootCAs = stub.roots
+			t.Cleanup(func() { smtpRootCAs = nil })
+
+			email := stub.config()
+			email.Enabled = true
+			notify := NewNotifyService(&config.NotificationsConfig{Email: *email, Language: tt.language})
+
+			expiry := time.Now().AddDate(0, 0, 7)
+			alert := &Alert{
+				Domain:        &models.Domain{Name: "example.com", ExpiryDate: &expiry},
+				DaysRemaining: 7,
+				Severity:      SeverityCritical,
+			}
+			if err := notify.SendAlert(alert); err != nil {
+				t.Fatalf("SendAlert: %v", err)
+			}
+
+			messages := stub.received()
+			if len(messages) != 1 {
+				t.Fatalf("stub received %d messages, want 1", len(messages))
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(messages[0], want) {
+					t.Errorf("email missing %q:\n%s", want, messages[0])
+				}
+			}
+			for _, unwanted := range tt.unwanted {
+				if strings.Contains(messages[0], unwanted) {
+					t.Errorf("email contains %q of another language", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":        DefaultLanguage,
+		"zh":      LanguageChinese,
+		"zh_CN":   LanguageChinese,
+		"zh-Hans": LanguageChinese,
+		"en":      LanguageEnglish,
+		"en-US":   LanguageEnglish,
+		"EN_gb":   LanguageEnglish,
+	}
+	for input, want := range tests {
+		if got, err := NormalizeLanguage(input); err != nil || got != want {
+			t.Errorf("NormalizeLanguage(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := NormalizeLanguage("fr"); err == nil {
+		t.Error("NormalizeLanguage(fr) succeeded")
+	}
+	if MessagesFor("fr") != MessagesFor(DefaultLanguage) {
+		t.Error("unsupported language doesn't fall back to the default")
+	}
+}
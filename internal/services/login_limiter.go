@@ -0,0 +1,145 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoginMaxAttempts = 5
+	defaultLoginWindow      = 15 * time.Minute
+	defaultLoginLockout     = 15 * time.Minute
+
+	// maxLoginEntries triggers a sweep of stale entries, bounding memory under attack
+	maxLoginEntries = 10000
+)
+
+// LoginLimiter counts failed logins per username and per client IP in a sliding window
+// and locks the key out once the limit is reached. State is kept in memory.
+type LoginLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int // 0 = disabled
+	window      time.Duration
+	lockout     time.Duration
+	entries     map[string]*loginEntry
+}
+
+// loginEntry tracks the failed logins of one username or IP
+type loginEntry struct {
+	failures    []time.Time // Failed attempts within the window
+	lockedUntil time.Time
+}
+
+// NewLoginLimiter creates a login limiter from the security configuration
+func NewLoginLimiter(cfg *config.SecurityConfig) *LoginLimiter {
+	maxAttempts := cfg.LoginMaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultLoginMaxAttempts
+	}
+	if maxAttempts < 0 {
+		maxAttempts = 0
+	}
+
+	window, err := time.ParseDuration(cfg.LoginWindow)
+	if err != nil || window <= 0 {
+		window = defaultLoginWindow
+	}
+	lockout, err := time.ParseDuration(cfg.LoginLockout)
+	if err != nil || lockout <= 0 {
+		lockout = defaultLoginLockout
+	}
+
+	return &LoginLimiter{
+		maxAttempts: maxAttempts,
+		window:      window,
+		lockout:     lockout,
+		entries:     make(map[string]*loginEntry),
+	}
+}
+
+// Allow reports whether a login for the username from the IP may be attempted at now,
+// and otherwise how long until the lockout ends
+func (l *LoginLimiter) Allow(username, ip string, now time.Time) (time.Duration, bool) {
+	if l.maxAttempts == 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range loginKeys(username, ip) {
+		if entry := l.entries[key]; entry != nil && now.Before(entry.lockedUntil) {
+			wait = max(wait, entry.lockedUntil.Sub(now))
+		}
+	}
+	return wait, wait == 0
+}
+
+// Fail records a failed login and locks the username or IP once it reaches the limit
+func (l *LoginLimiter) Fail(username, ip string, now time.Time) {
+	if l.maxAttempts == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.entries) >= maxLoginEntries {
+		l.prune(now)
+	}
+
+	for _, key := range loginKeys(username, ip) {
+		entry := l.entries[key]
+		if entry == nil {
+			entry = &loginEntry{}
+			l.entries[key] = entry
+		}
+
+		// Drop failures that left the window
+		cutoff := now.Add(-l.window)
+		recent := entry.failures[:0]
+		for _, t := range entry.failures {
+			if t.After(cutoff) {
+				recent = append(recent, t)
+			}
+		}
+		entry.failures = append(recent, now)
+
+		if len(entry.failures) >= l.maxAttempts {
+			entry.lockedUntil = now.Add(l.lockout)
+			entry.failures = nil
+		}
+	}
+}
+
+// Succeed clears the failed logins of the username. The IP keeps its count, so that
+// logging into one's own account doesn't reset guessing at others from the same IP.
+func (l *LoginLimiter) Succeed(username string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, userKey(username))
+}
+
+// prune removes entries that are neither locked nor have failures in the window
+func (l *LoginLimiter) prune(now time.Time) {
+	cutoff := now.Add(-l.window)
+	for key, entry := range l.entries {
+		last := len(entry.failures) - 1
+		if now.After(entry.lockedUntil) && (last < 0 || !entry.failures[last].After(cutoff)) {
+			delete(l.entries, key)
+		}
+	}
+}
+
+// loginKeys returns the limiter keys of a login attempt
+func loginKeys(username, ip string) []string {
+	return []string{userKey(username), "ip:" + ip}
+}
+
+// userKey returns the limiter key of a username
+func userKey(username string) string {
+	return "user:" + username
+}
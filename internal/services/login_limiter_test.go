@@ -0,0 +1,82 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"testing"
+	"time"
+)
+
+func newTestLimiter() *LoginLimiter {
+	return NewLoginLimiter(&config.SecurityConfig{LoginMaxAttempts: 3, LoginWindow: "10m", LoginLockout: "5m"})
+}
+
+func TestLoginLimiterLocksOut(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Allow("alice", "10.0.0.1", now); !ok {
+			t.Fatalf("attempt %d refused before the limit", i+1)
+		}
+		l.Fail("alice", "10.0.0.1", now)
+	}
+
+	wait, ok := l.Allow("alice", "10.0.0.1", now.Add(time.Minute))
+	if ok || wait != 4*time.Minute {
+		t.Fatalf("Allow() = %v, %v; want locked out for 4m", wait, ok)
+	}
+	// The username is locked from any IP, and the IP for any username
+	if _, ok := l.Allow("alice", "10.0.0.2", now); ok {
+		t.Error("locked username allowed from another IP")
+	}
+	if _, ok := l.Allow("bob", "10.0.0.1", now); ok {
+		t.Error("locked IP allowed for another username")
+	}
+
+	if _, ok := l.Allow("alice", "10.0.0.1", now.Add(5*time.Minute)); !ok {
+		t.Error("still locked out after the lockout ended")
+	}
+}
+
+func TestLoginLimiterWindow(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Now()
+
+	l.Fail("alice", "10.0.0.1", now)
+	l.Fail("alice", "10.0.0.1", now.Add(time.Minute))
+	// The first failure has left the window
+	l.Fail("alice", "10.0.0.1", now.Add(11*time.Minute))
+
+	if _, ok := l.Allow("alice", "10.0.0.1", now.Add(11*time.Minute)); !ok {
+		t.Error("failures outside the window counted towards the lockout")
+	}
+}
+
+func TestLoginLimiterSucceedResetsUserOnly(t *testing.T) {
+	l := newTestLimiter()
+	now := time.Now()
+
+	// Two failures guessing bob's password, then a login to the attacker's own account
+	l.Fail("bob", "10.0.0.1", now)
+	l.Fail("bob", "10.0.0.1", now)
+	l.Fail("mallory", "10.0.0.1", now)
+	l.Succeed("mallory")
+
+	if _, ok := l.Allow("mallory", "10.0.0.2", now); !ok {
+		t.Error("username still counted after a successful login")
+	}
+	if _, ok := l.Allow("bob", "10.0.0.1", now); ok {
+		t.Error("a successful login reset the failures of the IP")
+	}
+}
+
+func TestLoginLimiterDisabled(t *testing.T) {
+	l := NewLoginLimiter(&config.SecurityConfig{LoginMaxAttempts: -1})
+	now := time.Now()
+	for i := 0; i < 10; i++ {
+		l.Fail("alice", "10.0.0.1", now)
+	}
+	if _, ok := l.Allow("alice", "10.0.0.1", now); !ok {
+		t.Error("disabled limiter refused a login")
+	}
+}
@@ -1,101 +1,865 @@
 package services
 
 import (
+	"context"
+	"domain-monitor/internal/config"
 	"domain-monitor/internal/database"
 	"domain-monitor/internal/models"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
+)
+
+// Alert modes
+const (
+	AlertModeThreshold  = "threshold"  // Alert when days remaining crosses an alert day
+	AlertModeTransition = "transition" // Alert when the urgency bucket gets worse
+)
+
+// Urgency buckets used by the transition alert mode, from least to most urgent
+const (
+	BucketNormal   = "normal"
+	BucketWarning  = "warning"
+	BucketCritical = "critical"
+	BucketExpired  = "expired"
 )
 
+// bucketRank orders the urgency buckets
+var bucketRank = map[string]int{
+	BucketNormal:   0,
+	BucketWarning:  1,
+	BucketCritical: 2,
+	BucketExpired:  3,
+}
+
+// ErrShuttingDown is returned when a check is started after Shutdown
+var ErrShuttingDown = errors.New("the monitor is shutting down")
+
+// errNoExpiryDate is the check error of WHOIS answers without a parseable expiry date
+var errNoExpiryDate = errors.New("WHOIS response has no parseable expiry date")
+
 // MonitorService handles domain monitoring
 type MonitorService struct {
-	whoisService *WhoisService
-	notifyService *NotifyService
-	alertDays    []int
+	whoisService          *WhoisService
+	notifyService         *NotifyService
+	alertDays             []int
+	alertMode             string      // AlertModeThreshold or AlertModeTransition
+	anomalyTolerance      int         // Days of slack before a drop in days remaining is reported
+	failureThreshold      int         // Consecutive failed checks before the domain error is set
+	autoDisableThreshold  int         // Consecutive failed checks before monitoring is turned off (0 = never)
+	concurrency           int         // Domains checked in parallel
+	historyRetentionDays  int         // Days of check history kept
+	notificationRetention int         // Days of notification history kept
+	parkingPatterns       []string    // Name server patterns of parking providers
+	digestMode            bool        // Send one digest per scheduled run instead of per-domain alerts
+	dnsService            *DNSService // Optional, nil when DNS monitoring is disabled
+
+	digestMu sync.Mutex
+	digest   *[]DomainAlert // Expiry alerts collected during a scheduled run (nil = send immediately)
+
+	refresh refreshJobs // Manual refresh-all jobs
+
+	lastSweep atomic.Pointer[time.Time] // End of the last scheduled or overdue check run
+
+	ctx      context.Context // Root context of the checks, cancelled when Shutdown times out
+	cancel   context.CancelFunc
+	workMu   sync.Mutex
+	work     sync.WaitGroup // Checks in progress
+	stopping bool           // Shutdown has started, no new checks are accepted
 }
 
 // NewMonitorService creates a new monitoring service
-func NewMonitorService(whoisService *WhoisService, notifyService *NotifyService, alertDays []int) *MonitorService {
+func NewMonitorService(whoisService *WhoisService, notifyService *NotifyService, cfg *config.MonitorConfig) *MonitorService {
+	anomalyTolerance := cfg.AnomalyToleranceDays
+	if anomalyTolerance <= 0 {
+		anomalyTolerance = 1
+	}
+	failureThreshold := cfg.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = 3
+	}
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+	historyRetentionDays := cfg.HistoryRetentionDays
+	if historyRetentionDays <= 0 {
+		historyRetentionDays = 365
+	}
+	notificationRetention := cfg.NotificationRetentionDays
+	if notificationRetention <= 0 {
+		notificationRetention = 365
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
 	return &MonitorService{
-		whoisService:  whoisService,
-		notifyService: notifyService,
-		alertDays:     alertDays,
+		ctx:                   ctx,
+		cancel:                cancel,
+		whoisService:          whoisService,
+		notifyService:         notifyService,
+		alertDays:             cfg.AlertDays,
+		alertMode:             cfg.AlertMode,
+		anomalyTolerance:      anomalyTolerance,
+		failureThreshold:      failureThreshold,
+		autoDisableThreshold:  cfg.AutoDisableThreshold,
+		concurrency:           concurrency,
+		historyRetentionDays:  historyRetentionDays,
+		notificationRetention: notificationRetention,
+		parkingPatterns:       cfg.ParkingNameservers,
+		digestMode:            cfg.DigestMode,
+	}
+}
+
+// SetDNSService enables DNS record monitoring during domain checks
+func (s *MonitorService) SetDNSService(dnsService *DNSService) {
+	s.dnsService = dnsService
+}
+
+// Context returns the root context of the checks, which is cancelled when Shutdown times out
+func (s *MonitorService) Context() context.Context {
+	return s.ctx
+}
+
+// startWork registers a check that Shutdown waits for, or returns false once shutdown has started
+func (s *MonitorService) startWork() bool {
+	s.workMu.Lock()
+	defer s.workMu.Unlock()
+
+	if s.stopping {
+		return false
+	}
+	s.work.Add(1)
+	return true
+}
+
+// Shutdown stops accepting checks and waits for the running ones to finish. When ctx is
+// done first, the outstanding checks are cancelled and ctx's error is returned.
+func (s *MonitorService) Shutdown(ctx context.Context) error {
+	s.workMu.Lock()
+	s.stopping = true
+	s.workMu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.work.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		s.cancel()
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
 	}
 }
 
 // CheckAllDomains checks all active domains
 func (s *MonitorService) CheckAllDomains() error {
-	db := database.GetDB()
+	return s.CheckAllDomainsContext(s.ctx)
+}
+
+// CheckAllDomainsContext checks all active domains; cancelling the context aborts
+// the outstanding checks
+func (s *MonitorService) CheckAllDomainsContext(ctx context.Context) error {
+	if !s.startWork() {
+		return ErrShuttingDown
+	}
+	defer s.work.Done()
+
+	var domains []models.Domain
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Where("is_active = ?", true).Find(&domains).Error
+	}); err != nil {
+		m := s.Messages()
+		s.ReportSystemAlert(m.DatabaseErrorTitle, fmt.Sprintf(m.DatabaseErrorMessage, err))
+		return fmt.Errorf("failed to fetch domains: %w", err)
+	}
+
+	domains = s.skipPaused(domains)
+	domains = s.skipNotDue(domains)
+
+	slog.Info("Checking domains", "count", len(domains), "concurrency", s.concurrency)
+
+	s.beginDigest()
+	s.checkDomains(ctx, domains, false, nil)
+	s.flushDigest()
+	s.markSweep()
+
+	if err := s.PruneHistory(); err != nil {
+		slog.Error("Failed to prune check history", "error", err)
+	}
+	if err := s.PruneNotifications(); err != nil {
+		slog.Error("Failed to prune notification history", "error", err)
+	}
+
+	return nil
+}
+
+// CheckOverdueDomains checks active domains that missed their scheduled check,
+// i.e. whose next run after LastChecked is already in the past
+func (s *MonitorService) CheckOverdueDomains(checkInterval string) error {
+	schedule, err := cron.ParseStandard(checkInterval)
+	if err != nil {
+		return fmt.Errorf("invalid check interval: %w", err)
+	}
+
+	if !s.startWork() {
+		return ErrShuttingDown
+	}
+	defer s.work.Done()
 
 	var domains []models.Domain
-	if err := db.Where("is_active = ?", true).Find(&domains).Error; err != nil {
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Where("is_active = ?", true).Find(&domains).Error
+	}); err != nil {
 		return fmt.Errorf("failed to fetch domains: %w", err)
 	}
 
-	log.Printf("Checking %d domains...", len(domains))
+	domains = s.skipPaused(domains)
 
+	now := time.Now()
+	overdue := make([]models.Domain, 0)
 	for _, domain := range domains {
-		if err := s.CheckDomain(&domain); err != nil {
-			log.Printf("Error checking domain %s: %v", domain.Name, err)
+		if !s.checkDue(&domain, now) {
 			continue
 		}
+		if domain.LastChecked == nil || schedule.Next(*domain.LastChecked).Before(now) {
+			overdue = append(overdue, domain)
+		}
 	}
 
+	slog.Info("Checking overdue domains", "count", len(overdue), "active", len(domains))
+
+	s.beginDigest()
+	s.checkDomains(s.ctx, overdue, false, nil)
+	s.flushDigest()
+	s.markSweep()
+
 	return nil
 }
 
-// CheckDomain checks a single domain and updates its information
+// skipPaused drops paused domains from a scheduled check and resumes those whose pause has ended
+func (s *MonitorService) skipPaused(domains []models.Domain) []models.Domain {
+	now := time.Now()
+	unpaused := make([]models.Domain, 0, len(domains))
+	for _, domain := range domains {
+		if domain.IsPaused(now) {
+			slog.Info("Skipping paused domain", "domain", domain.Name, "paused_until", domain.PausedUntil, "reason", domain.PauseReason)
+			continue
+		}
+		if domain.PausedUntil != nil {
+			slog.Info("Resuming paused domain", "domain", domain.Name, "paused_until", domain.PausedUntil)
+			if err := s.ResumeDomain(&domain); err != nil {
+				slog.Error("Failed to resume domain", "domain", domain.Name, "error", err)
+			}
+		}
+		unpaused = append(unpaused, domain)
+	}
+	return unpaused
+}
+
+// skipNotDue drops domains whose check frequency says they were checked recently enough
+func (s *MonitorService) skipNotDue(domains []models.Domain) []models.Domain {
+	now := time.Now()
+	due := make([]models.Domain, 0, len(domains))
+	for _, domain := range domains {
+		if s.checkDue(&domain, now) {
+			due = append(due, domain)
+		}
+	}
+	if skipped := len(domains) - len(due); skipped > 0 {
+		slog.Info("Skipping domains not due for a check (check_frequency_days)", "count", skipped)
+	}
+	return due
+}
+
+// checkDue reports whether a scheduled run should check the domain. The alert policy
+// is only loaded for domains with a check frequency.
+func (s *MonitorService) checkDue(domain *models.Domain, now time.Time) bool {
+	if domain.CheckFrequencyDays <= 0 {
+		return true
+	}
+	return domain.CheckDue(now, s.alertDaysFor(domain))
+}
+
+// alertDaysFor returns the alert days of the domain's policy, or the configured ones
+func (s *MonitorService) alertDaysFor(domain *models.Domain) []int {
+	if policy := LoadAlertPolicy(domain); policy != nil {
+		return policy.Days()
+	}
+	return s.alertDays
+}
+
+// PauseDomain pauses scheduled checks of a domain until the given time; the zero time clears the pause
+func (s *MonitorService) PauseDomain(domain *models.Domain, until time.Time, reason string) error {
+	pausedUntil := models.NullTime(until.UTC())
+	domain.PausedUntil = pausedUntil
+	domain.PauseReason = reason
+	return database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(domain).Updates(map[string]interface{}{
+			"paused_until": pausedUntil,
+			"pause_reason": reason,
+		}).Error
+	})
+}
+
+// ResumeDomain clears the pause of a domain
+func (s *MonitorService) ResumeDomain(domain *models.Domain) error {
+	return s.PauseDomain(domain, time.Time{}, "")
+}
+
+// maxReportedFailures limits the domains listed in a check failure alert
+const maxReportedFailures = 10
+
+// markSweep records the end of a check run
+func (s *MonitorService) markSweep() {
+	now := time.Now()
+	s.lastSweep.Store(&now)
+}
+
+// LastCheckTime returns when the last scheduled or overdue check run finished, nil if none has
+func (s *MonitorService) LastCheckTime() *time.Time {
+	return s.lastSweep.Load()
+}
+
+// CheckDomains checks the given domains with up to monitor.concurrency workers, logging
+// failures and reporting them as a system alert
+func (s *MonitorService) CheckDomains(domains []models.Domain) {
+	if !s.startWork() {
+		return
+	}
+	defer s.work.Done()
+
+	s.checkDomains(s.ctx, domains, false, nil)
+}
+
+// checkDomains checks the domains in parallel, calling done (if set) after each check,
+// and reports the failures in one system alert. Live checks bypass the WHOIS caches.
+// Once the context is cancelled the remaining domains are skipped and nothing is reported.
+func (s *MonitorService) checkDomains(ctx context.Context, domains []models.Domain, live bool, done func(err error)) {
+	var mu sync.Mutex
+	failures := make([]string, 0)
+
+	runPool(domains, s.concurrency, func(domain models.Domain) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := s.checkDomain(ctx, &domain, live)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Error checking domain", "domain", domain.Name, "error", err)
+			mu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", domain.Name, err))
+			mu.Unlock()
+		}
+		if done != nil {
+			done(err)
+		}
+	})
+
+	if ctx.Err() != nil {
+		slog.Warn("Domain check cancelled", "error", ctx.Err())
+		return
+	}
+	s.reportFailures(failures, len(domains))
+}
+
+// reportFailures sends one system alert listing the domains whose check failed
+func (s *MonitorService) reportFailures(failures []string, total int) {
+	if len(failures) == 0 {
+		return
+	}
+	m := s.Messages()
+	message := fmt.Sprintf(m.CheckFailedMessage, len(failures), total, strings.Join(failures[:min(len(failures), maxReportedFailures)], "\n"))
+	if len(failures) > maxReportedFailures {
+		message += fmt.Sprintf(m.CheckFailedMore, len(failures))
+	}
+	s.ReportSystemAlert(m.CheckFailedTitle, message)
+}
+
+// Messages returns the notification text in the configured language
+func (s *MonitorService) Messages() *Messages {
+	return s.notifyService.Messages()
+}
+
+// ReportSystemAlert sends a health alert about the monitoring system to the system channel
+func (s *MonitorService) ReportSystemAlert(title, message string) {
+	if s.notifyService == nil {
+		return
+	}
+	if err := s.notifyService.SendSystemAlert(title, message); err != nil {
+		slog.Error("Failed to send system alert", "title", title, "error", err)
+	}
+}
+
+// CheckDomain checks a single domain and updates its information, reusing cached WHOIS results
 func (s *MonitorService) CheckDomain(domain *models.Domain) error {
+	if !s.startWork() {
+		return ErrShuttingDown
+	}
+	defer s.work.Done()
+
+	return s.CheckDomainContext(s.ctx, domain)
+}
+
+// CheckDomainContext is CheckDomain with a context that cancels the WHOIS query
+func (s *MonitorService) CheckDomainContext(ctx context.Context, domain *models.Domain) error {
+	return s.checkDomain(ctx, domain, false)
+}
+
+// RefreshDomain checks a single domain with a live WHOIS query, bypassing the caches
+func (s *MonitorService) RefreshDomain(ctx context.Context, domain *models.Domain) error {
+	return s.checkDomain(ctx, domain, true)
+}
+
+// checkDomain checks a domain and updates its information
+func (s *MonitorService) checkDomain(ctx context.Context, domain *models.Domain, live bool) error {
 	// Query WHOIS information
-	info, err := s.whoisService.QueryDomain(domain.Name)
+	query := s.whoisService.QueryDomainContext
+	if live {
+		query = s.whoisService.QueryDomainLive
+	}
+	info, err := query(ctx, domain.Name)
+	if errors.Is(err, ErrDomainNotFound) {
+		return s.markAvailable(domain, err)
+	}
+	if err != nil && ctx.Err() != nil {
+		// A cancelled check says nothing about the domain, so it isn't counted as a failure
+		return fmt.Errorf("WHOIS query cancelled: %w", err)
+	}
 	if err != nil {
+		s.recordFailure(domain, err)
+		s.recordHistory(domain, err)
 		return fmt.Errorf("WHOIS query failed: %w", err)
 	}
 
+	// Keep the previous state for anomaly detection
+	previousDays := domain.DaysRemaining
+	previousExpiry := models.TimeValue(domain.ExpiryDate)
+	previousChecked := models.TimeValue(domain.LastChecked)
+	previousRegistrar := domain.Registrar
+	previousNameServers := domain.NameServerList()
+
 	// Update domain information
 	domain.Registrar = info.Registrar
-	domain.ExpiryDate = info.ExpiryDate
-	domain.CreatedDate = info.CreatedDate
-	domain.UpdatedDate = info.UpdatedDate
+	domain.ExpiryDate = models.NullTime(info.ExpiryDate)
+	domain.CreatedDate = models.NullTime(info.CreatedDate)
+	domain.ComputeAge(time.Now())
+	domain.UpdatedDate = models.NullTime(info.UpdatedDate)
 	domain.Status = info.Status
-	domain.LastChecked = time.Now()
+	domain.SetNameServers(info.NameServers)
+	domain.RawWhois = info.RawData
+	domain.LastChecked = models.NullTime(time.Now())
+	domain.LastError = ""
+	domain.ConsecutiveFailures = 0
+
+	// An answer without an expiry date usually means the registrar's format isn't parsed.
+	// Days remaining can't be computed, so the domain needs attention instead of an alert.
+	domain.CheckStatus = models.CheckStatusOK
+	domain.LastCheckError = ""
+	if info.ExpiryDate.IsZero() {
+		domain.CheckStatus = models.CheckStatusParseError
+		domain.LastCheckError = errNoExpiryDate.Error()
+		slog.WarnContext(ctx, "WHOIS response has no parseable expiry date", "domain", domain.Name)
+	}
+
+	// Detect parking name servers before saving so the state is persisted
+	parkedBy := s.matchParking(info.NameServers)
+	wasParked := domain.Parked
+	domain.Parked = len(parkedBy) > 0
 
 	// Calculate days remaining
 	if !info.ExpiryDate.IsZero() {
-		domain.DaysRemaining = int(time.Until(info.ExpiryDate).Hours() / 24)
+		domain.DaysRemaining = models.DaysUntil(info.ExpiryDate, time.Now())
+	}
+
+	// A later expiry date means the domain was renewed: re-arm the expiry alerts
+	if !previousExpiry.IsZero() && info.ExpiryDate.After(previousExpiry) {
+		domain.LastAlertThreshold = nil
 	}
 
 	// Save to database
-	db := database.GetDB()
-	if err := db.Save(domain).Error; err != nil {
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(domain).Error
+	}); err != nil {
 		return fmt.Errorf("failed to save domain: %w", err)
 	}
 
-	log.Printf("Updated domain %s: %d days remaining", domain.Name, domain.DaysRemaining)
+	slog.InfoContext(ctx, "Updated domain", "domain", domain.Name, "days_remaining", domain.DaysRemaining)
+	s.recordHistory(domain, nil)
+
+	// Report an unexpected drop in days remaining
+	if !previousExpiry.IsZero() && !previousChecked.IsZero() && !info.ExpiryDate.IsZero() {
+		s.checkAnomaly(domain, previousDays, previousExpiry, previousChecked)
+	}
+
+	// Alert once when the domain starts pointing at a parking provider
+	if domain.Parked && !wasParked {
+		s.notifyParking(domain, parkedBy)
+	}
+
+	// Report registrar or name server changes, which may indicate a transfer or hijack
+	if changes := registrationChanges(s.Messages(), previousRegistrar, info.Registrar, previousNameServers, info.NameServers); len(changes) > 0 {
+		s.notifyChange(domain, changes)
+	}
+
+	// Report changed DNS records, which may indicate a hijack or misconfiguration
+	if s.dnsService != nil {
+		s.checkDNS(ctx, domain)
+	}
 
 	// Check if notification is needed
-	s.CheckAndNotify(domain)
+	if domain.CheckStatus != models.CheckStatusParseError {
+		s.CheckAndNotify(domain)
+	}
+
+	return nil
+}
+
+// matchParking returns the name servers matching a configured parking pattern
+func (s *MonitorService) matchParking(nameServers []string) []string {
+	matched := make([]string, 0)
+	for _, ns := range nameServers {
+		ns = strings.ToLower(strings.TrimSuffix(ns, "."))
+		for _, pattern := range s.parkingPatterns {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if pattern == "" {
+				continue
+			}
+			if ok, _ := path.Match(pattern, ns); ok || strings.Contains(ns, pattern) {
+				matched = append(matched, ns)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// notifyParking sends an alert that the domain now uses parking name servers
+func (s *MonitorService) notifyParking(domain *models.Domain, nameServers []string) {
+	slog.Warn("Domain points to parking name servers", "domain", domain.Name, "name_servers", nameServers)
+
+	if !s.canNotify(domain) {
+		return
+	}
+
+	m := s.Messages()
+	alert := &Alert{
+		Kind:          AlertParking,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+		Severity:      SeverityCritical,
+		Title:         m.ParkingTitle,
+		Message:       fmt.Sprintf(m.ParkingMessage, strings.Join(nameServers, ", ")),
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send parking notification", "domain", domain.Name, "error", err)
+	}
+}
+
+// canNotify reports whether alerts may be sent for the domain
+func (s *MonitorService) canNotify(domain *models.Domain) bool {
+	return s.notifyService != nil && domain.NotifyEnabled
+}
+
+// markAvailable records that the domain is not registered; this is a successful check, not a failure
+func (s *MonitorService) markAvailable(domain *models.Domain, reason error) error {
+	domain.Status = models.StatusAvailable
+	domain.LastChecked = models.NullTime(time.Now())
+	domain.LastError = ""
+	domain.ConsecutiveFailures = 0
+	domain.CheckStatus = models.CheckStatusOK
+	domain.LastCheckError = ""
 
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", domain.ID).Updates(map[string]interface{}{
+			"status":               domain.Status,
+			"last_checked":         domain.LastChecked,
+			"last_error":           "",
+			"consecutive_failures": 0,
+			"check_status":         domain.CheckStatus,
+			"last_check_error":     "",
+		}).Error
+	}); err != nil {
+		return fmt.Errorf("failed to save domain: %w", err)
+	}
+	s.recordHistory(domain, nil)
+
+	slog.Warn("Domain is not registered", "domain", domain.Name, "reason", reason)
 	return nil
 }
 
+// recordFailure counts a failed check and only sets the domain error once
+// failureThreshold checks in a row have failed, so transient blips stay hidden
+func (s *MonitorService) recordFailure(domain *models.Domain, checkErr error) {
+	domain.ConsecutiveFailures++
+	domain.CheckStatus = models.CheckStatusQueryError
+	domain.LastCheckError = checkErr.Error()
+	updates := map[string]interface{}{
+		"consecutive_failures": domain.ConsecutiveFailures,
+		"check_status":         domain.CheckStatus,
+		"last_check_error":     domain.LastCheckError,
+	}
+	if domain.ConsecutiveFailures >= s.failureThreshold {
+		domain.LastError = checkErr.Error()
+		updates["last_error"] = domain.LastError
+	}
+
+	// Stop checking domains that keep failing, e.g. deleted at the registrar or unsupported TLDs
+	disable := s.autoDisableThreshold > 0 && domain.ConsecutiveFailures >= s.autoDisableThreshold && domain.IsActive
+	if disable {
+		domain.IsActive = false
+		updates["is_active"] = false
+	}
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", domain.ID).Updates(updates).Error
+	}); err != nil {
+		slog.Error("Failed to record check failure", "domain", domain.Name, "error", err)
+		return
+	}
+
+	if disable {
+		s.notifyDisabled(domain, checkErr)
+	}
+}
+
+// notifyDisabled sends a one-time alert that monitoring of a domain was turned off
+func (s *MonitorService) notifyDisabled(domain *models.Domain, checkErr error) {
+	slog.Warn("Disabled monitoring after consecutive failed checks", "domain", domain.Name, "failures", domain.ConsecutiveFailures, "error", checkErr)
+
+	if !s.canNotify(domain) {
+		return
+	}
+
+	m := s.Messages()
+	alert := &Alert{
+		Kind:          AlertDisabled,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+		Severity:      SeverityWarning,
+		Title:         m.DisabledTitle,
+		Message:       fmt.Sprintf(m.DisabledMessage, domain.ConsecutiveFailures, checkErr),
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send auto-disable notification", "domain", domain.Name, "error", err)
+	}
+}
+
 // CheckAndNotify checks if notification should be sent
 func (s *MonitorService) CheckAndNotify(domain *models.Domain) {
-	// Skip if notification service is not available
-	if s.notifyService == nil {
+	if s.alertMode == AlertModeTransition {
+		s.notifyTransition(domain)
 		return
 	}
 
-	// Check if domain is about to expire
-	for _, threshold := range s.alertDays {
-		if domain.DaysRemaining == threshold {
-			log.Printf("Sending notification for domain %s (%d days remaining)", domain.Name, domain.DaysRemaining)
-			if err := s.notifyService.SendNotification(domain, threshold); err != nil {
-				log.Printf("Failed to send notification for %s: %v", domain.Name, err)
-			}
-			break
+	threshold, crossed := crossedThreshold(s.alertDaysFor(domain), domain.DaysRemaining)
+
+	// Back above every threshold (e.g. renewed): re-arm the alerts
+	if !crossed {
+		if domain.LastAlertThreshold != nil {
+			s.setLastAlertThreshold(domain, nil)
 		}
+		return
+	}
+
+	// Each threshold fires once per crossing, even if checks skipped the exact day
+	if domain.LastAlertThreshold != nil && threshold >= *domain.LastAlertThreshold {
+		return
+	}
+
+	// Skip if notification service is not available or the domain is muted
+	if !s.canNotify(domain) {
+		return
+	}
+
+	// Domains marked as retiring are tracked until they expire but never alert
+	if domain.Retiring {
+		slog.Info("Skipping expiry notification for retiring domain", "domain", domain.Name, "days_remaining", domain.DaysRemaining)
+		return
+	}
+
+	slog.Info("Sending expiry notification", "domain", domain.Name, "days_remaining", domain.DaysRemaining, "threshold", threshold)
+	queued, err := s.sendExpiry(domain, threshold)
+	if err != nil {
+		slog.Error("Failed to send expiry notification", "domain", domain.Name, "error", err)
+		return
+	}
+	if queued {
+		return
+	}
+
+	s.setLastAlertThreshold(domain, &threshold)
+}
+
+// notifyTransition alerts when the domain's urgency bucket got worse since the last check
+func (s *MonitorService) notifyTransition(domain *models.Domain) {
+	bucket := urgencyBucket(domain.DaysRemaining)
+	if bucket == domain.LastBucket {
+		return
+	}
+
+	// Only escalations alert; the first check of a healthy domain and
+	// de-escalations (e.g. after renewal) are just recorded
+	escalated := bucketRank[bucket] > bucketRank[domain.LastBucket]
+	if escalated && !(domain.LastBucket == "" && bucket == BucketNormal) && s.canNotify(domain) && !domain.Retiring {
+		slog.Info("Sending expiry notification", "domain", domain.Name, "days_remaining", domain.DaysRemaining, "from_bucket", defaultString(domain.LastBucket, "none"), "bucket", bucket)
+		queued, err := s.sendExpiry(domain, domain.DaysRemaining)
+		if err != nil {
+			slog.Error("Failed to send expiry notification", "domain", domain.Name, "error", err)
+			return
+		}
+		if queued {
+			return
+		}
+	}
+
+	s.setLastBucket(domain, bucket)
+}
+
+// setLastBucket records the urgency bucket of a domain
+func (s *MonitorService) setLastBucket(domain *models.Domain, bucket string) {
+	domain.LastBucket = bucket
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(domain).Update("last_bucket", bucket).Error
+	}); err != nil {
+		slog.Error("Failed to record urgency bucket", "domain", domain.Name, "error", err)
+	}
+}
+
+// urgencyBucket returns the urgency bucket for the days remaining
+func urgencyBucket(daysRemaining int) string {
+	switch {
+	case daysRemaining <= 0:
+		return BucketExpired
+	case daysRemaining <= 7:
+		return BucketCritical
+	case daysRemaining <= 30:
+		return BucketWarning
+	default:
+		return BucketNormal
+	}
+}
+
+// crossedThreshold returns the lowest alert threshold at or above the days remaining
+func crossedThreshold(alertDays []int, daysRemaining int) (int, bool) {
+	threshold, crossed := 0, false
+	for _, alertDay := range alertDays {
+		if daysRemaining <= alertDay && (!crossed || alertDay < threshold) {
+			threshold, crossed = alertDay, true
+		}
+	}
+	return threshold, crossed
+}
+
+// setLastAlertThreshold records the last alerted threshold of a domain
+func (s *MonitorService) setLastAlertThreshold(domain *models.Domain, threshold *int) {
+	domain.LastAlertThreshold = threshold
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(domain).Update("last_alert_threshold", threshold).Error
+	}); err != nil {
+		slog.Error("Failed to record alert threshold", "domain", domain.Name, "error", err)
+	}
+}
+
+// registrationChanges describes the registrar and name server changes between two checks.
+// Values missing on either side are ignored since WHOIS responses often omit them.
+func registrationChanges(m *Messages, oldRegistrar, newRegistrar string, oldNameServers, newNameServers []string) []string {
+	changes := make([]string, 0)
+
+	if oldRegistrar != "" && newRegistrar != "" && !strings.EqualFold(strings.TrimSpace(oldRegistrar), strings.TrimSpace(newRegistrar)) {
+		changes = append(changes, fmt.Sprintf(m.ChangeRegistrar, oldRegistrar, newRegistrar))
+	}
+
+	if len(oldNameServers) > 0 && len(newNameServers) > 0 && !sameNameServers(oldNameServers, newNameServers) {
+		changes = append(changes, fmt.Sprintf(m.ChangeNameServers, strings.Join(oldNameServers, ", "), strings.Join(newNameServers, ", ")))
+	}
+
+	return changes
+}
+
+// sameNameServers compares name server lists ignoring order, case and trailing dots
+func sameNameServers(a, b []string) bool {
+	normalize := func(nameServers []string) []string {
+		normalized := make([]string, 0, len(nameServers))
+		for _, ns := range nameServers {
+			normalized = append(normalized, strings.TrimSuffix(strings.ToLower(strings.TrimSpace(ns)), "."))
+		}
+		slices.Sort(normalized)
+		return slices.Compact(normalized)
+	}
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+// notifyChange sends an alert about registrar or name server changes
+func (s *MonitorService) notifyChange(domain *models.Domain, changes []string) {
+	slog.Warn("Domain registration changed", "domain", domain.Name, "changes", strings.Join(changes, "; "))
+
+	if !s.canNotify(domain) {
+		return
+	}
+
+	m := s.Messages()
+	alert := &Alert{
+		Kind:          AlertChange,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+		Severity:      SeverityCritical,
+		Title:         m.ChangeTitle,
+		Message:       fmt.Sprintf(m.ChangeMessage, strings.Join(changes, "\n")),
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send change notification", "domain", domain.Name, "error", err)
+	}
+}
+
+// checkAnomaly sends a warning when days remaining dropped by more than the elapsed time
+func (s *MonitorService) checkAnomaly(domain *models.Domain, previousDays int, previousExpiry, previousChecked time.Time) {
+	elapsedDays := int(time.Since(previousChecked).Hours() / 24)
+	expectedDays := previousDays - elapsedDays
+	if domain.DaysRemaining >= expectedDays-s.anomalyTolerance {
+		return
+	}
+
+	slog.Warn("Days remaining dropped unexpectedly", "domain", domain.Name,
+		"previous_days", previousDays, "days_remaining", domain.DaysRemaining, "expected_days", expectedDays)
+
+	if !s.canNotify(domain) {
+		return
+	}
+
+	m := s.Messages()
+	alert := &Alert{
+		Kind:          AlertAnomaly,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+		Severity:      SeverityWarning,
+		Title:         m.AnomalyTitle,
+		Message: fmt.Sprintf(m.AnomalyMessage,
+			models.InLocation(previousChecked).Format("2006-01-02 15:04"),
+			previousDays,
+			models.FormatDate(previousExpiry),
+			domain.DaysRemaining,
+			models.FormatDate(models.TimeValue(domain.ExpiryDate)),
+			expectedDays,
+		),
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send anomaly notification", "domain", domain.Name, "error", err)
 	}
 }
 
@@ -106,7 +870,7 @@ func (s *MonitorService) TriggerNotification(domain *models.Domain) error {
 		return fmt.Errorf("notification service not available")
 	}
 
-	log.Printf("Triggering test notification for domain %s (%d days remaining)", domain.Name, domain.DaysRemaining)
+	slog.Info("Triggering test notification", "domain", domain.Name, "days_remaining", domain.DaysRemaining)
 
 	return s.notifyService.SendNotification(domain, domain.DaysRemaining)
 }
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckDomainAlertsWhenThresholdSkipped(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{AlertDays: []int{30, 7, 1}})
+	domain := createTestDomain(t, "example.com")
+
+	api.expireIn(35)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if got := len(channel.sentKind(AlertExpiry)); got != 0 {
+		t.Fatalf("sent %d expiry alerts at 35 days, want 0", got)
+	}
+
+	// The checks missed both the 30-day and the 7-day mark
+	api.expireIn(5)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	alerts := channel.sentKind(AlertExpiry)
+	if len(alerts) != 1 {
+		t.Fatalf("sent %d expiry alerts at 5 days, want 1", len(alerts))
+	}
+	if alerts[0].DaysRemaining != 5 {
+		t.Errorf("alert DaysRemaining = %d, want 5", alerts[0].DaysRemaining)
+	}
+	if domain.LastAlertThreshold == nil || *domain.LastAlertThreshold != 7 {
+		t.Errorf("LastAlertThreshold = %v, want 7", domain.LastAlertThreshold)
+	}
+}
+
+func TestCheckDomainAlertsOncePerThreshold(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{AlertDays: []int{30, 7, 1}})
+	domain := createTestDomain(t, "example.com")
+
+	api.expireIn(7)
+	for range 2 {
+		if err := monitor.CheckDomain(domain); err != nil {
+			t.Fatalf("CheckDomain: %v", err)
+		}
+	}
+	if got := len(channel.sentKind(AlertExpiry)); got != 1 {
+		t.Fatalf("sent %d expiry alerts for two checks at 7 days, want 1", got)
+	}
+
+	// Renewal re-arms the thresholds
+	api.expireIn(365)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if domain.LastAlertThreshold != nil {
+		t.Errorf("LastAlertThreshold = %d after renewal, want nil", *domain.LastAlertThreshold)
+	}
+
+	api.expireIn(7)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if got := len(channel.sentKind(AlertExpiry)); got != 2 {
+		t.Errorf("sent %d expiry alerts, want 2 after the renewed domain reached 7 days again", got)
+	}
+}
+
+func TestCheckAllDomainsConcurrencyCapped(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{Concurrency: 3})
+	api.expireIn(200)
+	api.setDelay(20 * time.Millisecond)
+
+	const count = 12
+	for i := range count {
+		createTestDomain(t, fmt.Sprintf("example%d.com", i))
+	}
+
+	if err := monitor.CheckAllDomains(); err != nil {
+		t.Fatalf("CheckAllDomains: %v", err)
+	}
+
+	if got := api.queryCount(); got != count {
+		t.Errorf("WHOIS queried %d times, want %d", got, count)
+	}
+	if peak := api.peakConcurrency(); peak > 3 || peak < 2 {
+		t.Errorf("peak concurrency = %d, want parallel checks capped at 3", peak)
+	}
+
+	var checked int64
+	database.DB.Model(&models.Domain{}).Where("last_checked IS NOT NULL AND days_remaining > 0").Count(&checked)
+	if checked != count {
+		t.Errorf("%d domains updated, want %d", checked, count)
+	}
+}
+
+func TestCheckDomainContextCancelled(t *testing.T) {
+	setupTestDB(t)
+	monitor, _, _ := newTestMonitor(t, config.MonitorConfig{})
+	monitor.whoisService = NewWhoisService(&config.WhoisConfig{APIURL: newHangingAPI(t).URL, CacheTTL: "0"})
+	domain := createTestDomain(t, "example.com")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	err := monitor.CheckDomainContext(ctx, domain)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	// A cancelled check is not a failure of the domain
+	var stored models.Domain
+	database.DB.First(&stored, domain.ID)
+	if stored.ConsecutiveFailures != 0 || stored.LastError != "" {
+		t.Errorf("failures = %d, last error = %q; want none", stored.ConsecutiveFailures, stored.LastError)
+	}
+	var history int64
+	database.DB.Model(&models.DomainCheckHistory{}).Count(&history)
+	if history != 0 {
+		t.Errorf("%d history rows recorded for a cancelled check", history)
+	}
+}
+
+func TestShutdownCancelsChecks(t *testing.T) {
+	setupTestDB(t)
+	monitor, _, _ := newTestMonitor(t, config.MonitorConfig{})
+	monitor.whoisService = NewWhoisService(&config.WhoisConfig{APIURL: newHangingAPI(t).URL, CacheTTL: "0"})
+	domain := createTestDomain(t, "example.com")
+
+	checked := make(chan error, 1)
+	go func() { checked <- monitor.CheckDomain(domain) }()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := monitor.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want the deadline to pass while the check hangs", err)
+	}
+
+	select {
+	case err := <-checked:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("check err = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("check still running after shutdown")
+	}
+}
+
+func TestCheckDomainAlertsOnRegistrationChange(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(200)
+	api.setNameServers("ns1.example.net", "ns2.example.net")
+	domain := createTestDomain(t, "example.com")
+
+	// The first check only records the registration
+	for range 2 {
+		if err := monitor.CheckDomain(domain); err != nil {
+			t.Fatalf("CheckDomain: %v", err)
+		}
+	}
+	if got := len(channel.sentKind(AlertChange)); got != 0 {
+		t.Fatalf("sent %d change alerts without a change", got)
+	}
+
+	api.setRegistrar("Other Registrar")
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	alerts := channel.sentKind(AlertChange)
+	if len(alerts) != 1 {
+		t.Fatalf("sent %d change alerts after a registrar change, want 1", len(alerts))
+	}
+	if !strings.Contains(alerts[0].Message, "Example Registrar") || !strings.Contains(alerts[0].Message, "Other Registrar") {
+		t.Errorf("change message = %q, want the old and new registrar", alerts[0].Message)
+	}
+	if alerts[0].Severity != SeverityCritical {
+		t.Errorf("severity = %q", alerts[0].Severity)
+	}
+
+	api.setNameServers("ns1.attacker.example", "ns2.example.net")
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	alerts = channel.sentKind(AlertChange)
+	if len(alerts) != 2 || !strings.Contains(alerts[1].Message, "ns1.attacker.example") {
+		t.Errorf("change alerts = %d, want one more naming the new name server", len(alerts))
+	}
+}
+
+func TestCheckDomainAutoDisable(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{AutoDisableThreshold: 3})
+	api.expireIn(200)
+	domain := createTestDomain(t, "example.com")
+
+	api.setFailing(true)
+	for i := 1; i <= 2; i++ {
+		monitor.CheckDomain(domain)
+		if !domain.IsActive || domain.ConsecutiveFailures != i {
+			t.Fatalf("after %d failures: active = %v, failures = %d", i, domain.IsActive, domain.ConsecutiveFailures)
+		}
+	}
+
+	// A successful check resets the counter
+	api.setFailing(false)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if domain.ConsecutiveFailures != 0 {
+		t.Fatalf("failures = %d after a successful check, want 0", domain.ConsecutiveFailures)
+	}
+
+	api.setFailing(true)
+	for range 3 {
+		monitor.CheckDomain(domain)
+	}
+
+	var stored models.Domain
+	database.DB.First(&stored, domain.ID)
+	if stored.IsActive || stored.ConsecutiveFailures != 3 {
+		t.Errorf("stored active = %v, failures = %d; want disabled after 3 failures", stored.IsActive, stored.ConsecutiveFailures)
+	}
+	if got := len(channel.sentKind(AlertDisabled)); got != 1 {
+		t.Errorf("sent %d auto-disable alerts, want 1", got)
+	}
+
+	// Further failures of the disabled domain don't notify again
+	monitor.CheckDomain(domain)
+	if got := len(channel.sentKind(AlertDisabled)); got != 1 {
+		t.Errorf("sent %d auto-disable alerts after another failure, want 1", got)
+	}
+}
+
+func TestCheckDomainNoAutoDisableByDefault(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, channel := newTestMonitor(t, config.MonitorConfig{})
+	api.setFailing(true)
+	domain := createTestDomain(t, "example.com")
+
+	for range 5 {
+		monitor.CheckDomain(domain)
+	}
+	if !domain.IsActive || len(channel.sentKind(AlertDisabled)) != 0 {
+		t.Errorf("active = %v, want monitoring kept without auto_disable_threshold", domain.IsActive)
+	}
+}
+
+func TestCheckAllDomainsSkipsDomainsNotDue(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(200)
+
+	// Weekly checks, last checked a day ago
+	weekly := createTestDomain(t, "weekly.example")
+	checked := time.Now().AddDate(0, 0, -1)
+	database.DB.Model(weekly).Updates(map[string]any{"check_frequency_days": 7, "last_checked": checked})
+	// Weekly checks, last checked eight days ago
+	overdue := createTestDomain(t, "overdue.example")
+	database.DB.Model(overdue).Updates(map[string]any{"check_frequency_days": 7, "last_checked": time.Now().AddDate(0, 0, -8)})
+	// Default: checked on every sweep
+	daily := createTestDomain(t, "daily.example")
+	database.DB.Model(daily).Update("last_checked", checked)
+
+	if err := monitor.CheckAllDomains(); err != nil {
+		t.Fatalf("CheckAllDomains: %v", err)
+	}
+
+	if got := api.queryCount(); got != 2 {
+		t.Errorf("WHOIS queried %d times, want the overdue and the default domain", got)
+	}
+	var stored models.Domain
+	database.DB.First(&stored, weekly.ID)
+	if !stored.LastChecked.Equal(checked) {
+		t.Errorf("weekly domain checked at %v, want it skipped", stored.LastChecked)
+	}
+}
+
+func TestCheckDomainStoresRawWhois(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(100)
+	api.setNameServers("ns1.example.net")
+	domain := createTestDomain(t, "example.com")
+
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+
+	var stored models.Domain
+	database.DB.First(&stored, domain.ID)
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(stored.RawWhois), &raw); err != nil {
+		t.Fatalf("raw WHOIS %q is not the API's JSON: %v", stored.RawWhois, err)
+	}
+	if raw["registrar"] != "Example Registrar" || raw["expirationDate"] == nil {
+		t.Errorf("raw WHOIS = %v, want the API's answer", raw)
+	}
+
+	// A failed check keeps the last response
+	api.setFailing(true)
+	monitor.CheckDomain(domain)
+	var after models.Domain
+	database.DB.First(&after, domain.ID)
+	if after.RawWhois != stored.RawWhois {
+		t.Errorf("raw WHOIS changed to %q by a failed check", after.RawWhois)
+	}
+}
+
+func TestCheckDomainUnparseableExpiry(t *testing.T) {
+	setupTestDB(t)
+	whoisAPI := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"data":{"registrar":"Example Registrar","expirationDate":"in a while"}}`)
+	}))
+	defer whoisAPI.Close()
+
+	monitor, _, channel := newTestMonitor(t, config.MonitorConfig{})
+	monitor.whoisService = NewWhoisService(&config.WhoisConfig{APIURL: whoisAPI.URL, CacheTTL: "0"})
+	domain := createTestDomain(t, "example.com")
+
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+
+	var stored models.Domain
+	database.DB.First(&stored, domain.ID)
+	if stored.CheckStatus != models.CheckStatusParseError || stored.LastCheckError == "" {
+		t.Errorf("check status = %q (%q), want parse_error with a reason", stored.CheckStatus, stored.LastCheckError)
+	}
+	if stored.ExpiryDate != nil || stored.Registrar != "Example Registrar" {
+		t.Errorf("expiry = %v, registrar = %q; want no expiry and the parsed registrar", stored.ExpiryDate, stored.Registrar)
+	}
+	// Zero days remaining would look expired, so no alert is sent
+	if alerts := channel.sent(); len(alerts) != 0 {
+		t.Errorf("%d alerts sent for an unparseable expiry, want 0", len(alerts))
+	}
+}
+
+func TestCheckDomainStatusRecovers(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{})
+	domain := createTestDomain(t, "example.com")
+
+	// The fake API reports the zero time when no expiry is set
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if domain.CheckStatus != models.CheckStatusParseError {
+		t.Fatalf("check status = %q, want parse_error for a zero expiry", domain.CheckStatus)
+	}
+
+	api.expireIn(100)
+	if err := monitor.CheckDomain(domain); err != nil {
+		t.Fatalf("CheckDomain: %v", err)
+	}
+	if domain.CheckStatus != models.CheckStatusOK || domain.LastCheckError != "" || domain.DaysRemaining != 100 {
+		t.Errorf("status = %q (%q), %d days; want ok with 100 days", domain.CheckStatus, domain.LastCheckError, domain.DaysRemaining)
+	}
+}
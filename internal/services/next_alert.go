@@ -0,0 +1,84 @@
+package services
+
+import (
+	"domain-monitor/internal/models"
+	"sort"
+	"time"
+)
+
+// transitionThresholds are the urgency bucket boundaries of the transition alert mode
+var transitionThresholds = map[int]string{30: BucketWarning, 7: BucketCritical, 0: BucketExpired}
+
+// NextAlert previews the next expiry alert of a domain from its expiry date and effective
+// alert policy. It returns nil when the expiry date is unknown or no alert is left.
+func (s *MonitorService) NextAlert(domain *models.Domain) *models.NextAlert {
+	if domain.ExpiryDate == nil {
+		return nil
+	}
+
+	policy := LoadAlertPolicy(domain)
+
+	// Thresholds that can still fire, from the lowest
+	var pending []int
+	if s.alertMode == AlertModeTransition {
+		for days, bucket := range transitionThresholds {
+			if bucketRank[bucket] > bucketRank[domain.LastBucket] {
+				pending = append(pending, days)
+			}
+		}
+	} else {
+		alertDays := s.alertDays
+		if policy != nil {
+			alertDays = policy.Days()
+		}
+		for _, days := range alertDays {
+			if domain.LastAlertThreshold == nil || days < *domain.LastAlertThreshold {
+				pending = append(pending, days)
+			}
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	sort.Ints(pending)
+
+	// The lowest crossed threshold fires on the next check, otherwise the highest one ahead
+	next := &models.NextAlert{Threshold: pending[len(pending)-1]}
+	for _, days := range pending {
+		if days >= domain.DaysRemaining {
+			next.Threshold, next.Due = days, true
+			break
+		}
+	}
+	if next.Due {
+		next.Date = time.Now()
+	} else {
+		next.Date = domain.ExpiryDate.AddDate(0, 0, -next.Threshold)
+	}
+
+	if s.notifyService != nil {
+		next.Channels = s.notifyService.ChannelsFor(policy)
+	}
+
+	switch {
+	case !domain.NotifyEnabled:
+		next.Suppressed = "muted"
+	case domain.Retiring:
+		next.Suppressed = "retiring"
+	case domain.IsPaused(next.Date):
+		next.Suppressed = "paused"
+	}
+
+	return next
+}
+
+// ChannelsFor returns the domain alert channels allowed by an alert policy
+func (s *NotifyService) ChannelsFor(policy *models.AlertPolicy) []string {
+	channels := make([]string, 0, len(s.notifiers))
+	for _, notifier := range s.notifiers {
+		if ch := notifier.Type(); policy == nil || policy.AllowsChannel(ch) {
+			channels = append(channels, ch)
+		}
+	}
+	return channels
+}
@@ -5,80 +5,306 @@ import (
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
 	"domain-monitor/internal/config"
 	"domain-monitor/internal/database"
+	"domain-monitor/internal/metrics"
 	"domain-monitor/internal/models"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"net/http"
 	"net/smtp"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"golang.org/x/net/proxy"
+	"gorm.io/gorm"
 )
 
+// Severity levels for alerts
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Alert kinds
+const (
+	AlertExpiry   = "expiry"   // Domain is approaching expiry
+	AlertAnomaly  = "anomaly"  // Days remaining dropped unexpectedly between checks
+	AlertParking  = "parking"  // Name servers point to a parking/for-sale provider
+	AlertChange   = "change"   // Registrar or name servers changed between checks
+	AlertDNS      = "dns"      // Watched DNS records changed between checks
+	AlertUptime   = "uptime"   // Site of the domain went down or recovered
+	AlertDisabled = "disabled" // Monitoring was turned off after repeated check failures
+	AlertSystem   = "system"   // Health of the monitoring system itself (no domain)
+	AlertDigest   = "digest"   // Expiry alerts of a scheduled run, sent together (no domain)
+)
+
+// Alert describes a notification to deliver through the notifiers
+type Alert struct {
+	Kind          string         // Alert kind (expiry/anomaly/parking/change/dns/uptime/disabled/system/digest)
+	Domain        *models.Domain // nil for system alerts
+	DaysRemaining int
+	Severity      string        // info/warning/critical
+	Title         string        // Title for non-expiry alerts
+	Message       string        // Custom message for the triggered threshold, or details of other alerts
+	Body          string        // Rendered group template replacing the default body (optional)
+	Digest        []DomainAlert // Domains of a digest alert
+	Language      string        // Language of the notification text, set by the notify service
+}
+
+// IsExpiry reports whether the alert is a regular expiry reminder
+func (a *Alert) IsExpiry() bool {
+	return a.Kind == "" || a.Kind == AlertExpiry
+}
+
+// Subject returns a one-line subject for the alert
+func (a *Alert) Subject() string {
+	m := a.messages()
+	if a.IsExpiry() {
+		return fmt.Sprintf(m.ExpirySubject, a.Domain.Name, a.DaysRemaining)
+	}
+	if a.Domain == nil {
+		return a.Title
+	}
+	return m.Field(a.Title, a.Domain.Name)
+}
+
+// messages returns the notification text in the alert's language
+func (a *Alert) messages() *Messages {
+	return MessagesFor(a.Language)
+}
+
+// logArgs returns the alert fields attached to log records
+func (a *Alert) logArgs() []any {
+	args := []any{"kind", defaultString(a.Kind, AlertExpiry)}
+	if a.Domain != nil {
+		args = append(args, "domain", a.Domain.Name, "days_remaining", a.DaysRemaining)
+	}
+	return args
+}
+
+// domainLine returns the domain line of a non-expiry message, empty for system alerts.
+// The line is the localized label and the domain name in format, e.g. "**%s**: %s\n\n".
+func (a *Alert) domainLine(format string) string {
+	if a.Domain == nil {
+		return ""
+	}
+	return fmt.Sprintf(format, a.messages().Domain, a.Domain.Name)
+}
+
+// Summary returns the text recorded in the notification history
+func (a *Alert) Summary() string {
+	if a.IsExpiry() {
+		return fmt.Sprintf("Domain %s expires in %d days", a.Domain.Name, a.DaysRemaining)
+	}
+	return fmt.Sprintf("%s: %s", a.Subject(), a.Message)
+}
+
+// pushMessage returns the title and plain-text body of a mobile push notification
+func pushMessage(alert *Alert) (string, string) {
+	m := alert.messages()
+	if !alert.IsExpiry() {
+		return severityEmoji(alert.Severity) + " " + alert.Title, alert.domainLine("%s"+m.Colon+"%s\n") + alert.Message
+	}
+
+	title := severityEmoji(alert.Severity) + " " + m.ExpiryTitle
+	if alert.Body != "" {
+		return title, alert.Body
+	}
+
+	domain := alert.Domain
+	body := strings.Join([]string{
+		m.Field(m.Domain, domain.Name),
+		m.Field(m.DaysRemaining, m.DaysText(alert.DaysRemaining)),
+		m.Field(m.ExpiryDate, models.FormatDate(models.TimeValue(domain.ExpiryDate))),
+		m.Field(m.Registrar, domain.Registrar),
+	}, "\n")
+	if alert.Message != "" {
+		body += "\n\n" + alert.Message
+	}
+	return title, body
+}
+
 // Notifier interface for different notification types
 type Notifier interface {
-	Send(domain *models.Domain, daysRemaining int) error
+	Send(alert *Alert) error
+	Type() string // Stable channel identifier (email, webhook, ...) recorded in the notification history
 }
 
 // NotifyService handles notifications
 type NotifyService struct {
-	notifiers []Notifier
+	notifiers       []Notifier
+	systemNotifiers []Notifier // Channels dedicated to system health alerts
+	thresholds      []config.ThresholdConfig
+	concurrency     int           // Channels sent to in parallel
+	maxRetries      int           // Retries per channel after a failed send
+	retryDelay      time.Duration // Delay before the first retry, doubled each attempt
+	language        string        // Language of the notification text
+
+	systemMu   sync.Mutex
+	systemSent map[string]time.Time // Last send time per system alert, for the cooldown
 }
 
+// systemAlertCooldown suppresses repeats of an identical system alert
+const systemAlertCooldown = time.Hour
+
 // NewNotifyService creates a new notification service
 func NewNotifyService(cfg *config.NotificationsConfig) *NotifyService {
-	service := &NotifyService{
-		notifiers: make([]Notifier, 0),
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultNotifyRetries
 	}
-
-	// Add enabled notifiers
-	if cfg.Email.Enabled {
-		service.notifiers = append(service.notifiers, NewEmailNotifier(&cfg.Email))
+	retryDelay, err := time.ParseDuration(cfg.RetryDelay)
+	if err != nil || retryDelay <= 0 {
+		retryDelay = defaultNotifyRetryDelay
 	}
 
-	if cfg.Webhook.Enabled {
-		service.notifiers = append(service.notifiers, NewWebhookNotifier(&cfg.Webhook))
+	language, err := NormalizeLanguage(cfg.Language)
+	if err != nil {
+		slog.Warn("Falling back to the default notification language", "language", DefaultLanguage, "error", err)
+		language = DefaultLanguage
 	}
 
-	if cfg.Telegram.Enabled {
-		service.notifiers = append(service.notifiers, NewTelegramNotifier(&cfg.Telegram))
+	service := &NotifyService{
+		notifiers:   make([]Notifier, 0),
+		thresholds:  cfg.Thresholds,
+		concurrency: cfg.Concurrency,
+		maxRetries:  maxRetries,
+		retryDelay:  retryDelay,
+		language:    language,
+		systemSent:  make(map[string]time.Time),
 	}
 
-	if cfg.DingDing.Enabled {
-		service.notifiers = append(service.notifiers, NewDingDingNotifier(&cfg.DingDing))
+	// Add enabled notifiers; the system channel is kept apart from domain alerts
+	for _, channel := range channels {
+		if !channel.enabled(cfg) {
+			continue
+		}
+		if channel.Type == cfg.SystemChannel {
+			service.systemNotifiers = append(service.systemNotifiers, channel.build(cfg))
+		} else {
+			service.notifiers = append(service.notifiers, channel.build(cfg))
+		}
 	}
 
 	return service
 }
 
-// SendNotification sends notification through all enabled channels
+// Messages returns the notification text in the configured language; a nil service
+// returns the default language
+func (s *NotifyService) Messages() *Messages {
+	if s == nil {
+		return MessagesFor(DefaultLanguage)
+	}
+	return MessagesFor(s.language)
+}
+
+// SendNotification sends an expiry notification through all enabled channels
 func (s *NotifyService) SendNotification(domain *models.Domain, daysRemaining int) error {
-	var lastErr error
-	successCount := 0
+	return s.SendAlert(s.buildAlert(domain, daysRemaining, daysRemaining))
+}
 
-	for _, notifier := range s.notifiers {
-		notifierType := fmt.Sprintf("%T", notifier)
-		if err := notifier.Send(domain, daysRemaining); err != nil {
-			fmt.Printf("[ERROR] %s notification failed: %v\n", notifierType, err)
-			lastErr = err
-			// Record failed notification
-			s.recordNotification(domain, notifier, "failed")
-			continue
+// SendThresholdNotification sends an expiry notification for a crossed alert threshold
+func (s *NotifyService) SendThresholdNotification(domain *models.Domain, threshold int) error {
+	return s.SendAlert(s.buildAlert(domain, domain.DaysRemaining, threshold))
+}
+
+// SendSystemAlert sends a health alert about the monitoring system to the system channel.
+// Without a system channel the alert is only logged; identical alerts are sent at most once per cooldown.
+func (s *NotifyService) SendSystemAlert(title, message string) error {
+	slog.Warn("System alert", "title", title, "message", message)
+	if len(s.systemNotifiers) == 0 {
+		return nil
+	}
+
+	key := title + "\n" + message
+	s.systemMu.Lock()
+	if last, ok := s.systemSent[key]; ok && time.Since(last) < systemAlertCooldown {
+		s.systemMu.Unlock()
+		return nil
+	}
+	// Messages often carry details such as error text, so expired keys are dropped
+	// to keep the map from growing without bound
+	for sentKey, sent := range s.systemSent {
+		if time.Since(sent) >= systemAlertCooldown {
+			delete(s.systemSent, sentKey)
 		}
+	}
+	s.systemSent[key] = time.Now()
+	s.systemMu.Unlock()
 
-		// Record successful notification
-		s.recordNotification(domain, notifier, "success")
-		successCount++
-		fmt.Printf("[SUCCESS] %s notification sent\n", notifierType)
+	alert := &Alert{
+		Kind:     AlertSystem,
+		Severity: SeverityCritical,
+		Title:    title,
+		Message:  message,
+	}
+	return s.sendAll(s.systemNotifiers, alert)
+}
+
+// SendAlert sends an alert through all enabled channels
+func (s *NotifyService) SendAlert(alert *Alert) error {
+	return s.sendAll(s.notifiers, alert)
+}
+
+// sendAll sends an alert through the given channels
+func (s *NotifyService) sendAll(notifiers []Notifier, alert *Alert) error {
+	var (
+		mu           sync.Mutex
+		lastErr      error
+		successCount int
+	)
+
+	if alert.Language == "" {
+		alert.Language = s.language
+	}
+
+	// Expiry reminders use the templates of the domain's group, if any
+	var group *models.DomainGroup
+	if alert.IsExpiry() && alert.Domain.GroupID != 0 {
+		group = loadGroup(alert.Domain.GroupID)
 	}
 
+	// Domain alerts only go to the channels of the domain's alert policy
+	var policy *models.AlertPolicy
+	if alert.Domain != nil {
+		policy = LoadAlertPolicy(alert.Domain)
+	}
+	if policy != nil {
+		allowed := make([]Notifier, 0, len(notifiers))
+		for _, notifier := range notifiers {
+			if policy.AllowsChannel(notifier.Type()) {
+				allowed = append(allowed, notifier)
+			}
+		}
+		notifiers = allowed
+	}
+
+	// Channels are sent to in parallel so that a slow channel doesn't hold up the others
+	runPool(notifiers, s.concurrency, func(notifier Notifier) {
+		if err := s.sendTo(notifier, alert, group, policy); err != nil {
+			mu.Lock()
+			lastErr = err
+			mu.Unlock()
+			return
+		}
+		mu.Lock()
+		successCount++
+		mu.Unlock()
+	})
+
 	if successCount > 0 && lastErr != nil {
 		// At least one succeeded, don't return error
 		return nil
@@ -87,96 +313,409 @@ func (s *NotifyService) SendNotification(domain *models.Domain, daysRemaining in
 	return lastErr
 }
 
+// sendTo sends an alert through one channel and records the result
+func (s *NotifyService) sendTo(notifier Notifier, alert *Alert, group *models.DomainGroup, policy *models.AlertPolicy) error {
+	// Group templates take precedence over the policy template
+	var tmpl string
+	if group != nil {
+		tmpl = group.TemplateFor(notifier.Type())
+	}
+	if tmpl == "" && policy != nil && alert.IsExpiry() {
+		tmpl = policy.Template
+	}
+
+	channelAlert := alert
+	if tmpl != "" {
+		data := alertData(alert)
+		if group != nil {
+			data.Group = group.Name
+		}
+		templated := *alert
+		templated.Body = renderMessage(tmpl, data)
+		channelAlert = &templated
+	}
+
+	if err := s.sendWithRetry(notifier, channelAlert); err != nil {
+		slog.Error("Notification failed", append(alert.logArgs(), "channel", notifier.Type(), "error", err)...)
+		// Record failed notification
+		s.recordNotification(alert, notifier, "failed")
+		return err
+	}
+
+	// Record successful notification
+	s.recordNotification(alert, notifier, "success")
+	slog.Info("Notification sent", append(alert.logArgs(), "channel", notifier.Type())...)
+	return nil
+}
+
+// buildAlert builds the alert for a threshold, applying the configured severity and message
+func (s *NotifyService) buildAlert(domain *models.Domain, daysRemaining, alertDay int) *Alert {
+	alert := &Alert{
+		Kind:          AlertExpiry,
+		Domain:        domain,
+		DaysRemaining: daysRemaining,
+		Severity:      severityForDays(daysRemaining),
+	}
+	data := alertData(alert)
+
+	for _, threshold := range s.thresholds {
+		if threshold.Days != alertDay {
+			continue
+		}
+		if threshold.Severity != "" {
+			alert.Severity = threshold.Severity
+		}
+		if threshold.Message != "" {
+			alert.Message = renderMessage(threshold.Message, data)
+		}
+		break
+	}
+
+	return alert
+}
+
+// severityForDays returns the default severity for the remaining days
+func severityForDays(daysRemaining int) string {
+	if daysRemaining <= 7 {
+		return SeverityCritical
+	} else if daysRemaining <= 30 {
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// severityEmoji returns the status emoji for a severity
+func severityEmoji(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "🔴"
+	case SeverityWarning:
+		return "🟡"
+	default:
+		return "🟢"
+	}
+}
+
+// messageData is the data available to message templates
+type messageData struct {
+	Event         string // Alert kind
+	Title         string
+	Domain        string
+	DaysRemaining int
+	ExpiryDate    string
+	Registrar     string
+	Status        string
+	Severity      string
+	Message       string // Threshold message, if configured
+	Group         string // Domain group name, for group templates
+}
+
+// alertData returns the template data for an alert
+func alertData(alert *Alert) messageData {
+	data := messageData{
+		Event:    alert.Kind,
+		Title:    alert.Title,
+		Severity: alert.Severity,
+		Message:  alert.Message,
+	}
+	if domain := alert.Domain; domain != nil {
+		data.Domain = domain.Name
+		data.DaysRemaining = alert.DaysRemaining
+		data.ExpiryDate = models.FormatDate(models.TimeValue(domain.ExpiryDate))
+		data.Registrar = domain.Registrar
+		data.Status = domain.Status
+	}
+	return data
+}
+
+// renderMessage renders a message template, falling back to the raw text on error
+func renderMessage(text string, data messageData) string {
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		slog.Error("Invalid message template", "template", text, "error", err)
+		return text
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		slog.Error("Failed to render message template", "template", text, "error", err)
+		return text
+	}
+
+	return buf.String()
+}
+
+// loadGroup loads a domain group, returning nil if it can't be found
+func loadGroup(id uint) *models.DomainGroup {
+	var group models.DomainGroup
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.First(&group, id).Error
+	}); err != nil {
+		return nil
+	}
+	return &group
+}
+
+// LoadAlertPolicy returns the alert policy of a domain, falling back to the default policy.
+// It returns nil when neither exists.
+func LoadAlertPolicy(domain *models.Domain) *models.AlertPolicy {
+	var policies []models.AlertPolicy
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		query := db.Where("is_default = ?", true)
+		if domain.AlertPolicyID != 0 {
+			query = query.Or("id = ?", domain.AlertPolicyID)
+		}
+		return query.Find(&policies).Error
+	}); err != nil {
+		return nil
+	}
+
+	var fallback *models.AlertPolicy
+	for i := range policies {
+		if policies[i].ID == domain.AlertPolicyID {
+			return &policies[i]
+		}
+		fallback = &policies[i]
+	}
+	return fallback
+}
+
 // recordNotification records notification in database
-func (s *NotifyService) recordNotification(domain *models.Domain, notifier Notifier, status string) {
-	db := database.GetDB()
+func (s *NotifyService) recordNotification(alert *Alert, notifier Notifier, status string) {
+	metrics.Notifications.Inc(notifier.Type(), status)
+
+	var domainID uint
+	if alert.Domain != nil {
+		domainID = alert.Domain.ID
+	}
+
+	event := alert.Kind
+	if event == "" {
+		event = AlertExpiry
+	}
 
-	notification := &models.Notification{
-		DomainID: domain.ID,
-		Type:     fmt.Sprintf("%T", notifier),
-		Content:  fmt.Sprintf("Domain %s expires in %d days", domain.Name, domain.DaysRemaining),
+	notifications := []models.Notification{{
+		DomainID: domainID,
+		Type:     notifier.Type(),
+		Event:    event,
+		Content:  alert.Summary(),
 		Status:   status,
 		SentAt:   time.Now(),
+	}}
+
+	// Digests are recorded once per domain so that each domain's history is complete
+	if len(alert.Digest) > 0 {
+		notifications = notifications[:0]
+		for _, item := range alert.Digest {
+			notifications = append(notifications, models.Notification{
+				DomainID: item.Domain.ID,
+				Type:     notifier.Type(),
+				Event:    AlertDigest,
+				Content:  fmt.Sprintf("Domain %s expires in %d days (digest)", item.Domain.Name, item.DaysRemaining),
+				Status:   status,
+				SentAt:   time.Now(),
+			})
+		}
 	}
 
-	db.Create(notification)
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Create(&notifications).Error
+	}); err != nil {
+		slog.Error("Failed to record notification", append(alert.logArgs(), "channel", notifier.Type(), "error", err)...)
+	}
 }
 
 // EmailNotifier sends email notifications
 type EmailNotifier struct {
-	config *config.EmailConfig
+	config      *config.EmailConfig
+	templateDir string
 }
 
-// NewEmailNotifier creates a new email notifier
-func NewEmailNotifier(cfg *config.EmailConfig) *EmailNotifier {
-	return &EmailNotifier{config: cfg}
+// NewEmailNotifier creates a new email notifier reading its template from templateDir
+func NewEmailNotifier(cfg *config.EmailConfig, templateDir string) *EmailNotifier {
+	if templateDir == "" {
+		templateDir = defaultTemplateDir
+	}
+	return &EmailNotifier{config: cfg, templateDir: templateDir}
+}
+
+// Type returns the channel identifier
+func (e *EmailNotifier) Type() string {
+	return "email"
 }
 
 // Send sends email notification
-func (e *EmailNotifier) Send(domain *models.Domain, daysRemaining int) error {
+func (e *EmailNotifier) Send(alert *Alert) error {
 	// Build email content
-	subject := fmt.Sprintf("域名到期提醒：%s 还有 %d 天到期", domain.Name, daysRemaining)
+	subject := alert.Subject()
 
-	var statusEmoji string
-	if daysRemaining <= 7 {
-		statusEmoji = "🔴 紧急"
-	} else if daysRemaining <= 30 {
-		statusEmoji = "🟡 警告"
-	} else {
-		statusEmoji = "🟢 正常"
-	}
+	m := alert.messages()
+	statusEmoji := severityEmoji(alert.Severity) + " " + m.SeverityLabel(alert.Severity)
 
-	body := fmt.Sprintf(`
-域名到期提醒
+	var body string
+	if alert.IsExpiry() {
+		body = e.expiryBody(alert, statusEmoji)
+	} else {
+		body = fmt.Sprintf(`
+%s
 
-状态：%s
-域名：%s
-剩余天数：%d 天
-到期日期：%s
-注册商：%s
-域名状态：%s
-最后检查：%s
+%s
+%s%s
 
-请及时续费以避免域名过期！
+%s
 `,
-		statusEmoji,
-		domain.Name,
-		daysRemaining,
-		domain.ExpiryDate.Format("2006-01-02"),
-		domain.Registrar,
-		domain.Status,
-		time.Now().Format("2006-01-02 15:04:05"),
-	)
+			alert.Title,
+			m.Field(m.Status, statusEmoji),
+			alert.domainLine("%s"+m.Colon+"%s\n"),
+			alert.Message,
+			m.Field(m.LastChecked, models.FormatDateTime(time.Now())),
+		)
+	}
+
+	contentType := "text/plain; charset=UTF-8"
+	if e.config.Format == EmailFormatHTML {
+		html, err := e.renderHTML(alert)
+		if err == nil {
+			contentType, body, err = multipartBody(body, html)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to build HTML email: %w", err)
+		}
+	}
 
 	// Build email message
 	message := fmt.Sprintf("From: %s\r\n", e.config.From)
 	message += fmt.Sprintf("To: %s\r\n", strings.Join(e.config.To, ","))
 	message += fmt.Sprintf("Subject: %s\r\n", subject)
-	message += "Content-Type: text/plain; charset=UTF-8\r\n"
+	message += "MIME-Version: 1.0\r\n"
+	message += fmt.Sprintf("Content-Type: %s\r\n", contentType)
 	message += "\r\n"
 	message += body
 
 	// SMTP authentication
 	auth := smtp.PlainAuth("", e.config.From, e.config.Password, e.config.SMTPHost)
 
-	// Send email
+	// Send email; port 465 requires implicit TLS, 587/25 use STARTTLS or plain
 	addr := fmt.Sprintf("%s:%d", e.config.SMTPHost, e.config.SMTPPort)
-	err := smtp.SendMail(addr, auth, e.config.From, e.config.To, []byte(message))
-	if err != nil {
+	if err := e.sendMail(addr, auth, []byte(message), e.config.UseSSL || e.config.SMTPPort == 465); err != nil {
 		// QQ mail and some other providers return "short response" error
 		// but the email is actually sent successfully. Ignore this specific error.
 		errMsg := err.Error()
 		if !strings.Contains(errMsg, "short response") {
 			return fmt.Errorf("failed to send email: %w", err)
 		}
-		fmt.Printf("[EMAIL] Email sent (ignoring 'short response' error from SMTP server)\n")
+		slog.Debug("Ignoring 'short response' error from SMTP server", "channel", "email")
 	}
 
-	fmt.Printf("[EMAIL] Successfully sent notification for domain %s to %v\n",
-		domain.Name, e.config.To)
+	slog.Debug("Email sent", "channel", "email", "subject", subject, "to", e.config.To)
 	return nil
 }
 
+// smtpTimeout bounds connecting to the SMTP server and the whole SMTP conversation
+const smtpTimeout = 30 * time.Second
+
+// smtpRootCAs verifies the SMTP server certificate; nil uses the system roots
+var smtpRootCAs *x509.CertPool
+
+// sendMail sends a message over implicit TLS (SMTPS), or a plain connection upgraded
+// with STARTTLS when the server supports it
+func (e *EmailNotifier) sendMail(addr string, auth smtp.Auth, msg []byte, implicitTLS bool) error {
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if implicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: e.config.SMTPHost, RootCAs: smtpRootCAs})
+		if err != nil {
+			return fmt.Errorf("TLS connection failed: %w", err)
+		}
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+		if err != nil {
+			return err
+		}
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, e.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !implicitTLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(&tls.Config{ServerName: e.config.SMTPHost, RootCAs: smtpRootCAs}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := client.Auth(auth); err != nil {
+		return err
+	}
+	if err := client.Mail(e.config.From); err != nil {
+		return err
+	}
+	for _, to := range e.config.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// expiryBody builds the body of an expiry reminder email
+func (e *EmailNotifier) expiryBody(alert *Alert, statusEmoji string) string {
+	domain, daysRemaining := alert.Domain, alert.DaysRemaining
+	if alert.Body != "" {
+		return alert.Body
+	}
+
+	m := alert.messages()
+	closing := m.ExpiryClosing
+	if alert.Message != "" {
+		closing = alert.Message
+	}
+
+	return fmt.Sprintf(`
+%s
+
+%s
+%s
+%s
+%s
+%s
+%s
+%s
+
+%s
+`,
+		m.ExpiryTitle,
+		m.Field(m.Status, statusEmoji),
+		m.Field(m.Domain, domain.Name),
+		m.Field(m.DaysRemaining, m.DaysText(daysRemaining)),
+		m.Field(m.ExpiryDate, models.FormatDate(models.TimeValue(domain.ExpiryDate))),
+		m.Field(m.Registrar, domain.Registrar),
+		m.Field(m.DomainStatus, domain.Status),
+		m.Field(m.LastChecked, models.FormatDateTime(time.Now())),
+		closing,
+	)
+}
+
 // WebhookNotifier sends webhook notifications
 type WebhookNotifier struct {
 	config *config.WebhookConfig
@@ -187,22 +726,70 @@ func NewWebhookNotifier(cfg *config.WebhookConfig) *WebhookNotifier {
 	return &WebhookNotifier{config: cfg}
 }
 
+// Type returns the channel identifier
+func (w *WebhookNotifier) Type() string {
+	return "webhook"
+}
+
 // Send sends webhook notification
-func (w *WebhookNotifier) Send(domain *models.Domain, daysRemaining int) error {
+func (w *WebhookNotifier) Send(alert *Alert) error {
 	payload := map[string]interface{}{
-		"domain":         domain.Name,
-		"days_remaining": daysRemaining,
-		"expiry_date":    domain.ExpiryDate.Format("2006-01-02"),
-		"registrar":      domain.Registrar,
-		"status":         domain.Status,
+		"event":    alert.Kind,
+		"title":    alert.Title,
+		"severity": alert.Severity,
+		"message":  alert.Message,
+	}
+	if domain := alert.Domain; domain != nil {
+		payload["domain"] = domain.Name
+		payload["days_remaining"] = alert.DaysRemaining
+		payload["expiry_date"] = models.FormatDate(models.TimeValue(domain.ExpiryDate))
+		payload["registrar"] = domain.Registrar
+		payload["status"] = domain.Status
+	}
+	if alert.Body != "" {
+		payload["body"] = alert.Body
+	}
+	if len(alert.Digest) > 0 {
+		domains := make([]map[string]interface{}, 0, len(alert.Digest))
+		for _, item := range alert.Digest {
+			domains = append(domains, map[string]interface{}{
+				"domain":         item.Domain.Name,
+				"days_remaining": item.DaysRemaining,
+				"expiry_date":    models.FormatDate(models.TimeValue(item.Domain.ExpiryDate)),
+				"severity":       item.Severity,
+			})
+		}
+		payload["domains"] = domains
 	}
 
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
+	if w.config.Template != "" {
+		if jsonData, err = w.render(alert); err != nil {
+			return err
+		}
+	}
+
+	method := w.config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
 
-	resp, err := http.Post(w.config.URL, "application/json", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequest(strings.ToUpper(method), w.config.URL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+	if w.config.Secret != "" {
+		req.Header.Set("X-Jiankong-Signature", webhookSignature(jsonData, w.config.Secret))
+	}
+
+	resp, err := notifyClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -215,6 +802,41 @@ func (w *WebhookNotifier) Send(domain *models.Domain, daysRemaining int) error {
 	return nil
 }
 
+// webhookSignature returns the hex encoded HMAC-SHA256 of the request body
+func webhookSignature(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// webhookFuncs are the extra functions available to webhook templates
+var webhookFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. "domain": {{json .Domain}}
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// render renders the configured webhook body template
+func (w *WebhookNotifier) render(alert *Alert) ([]byte, error) {
+	tmpl, err := template.New("webhook").Funcs(webhookFuncs).Parse(w.config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook template: %w", err)
+	}
+
+	data := alertData(alert)
+	if alert.Body != "" {
+		data.Message = alert.Body
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 // TelegramNotifier sends Telegram notifications
 type TelegramNotifier struct {
 	config *config.TelegramConfig
@@ -225,10 +847,32 @@ func NewTelegramNotifier(cfg *config.TelegramConfig) *TelegramNotifier {
 	return &TelegramNotifier{config: cfg}
 }
 
+// Type returns the channel identifier
+func (t *TelegramNotifier) Type() string {
+	return "telegram"
+}
+
 // Send sends Telegram notification
-func (t *TelegramNotifier) Send(domain *models.Domain, daysRemaining int) error {
-	message := fmt.Sprintf("⚠️ 域名到期提醒\n\nDomain: %s\n剩余天数: %d\n到期日: %s\n注册商: %s",
-		domain.Name, daysRemaining, domain.ExpiryDate.Format("2006-01-02"), domain.Registrar)
+func (t *TelegramNotifier) Send(alert *Alert) error {
+	m := alert.messages()
+	var message string
+	if alert.IsExpiry() {
+		domain := alert.Domain
+		message = fmt.Sprintf("⚠️ %s\n\n%s: %s\n%s: %d\n%s: %s\n%s: %s",
+			m.ExpiryTitle,
+			m.Domain, domain.Name,
+			m.DaysRemaining, alert.DaysRemaining,
+			m.ExpiryDate, models.FormatDate(models.TimeValue(domain.ExpiryDate)),
+			m.Registrar, domain.Registrar)
+		if alert.Message != "" {
+			message += "\n\n" + alert.Message
+		}
+		if alert.Body != "" {
+			message = alert.Body
+		}
+	} else {
+		message = fmt.Sprintf("⚠️ %s\n\n%s%s", alert.Title, alert.domainLine("%s: %s\n\n"), alert.Message)
+	}
 
 	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", t.config.BotToken)
 
@@ -244,13 +888,13 @@ func (t *TelegramNotifier) Send(domain *models.Domain, daysRemaining int) error
 
 	// Create HTTP client with SOCKS5 proxy support
 	client := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: notifyHTTPTimeout,
 	}
 
 	// Use SOCKS5 proxy (socks5://127.0.0.1:7890)
 	dialer, err := proxy.SOCKS5("tcp", "127.0.0.1:7890", nil, proxy.Direct)
 	if err != nil {
-		fmt.Printf("[TELEGRAM] Failed to create SOCKS5 proxy: %v\n", err)
+		slog.Warn("Failed to create SOCKS5 proxy", "channel", "telegram", "error", err)
 	} else {
 		transport := &http.Transport{
 			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
@@ -258,7 +902,7 @@ func (t *TelegramNotifier) Send(domain *models.Domain, daysRemaining int) error
 			},
 		}
 		client.Transport = transport
-		fmt.Printf("[TELEGRAM] Using SOCKS5 proxy: 127.0.0.1:7890\n")
+		slog.Debug("Using SOCKS5 proxy", "channel", "telegram", "proxy", "127.0.0.1:7890")
 	}
 
 	resp, err := client.Post(apiURL, "application/json", bytes.NewBuffer(jsonData))
@@ -284,37 +928,57 @@ func NewDingDingNotifier(cfg *config.DingDingConfig) *DingDingNotifier {
 	return &DingDingNotifier{config: cfg}
 }
 
+// Type returns the channel identifier
+func (d *DingDingNotifier) Type() string {
+	return "dingding"
+}
+
 // Send sends DingTalk notification
-func (d *DingDingNotifier) Send(domain *models.Domain, daysRemaining int) error {
+func (d *DingDingNotifier) Send(alert *Alert) error {
 	// 构建消息文本
-	var statusEmoji string
-	if daysRemaining <= 7 {
-		statusEmoji = "🔴"
-	} else if daysRemaining <= 30 {
-		statusEmoji = "🟡"
+	statusEmoji := severityEmoji(alert.Severity)
+	m := alert.messages()
+
+	var title, message string
+	if alert.IsExpiry() {
+		domain, daysRemaining := alert.Domain, alert.DaysRemaining
+		title = m.ExpiryTitle
+		message = fmt.Sprintf("## %s %s\n\n"+
+			"**%s**: %s\n\n"+
+			"**%s**: %s\n\n"+
+			"**%s**: %s\n\n"+
+			"**%s**: %s\n\n"+
+			"**%s**: %s",
+			statusEmoji, m.ExpiryTitle,
+			m.Domain, domain.Name,
+			m.DaysRemaining, m.DaysText(daysRemaining),
+			m.ExpiryDate, models.FormatDate(models.TimeValue(domain.ExpiryDate)),
+			m.Registrar, domain.Registrar,
+			m.Status, domain.Status,
+		)
+		if alert.Message != "" {
+			message += "\n\n> " + alert.Message
+		}
+		if alert.Body != "" {
+			message = alert.Body
+		}
 	} else {
-		statusEmoji = "🟢"
-	}
-
-	message := fmt.Sprintf("## %s 域名到期提醒\n\n"+
-		"**域名**: %s\n\n"+
-		"**剩余天数**: %d 天\n\n"+
-		"**到期日期**: %s\n\n"+
-		"**注册商**: %s\n\n"+
-		"**状态**: %s",
-		statusEmoji,
-		domain.Name,
-		daysRemaining,
-		domain.ExpiryDate.Format("2006-01-02"),
-		domain.Registrar,
-		domain.Status,
-	)
+		title = alert.Title
+		message = fmt.Sprintf("## %s %s\n\n"+
+			"%s"+
+			"%s",
+			statusEmoji,
+			alert.Title,
+			alert.domainLine("**%s**: %s\n\n"),
+			alert.markdownMessage(),
+		)
+	}
 
 	// 构建请求体
 	payload := map[string]interface{}{
 		"msgtype": "markdown",
 		"markdown": map[string]interface{}{
-			"title": "域名到期提醒",
+			"title": title,
 			"text":  message,
 		},
 	}
@@ -324,7 +988,42 @@ func (d *DingDingNotifier) Send(domain *models.Domain, daysRemaining int) error
 		return err
 	}
 
-	// 构建 URL（带签名）
+	maxRetries := d.config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+	delay, err := time.ParseDuration(d.config.RetryDelay)
+	if err != nil || delay <= 0 {
+		delay = 3 * time.Second
+	}
+
+	// 钉钉机器人限流（每分钟 20 条），限流和临时错误时重试；每次重试都重新生成签名
+	for attempt := 0; ; attempt++ {
+		retryable, err := d.post(jsonData)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= maxRetries {
+			// Already retried here, so the notify service doesn't retry again
+			return permanent(err)
+		}
+
+		slog.Warn("Notification failed, retrying", "channel", "dingding", "attempt", attempt+1, "delay", delay, "error", err)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// dingDingRetryCodes are DingTalk error codes worth retrying (rate limited / busy)
+var dingDingRetryCodes = map[int]bool{
+	-1:     true, // 系统繁忙
+	130101: true, // 发送速度太快而限流
+	410100: true, // 发送速度太快而限流
+}
+
+// post sends one request with a freshly signed URL and reports whether a failure is retryable
+func (d *DingDingNotifier) post(jsonData []byte) (bool, error) {
+	// 构建 URL（带签名），签名包含时间戳，必须在每次请求时重新生成
 	webhookURL := d.config.Webhook
 
 	// 如果配置了加签密钥，添加签名
@@ -334,36 +1033,42 @@ func (d *DingDingNotifier) Send(domain *models.Domain, daysRemaining int) error
 
 		parsedURL, err := url.Parse(webhookURL)
 		if err != nil {
-			return fmt.Errorf("invalid webhook URL: %w", err)
+			return false, fmt.Errorf("invalid webhook URL: %w", err)
 		}
 
 		query := parsedURL.Query()
-		query.Add("timestamp", timestamp)
-		query.Add("sign", sign)
+		query.Set("timestamp", timestamp)
+		query.Set("sign", sign)
 		parsedURL.RawQuery = query.Encode()
 		webhookURL = parsedURL.String()
 	}
 
 	// 发送请求
-	resp, err := http.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
+	resp, err := notifyClient.Post(webhookURL, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return err
+		// The webhook URL carries the access token and signature, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return true, fmt.Errorf("dingding request failed: %w", urlErr.Err)
+		}
+		return true, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("dingding webhook returned status %d", resp.StatusCode)
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("dingding webhook returned status %d", resp.StatusCode)
 	}
 
 	// 检查响应
 	var result map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
 		if errCode, ok := result["errcode"].(float64); ok && errCode != 0 {
-			return fmt.Errorf("dingding API error: %v", result["errmsg"])
+			return dingDingRetryCodes[int(errCode)], fmt.Errorf("dingding API error %d: %v", int(errCode), result["errmsg"])
 		}
 	}
 
-	return nil
+	return false, nil
 }
 
 // generateSign 生成钉钉签名
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// barkPayload is the JSON body of a Bark push
+type barkPayload struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	Level string `json:"level"`
+	Group string `json:"group"`
+	Sound string `json:"sound"`
+}
+
+func TestBarkSend(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, `{"code":200,"message":"success"}`)
+	notifier := NewBarkNotifier(&config.BarkConfig{
+		ServerURL: server.URL + "/",
+		DeviceKey: "device/key",
+		Group:     "domains",
+		Sound:     "alarm",
+	})
+
+	if err := notifier.Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := server.last()
+	if req.Method != http.MethodPost {
+		t.Errorf("method = %s, want POST", req.Method)
+	}
+	// The device key is a single path segment, even with a slash in it
+	if req.Path != "/device%2Fkey" {
+		t.Errorf("path = %q, want /device%%2Fkey", req.Path)
+	}
+	if ct := req.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Content-Type = %q, want JSON", ct)
+	}
+
+	var payload barkPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("decode %q: %v", req.Body, err)
+	}
+	title, body := pushMessage(testExpiryAlert())
+	if payload.Title != title || payload.Body != body {
+		t.Errorf("title, body = %q, %q; want %q, %q", payload.Title, payload.Body, title, body)
+	}
+	if !strings.Contains(payload.Body, "example.com") {
+		t.Errorf("body %q doesn't name the domain", payload.Body)
+	}
+	if payload.Group != "domains" || payload.Sound != "alarm" {
+		t.Errorf("group, sound = %q, %q; want domains, alarm", payload.Group, payload.Sound)
+	}
+	if payload.Level != "active" {
+		t.Errorf("level = %q, want active for a warning", payload.Level)
+	}
+}
+
+func TestBarkSendDefaults(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, `{"code":200,"message":"success"}`)
+	alert := testExpiryAlert()
+	alert.DaysRemaining = 3
+	alert.Severity = SeverityCritical
+
+	if err := NewBarkNotifier(&config.BarkConfig{ServerURL: server.URL, DeviceKey: "key"}).Send(alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := server.last()
+	if req.Path != "/key" {
+		t.Errorf("path = %q, want /key", req.Path)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("decode %q: %v", req.Body, err)
+	}
+	if payload["level"] != "timeSensitive" {
+		t.Errorf("level = %v, want timeSensitive for a critical alert", payload["level"])
+	}
+	for _, key := range []string{"group", "sound"} {
+		if _, ok := payload[key]; ok {
+			t.Errorf("%s sent although not configured", key)
+		}
+	}
+}
+
+func TestBarkPushURL(t *testing.T) {
+	tests := []struct {
+		server string
+		want   string
+	}{
+		{"", defaultBarkServer + "/key"},
+		{"https://bark.example.com", "https://bark.example.com/key"},
+		{"https://bark.example.com/", "https://bark.example.com/key"},
+	}
+	for _, tt := range tests {
+		notifier := NewBarkNotifier(&config.BarkConfig{ServerURL: tt.server, DeviceKey: "key"})
+		if got := notifier.pushURL(); got != tt.want {
+			t.Errorf("pushURL() with server %q = %q, want %q", tt.server, got, tt.want)
+		}
+	}
+}
+
+func TestBarkSendErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantErr       string
+		wantPermanent bool
+	}{
+		{"unknown device", http.StatusBadRequest, `{"code":400,"message":"failed to get device token"}`, "failed to get device token", true},
+		{"rate limited", http.StatusTooManyRequests, ``, "status 429", false},
+		{"server error", http.StatusInternalServerError, ``, "status 500", false},
+		{"API error", http.StatusOK, `{"code":500,"message":"push failed"}`, "push failed", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t, tt.status, tt.body)
+			err := NewBarkNotifier(&config.BarkConfig{ServerURL: server.URL, DeviceKey: "secret-key"}).Send(testExpiryAlert())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			var perm *permanentError
+			if got := errors.As(err, &perm); got != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+}
+
+func TestBarkErrorHidesDeviceKey(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, ``)
+	server.Close()
+
+	err := NewBarkNotifier(&config.BarkConfig{ServerURL: server.URL, DeviceKey: "secret-key"}).Send(testExpiryAlert())
+	if err == nil {
+		t.Fatal("Send() succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error %q reveals the device key", err)
+	}
+}
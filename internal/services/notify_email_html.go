@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bytes"
+	"domain-monitor/internal/models"
+	"fmt"
+	"html/template"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"time"
+)
+
+// Email formats
+const (
+	EmailFormatText = "text"
+	EmailFormatHTML = "html"
+)
+
+// defaultTemplateDir holds the email templates when notifications.template_dir is unset
+const defaultTemplateDir = "templates"
+
+// defaultEmailTemplate is the built-in HTML email, overridable via email.template
+const defaultEmailTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="UTF-8"><title>{{.Title}}</title></head>
+<body style="margin:0;padding:24px;background:#f4f5f7;font-family:-apple-system,'Segoe UI','PingFang SC','Microsoft YaHei',sans-serif;color:#333;">
+<table width="100%" cellpadding="0" cellspacing="0" style="max-width:600px;margin:0 auto;background:#fff;border-radius:6px;overflow:hidden;">
+  <tr><td style="background:{{.Color}};color:#fff;padding:16px 24px;font-size:18px;font-weight:bold;">{{.Emoji}} {{.Title}} · {{.Label}}</td></tr>
+  <tr><td style="padding:24px;">
+    {{if .Domain}}
+    <table width="100%" cellpadding="8" cellspacing="0" style="border-collapse:collapse;font-size:14px;">
+      <tr><td style="border-bottom:1px solid #eee;color:#888;width:30%;">{{.Text.Domain}}</td><td style="border-bottom:1px solid #eee;font-weight:bold;">{{.Domain}}</td></tr>
+      {{if .IsExpiry}}
+      <tr><td style="border-bottom:1px solid #eee;color:#888;">{{.Text.DaysRemaining}}</td><td style="border-bottom:1px solid #eee;color:{{.Color}};font-weight:bold;">{{.Text.DaysText .DaysRemaining}}</td></tr>
+      <tr><td style="border-bottom:1px solid #eee;color:#888;">{{.Text.ExpiryDate}}</td><td style="border-bottom:1px solid #eee;">{{.ExpiryDate}}</td></tr>
+      <tr><td style="border-bottom:1px solid #eee;color:#888;">{{.Text.Registrar}}</td><td style="border-bottom:1px solid #eee;">{{.Registrar}}</td></tr>
+      <tr><td style="border-bottom:1px solid #eee;color:#888;">{{.Text.DomainStatus}}</td><td style="border-bottom:1px solid #eee;">{{.Status}}</td></tr>
+      {{end}}
+    </table>
+    {{end}}
+    {{if .Items}}
+    <table width="100%" cellpadding="8" cellspacing="0" style="border-collapse:collapse;font-size:14px;">
+      <tr style="background:#fafafa;color:#888;"><td>{{.Text.Domain}}</td><td>{{.Text.DaysRemaining}}</td><td>{{.Text.ExpiryDate}}</td></tr>
+      {{range .Items}}
+      <tr><td style="border-bottom:1px solid #eee;font-weight:bold;">{{.Domain}}</td><td style="border-bottom:1px solid #eee;color:{{.Color}};font-weight:bold;">{{$.Text.DaysText .DaysRemaining}}</td><td style="border-bottom:1px solid #eee;">{{.ExpiryDate}}</td></tr>
+      {{end}}
+    </table>
+    {{else if .Message}}<p style="font-size:14px;line-height:1.6;white-space:pre-wrap;">{{.Message}}</p>{{end}}
+    <p style="font-size:12px;color:#999;margin-top:24px;">{{.Text.Field .Text.LastChecked .CheckedAt}}</p>
+  </td></tr>
+</table>
+</body>
+</html>
+`
+
+// emailHTMLData is the data available to HTML email templates
+type emailHTMLData struct {
+	Text          *Messages // Localized labels, e.g. {{.Text.Domain}}
+	Title         string
+	Severity      string
+	Label         string // Localized severity label
+	Emoji         string
+	Color         string // Banner color for the severity
+	IsExpiry      bool
+	Domain        string
+	DaysRemaining int
+	ExpiryDate    string
+	Registrar     string
+	Status        string
+	Message       string
+	CheckedAt     string
+	Items         []emailHTMLItem // Domains of a digest
+}
+
+// emailHTMLItem is one row of a digest email
+type emailHTMLItem struct {
+	Domain        string
+	DaysRemaining int
+	ExpiryDate    string
+	Color         string
+}
+
+// emailColor maps alert severity to the banner color
+func emailColor(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "#d93025"
+	case SeverityWarning:
+		return "#f29900"
+	default:
+		return "#188038"
+	}
+}
+
+// renderHTML renders the HTML part of an email, using the configured template file if set
+func (e *EmailNotifier) renderHTML(alert *Alert) (string, error) {
+	text := defaultEmailTemplate
+	if e.config.Template != "" {
+		content, err := e.readTemplate()
+		if err != nil {
+			return "", fmt.Errorf("failed to read email template: %w", err)
+		}
+		text = string(content)
+	}
+
+	tmpl, err := template.New("email").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid email template: %w", err)
+	}
+
+	m := alert.messages()
+	data := emailHTMLData{
+		Text:      m,
+		Title:     alert.Title,
+		Severity:  alert.Severity,
+		Label:     m.SeverityLabel(alert.Severity),
+		Emoji:     severityEmoji(alert.Severity),
+		Color:     emailColor(alert.Severity),
+		IsExpiry:  alert.IsExpiry(),
+		Message:   alert.Message,
+		CheckedAt: models.FormatDateTime(time.Now()),
+	}
+	if alert.IsExpiry() {
+		data.Title = m.ExpiryTitle
+	}
+	if alert.Body != "" {
+		data.Message = alert.Body
+	}
+	if alert.Domain != nil {
+		data.Domain = alert.Domain.Name
+		data.DaysRemaining = alert.DaysRemaining
+		data.ExpiryDate = models.FormatDate(models.TimeValue(alert.Domain.ExpiryDate))
+		data.Registrar = alert.Domain.Registrar
+		data.Status = alert.Domain.Status
+	}
+
+	for _, item := range alert.Digest {
+		data.Items = append(data.Items, emailHTMLItem{
+			Domain:        item.Domain.Name,
+			DaysRemaining: item.DaysRemaining,
+			ExpiryDate:    models.FormatDate(models.TimeValue(item.Domain.ExpiryDate)),
+			Color:         emailColor(item.Severity),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// multipartBody builds a multipart/alternative body with plain-text and HTML parts.
+// It returns the Content-Type header value and the encoded body.
+func multipartBody(text, html string) (string, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", text},
+		{"text/html; charset=UTF-8", html},
+	} {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType)
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return "", "", err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return "", "", err
+		}
+		if err := qp.Close(); err != nil {
+			return "", "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+
+	return "multipart/alternative; boundary=" + writer.Boundary(), buf.String(), nil
+}
+
+// readTemplate reads the template file, which must not escape the template directory
+func (e *EmailNotifier) readTemplate() ([]byte, error) {
+	root, err := os.OpenRoot(e.templateDir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+	return root.ReadFile(e.config.Template)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderHTMLTemplateFromDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "alert.html"), []byte("<p>{{.Domain}} {{.DaysRemaining}}</p>"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewEmailNotifier(&config.EmailConfig{Template: "alert.html"}, dir)
+	html, err := e.renderHTML(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7})
+	if err != nil {
+		t.Fatalf("renderHTML: %v", err)
+	}
+	if html != "<p>example.com 7</p>" {
+		t.Errorf("renderHTML() = %q", html)
+	}
+}
+
+func TestRenderHTMLTemplateOutsideDir(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "templates")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(parent, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"../secret.txt", secret} {
+		e := NewEmailNotifier(&config.EmailConfig{Template: name}, dir)
+		html, err := e.renderHTML(&Alert{Domain: &models.Domain{Name: "example.com"}})
+		if err == nil || strings.Contains(html, "secret") {
+			t.Errorf("template %q: renderHTML() = %q, %v; want an error", name, html, err)
+		}
+	}
+}
+
+func TestEmailSendHTMLMultipart(t *testing.T) {
+	stub := newSMTPStub(t)
+	smtpRootCAs = stub.roots
+	t.Cleanup(func() { smtpRootCAs = nil })
+
+	cfg := stub.config()
+	cfg.Format = EmailFormatHTML
+	alert := &Alert{Domain: &models.Domain{Name: "example.com", Registrar: "Example Registrar"}, DaysRemaining: 7, Severity: SeverityWarning}
+	if err := NewEmailNotifier(cfg, "").Send(alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := stub.received()
+	if len(messages) != 1 {
+		t.Fatalf("stub received %d messages, want 1", len(messages))
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextRawPart: %v", err)
+		}
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if encoding := part.Header.Get("Content-Transfer-Encoding"); encoding != "quoted-printable" {
+			t.Errorf("%s part encoding = %q", contentType, encoding)
+		}
+		content, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("decode %s part: %v", contentType, err)
+		}
+		parts[contentType] = string(content)
+	}
+
+	if len(parts) != 2 {
+		t.Fatalf("message has parts %v, want text/plain and text/html", parts)
+	}
+	if text := parts["text/plain"]; !strings.Contains(text, "example.com") || strings.Contains(text, "<") {
+		t.Errorf("text/plain part = %q", text)
+	}
+	if html := parts["text/html"]; !strings.Contains(html, "<table") || !strings.Contains(html, "Example Registrar") {
+		t.Errorf("text/html part = %q", html)
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpStub is an SMTP server on implicit TLS that accepts every message
+type smtpStub struct {
+	listener net.Listener
+	roots    *x509.CertPool
+
+	mu       sync.Mutex
+	auth     string // Decoded AUTH PLAIN credentials
+	from     string
+	rcpt     []string
+	messages []string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	// httptest provides a certificate for 127.0.0.1 and the pool that trusts it
+	certServer := httptest.NewTLSServer(nil)
+	cert := certServer.TLS.Certificates[0]
+	roots := x509.NewCertPool()
+	roots.AddCert(certServer.Certificate())
+	certServer.Close()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpStub{listener: listener, roots: roots}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStub) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250-stub")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			_, credentials, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(credentials)
+			s.mu.Lock()
+			s.auth = string(decoded)
+			s.mu.Unlock()
+			reply("235 Authentication successful")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = arg
+			s.mu.Unlock()
+			reply("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpt = append(s.rcpt, arg)
+			s.mu.Unlock()
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+// config returns an email config sending to the stub over implicit TLS
+func (s *smtpStub) config() *config.EmailConfig {
+	return &config.EmailConfig{
+		SMTPHost: "127.0.0.1",
+		SMTPPort: s.listener.Addr().(*net.TCPAddr).Port,
+		UseSSL:   true,
+		From:     "monitor@example.com",
+		Password: "app-password",
+		To:       []string{"ops@example.com", "admin@example.com"},
+	}
+}
+
+// received returns the messages accepted so far
+func (s *smtpStub) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func TestEmailSendImplicitTLS(t *testing.T) {
+	stub := newSMTPStub(t)
+	smtpRootCAs = stub.roots
+	t.Cleanup(func() { smtpRootCAs = nil })
+
+	e := NewEmailNotifier(stub.config(), "")
+	if err := e.Send(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	messages := stub.received()
+	if len(messages) != 1 {
+		t.Fatalf("stub received %d messages, want 1", len(messages))
+	}
+	if !strings.Contains(messages[0], "To: ops@example.com,admin@example.com") || !strings.Contains(messages[0], "example.com") {
+		t.Errorf("message = %q", messages[0])
+	}
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+	if stub.auth != "\x00monitor@example.com\x00app-password" {
+		t.Errorf("AUTH PLAIN credentials = %q", stub.auth)
+	}
+	if stub.from != "FROM:<monitor@example.com>" || len(stub.rcpt) != 2 {
+		t.Errorf("envelope = %q -> %q", stub.from, stub.rcpt)
+	}
+}
+
+func TestEmailSendImplicitTLSVerifiesCertificate(t *testing.T) {
+	stub := newSMTPStub(t)
+
+	// The stub's self-signed certificate is not in the system roots
+	e := NewEmailNotifier(stub.config(), "")
+	err := e.Send(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7})
+	if err == nil || !strings.Contains(err.Error(), "TLS connection failed") {
+		t.Errorf("Send() error = %v, want a certificate verification failure", err)
+	}
+	if got := len(stub.received()); got != 0 {
+		t.Errorf("stub received %d messages over an unverified connection", got)
+	}
+}
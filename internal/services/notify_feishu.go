@@ -0,0 +1,128 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// FeishuNotifier sends Feishu/Lark bot notifications
+type FeishuNotifier struct {
+	config *config.FeishuConfig
+}
+
+// NewFeishuNotifier creates a new Feishu notifier
+func NewFeishuNotifier(cfg *config.FeishuConfig) *FeishuNotifier {
+	return &FeishuNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (f *FeishuNotifier) Type() string {
+	return "feishu"
+}
+
+// Send sends a Feishu interactive card message
+func (f *FeishuNotifier) Send(alert *Alert) error {
+	m := alert.messages()
+	var title, content string
+	if alert.IsExpiry() {
+		domain := alert.Domain
+		title = fmt.Sprintf("%s %s", severityEmoji(alert.Severity), m.ExpiryTitle)
+		content = fmt.Sprintf("**%s**%s%s\n**%s**%s%s\n**%s**%s%s\n**%s**%s%s\n**%s**%s%s",
+			m.Domain, m.Colon, domain.Name,
+			m.DaysRemaining, m.Colon, m.DaysText(alert.DaysRemaining),
+			m.ExpiryDate, m.Colon, models.FormatDate(models.TimeValue(domain.ExpiryDate)),
+			m.Registrar, m.Colon, domain.Registrar,
+			m.Status, m.Colon, domain.Status,
+		)
+		if alert.Message != "" {
+			content += "\n\n" + alert.Message
+		}
+		if alert.Body != "" {
+			content = alert.Body
+		}
+	} else {
+		title = fmt.Sprintf("%s %s", severityEmoji(alert.Severity), alert.Title)
+		content = alert.domainLine("**%s**"+m.Colon+"%s\n\n") + alert.Message
+	}
+
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title":    map[string]interface{}{"tag": "plain_text", "content": title},
+				"template": feishuColor(alert.Severity),
+			},
+			"elements": []interface{}{
+				map[string]interface{}{"tag": "markdown", "content": content},
+			},
+		},
+	}
+
+	// 飞书的签名放在请求体中（钉钉放在 URL 参数中），时间戳单位为秒
+	if f.config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = timestamp
+		payload["sign"] = feishuSign(timestamp, f.config.Secret)
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifyClient.Post(f.config.Webhook, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		// The webhook URL carries the bot token, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("feishu request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("feishu webhook returned status %d", resp.StatusCode)
+	}
+
+	// 检查响应
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil && result.Code != 0 {
+		return fmt.Errorf("feishu API error %d: %s", result.Code, result.Msg)
+	}
+
+	return nil
+}
+
+// feishuSign 生成飞书签名：以 "timestamp\nsecret" 为密钥对空字符串做 HmacSHA256 后 Base64 编码
+func feishuSign(timestamp, secret string) string {
+	stringToSign := fmt.Sprintf("%s\n%s", timestamp, secret)
+	h := hmac.New(sha256.New, []byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// feishuColor returns the card header color for a severity
+func feishuColor(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "red"
+	case SeverityWarning:
+		return "orange"
+	default:
+		return "blue"
+	}
+}
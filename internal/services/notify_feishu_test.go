@@ -0,0 +1,91 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeishuSign(t *testing.T) {
+	// HmacSHA256 keyed with "1599360473\nsecret-key" over an empty message
+	if got, want := feishuSign("1599360473", "secret-key"), "kB5cakiMTl54eaM5Mc9EATiEHFb8tiS242Jpmtx7X2o="; got != want {
+		t.Errorf("feishuSign() = %q, want %q", got, want)
+	}
+}
+
+func TestFeishuSendSigned(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer server.Close()
+
+	f := NewFeishuNotifier(&config.FeishuConfig{Webhook: server.URL, Secret: "secret-key"})
+	if err := f.Send(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	timestamp, _ := payload["timestamp"].(string)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(seconds, 0)).Abs() > time.Minute {
+		t.Fatalf("timestamp = %q, want the current Unix time in seconds", timestamp)
+	}
+	if payload["sign"] != feishuSign(timestamp, "secret-key") {
+		t.Errorf("sign = %v, want the signature of the timestamp", payload["sign"])
+	}
+	if payload["msg_type"] != "interactive" {
+		t.Errorf("msg_type = %v", payload["msg_type"])
+	}
+}
+
+func TestFeishuSendUnsigned(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	f := NewFeishuNotifier(&config.FeishuConfig{Webhook: server.URL})
+	if err := f.Send(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, ok := payload["sign"]; ok {
+		t.Error("payload is signed without a secret")
+	}
+}
+
+func TestFeishuSendAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+	}))
+	defer server.Close()
+
+	f := NewFeishuNotifier(&config.FeishuConfig{Webhook: server.URL, Secret: "wrong"})
+	err := f.Send(&Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 7})
+	if err == nil || !strings.Contains(err.Error(), "19021") {
+		t.Errorf("Send() error = %v, want the API error code", err)
+	}
+}
+
+func TestFeishuErrorHidesWebhookToken(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, ``)
+	server.Close()
+
+	err := NewFeishuNotifier(&config.FeishuConfig{Webhook: server.URL + "/open-apis/bot/v2/hook/secret-token"}).Send(testExpiryAlert())
+	if err == nil {
+		t.Fatal("Send() succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error %q reveals the webhook token", err)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"domain-monitor/internal/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Minimum Gotify priorities of warning and critical alerts; clients show 8 and above
+// as high priority notifications
+const (
+	gotifyWarningPriority  = 5
+	gotifyCriticalPriority = 8
+)
+
+// GotifyNotifier sends notifications to a Gotify server
+type GotifyNotifier struct {
+	config *config.GotifyConfig
+}
+
+// NewGotifyNotifier creates a new Gotify notifier
+func NewGotifyNotifier(cfg *config.GotifyConfig) *GotifyNotifier {
+	return &GotifyNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (g *GotifyNotifier) Type() string {
+	return "gotify"
+}
+
+// Send posts the alert as a Gotify message
+func (g *GotifyNotifier) Send(alert *Alert) error {
+	title, message := pushMessage(alert)
+	payload := map[string]interface{}{
+		"title":    title,
+		"message":  message,
+		"priority": g.priority(alert.Severity),
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimSuffix(g.config.ServerURL, "/") + "/message?token=" + url.QueryEscape(g.config.AppToken)
+	resp, err := notifyClient.Post(endpoint, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		// The request URL carries the token, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("gotify request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var result struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"errorDescription"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		err := fmt.Errorf("gotify server returned status %d: %s", resp.StatusCode, strings.TrimSpace(result.Error+" "+result.ErrorDescription))
+		// An invalid token or message won't be accepted by retrying
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// priority returns the Gotify priority of a severity, raising the configured priority
+// for warnings and urgent alerts
+func (g *GotifyNotifier) priority(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return max(g.config.Priority, gotifyCriticalPriority)
+	case SeverityWarning:
+		return max(g.config.Priority, gotifyWarningPriority)
+	default:
+		return g.config.Priority
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// gotifyPayload is the JSON body of a Gotify message
+type gotifyPayload struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+func TestGotifySend(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, `{"id":1}`)
+	notifier := NewGotifyNotifier(&config.GotifyConfig{ServerURL: server.URL + "/", AppToken: "app&token", Priority: 2})
+
+	if err := notifier.Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := server.last()
+	if req.Method != http.MethodPost || req.Path != "/message" {
+		t.Errorf("request = %s %s, want POST /message", req.Method, req.Path)
+	}
+	if got := req.Query.Get("token"); got != "app&token" {
+		t.Errorf("token = %q, want app&token", got)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var payload gotifyPayload
+	if err := json.Unmarshal(req.Body, &payload); err != nil {
+		t.Fatalf("decode %q: %v", req.Body, err)
+	}
+	title, message := pushMessage(testExpiryAlert())
+	if payload.Title != title || payload.Message != message {
+		t.Errorf("title, message = %q, %q; want %q, %q", payload.Title, payload.Message, title, message)
+	}
+	// A warning raises the configured priority to the warning minimum
+	if payload.Priority != gotifyWarningPriority {
+		t.Errorf("priority = %d, want %d", payload.Priority, gotifyWarningPriority)
+	}
+}
+
+func TestGotifyPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		days       int
+		configured int
+		want       int
+	}{
+		{"far off uses the configured priority", 60, 3, 3},
+		{"within 30 days is a warning", 20, 0, gotifyWarningPriority},
+		{"within 7 days is critical", 7, 0, gotifyCriticalPriority},
+		{"expired is critical", -1, 0, gotifyCriticalPriority},
+		{"configured priority above the warning minimum", 20, 6, 6},
+		{"configured priority above the critical minimum", 3, 10, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t, http.StatusOK, `{}`)
+			alert := testExpiryAlert()
+			alert.DaysRemaining = tt.days
+			alert.Severity = severityForDays(tt.days)
+
+			if err := NewGotifyNotifier(&config.GotifyConfig{ServerURL: server.URL, AppToken: "t", Priority: tt.configured}).Send(alert); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			var payload gotifyPayload
+			if err := json.Unmarshal(server.last().Body, &payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if payload.Priority != tt.want {
+				t.Errorf("priority = %d, want %d", payload.Priority, tt.want)
+			}
+		})
+	}
+}
+
+func TestGotifySendErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantErr       string
+		wantPermanent bool
+	}{
+		{"invalid token", http.StatusUnauthorized, `{"error":"Unauthorized","errorCode":401,"errorDescription":"you need to provide a valid access token"}`, "valid access token", true},
+		{"server error", http.StatusInternalServerError, ``, "status 500", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t, tt.status, tt.body)
+			err := NewGotifyNotifier(&config.GotifyConfig{ServerURL: server.URL, AppToken: "secret-token"}).Send(testExpiryAlert())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			if strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("error %q reveals the token", err)
+			}
+			var perm *permanentError
+			if got := errors.As(err, &perm); got != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// pushoverAPI is the Pushover message endpoint
+var pushoverAPI = "https://api.pushover.net/1/messages.json"
+
+// Pushover limits and emergency priority parameters
+const (
+	pushoverMaxTitle      = 250
+	pushoverMaxMessage    = 1024
+	pushoverEmergency     = 2
+	pushoverRetrySeconds  = 300  // Repeat an unacknowledged emergency alert every 5 minutes
+	pushoverExpireSeconds = 3600 // for up to an hour
+)
+
+// PushoverNotifier sends Pushover notifications
+type PushoverNotifier struct {
+	config *config.PushoverConfig
+}
+
+// NewPushoverNotifier creates a new Pushover notifier
+func NewPushoverNotifier(cfg *config.PushoverConfig) *PushoverNotifier {
+	return &PushoverNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (p *PushoverNotifier) Type() string {
+	return "pushover"
+}
+
+// Send posts the alert; critical alerts are sent as emergency messages that repeat
+// until they are acknowledged
+func (p *PushoverNotifier) Send(alert *Alert) error {
+	title, message := pushMessage(alert)
+	form := url.Values{
+		"token":   {p.config.Token},
+		"user":    {p.config.User},
+		"title":   {truncateRunes(title, pushoverMaxTitle)},
+		"message": {truncateRunes(message, pushoverMaxMessage)},
+	}
+
+	priority := p.priority(alert.Severity)
+	form.Set("priority", strconv.Itoa(priority))
+	if priority == pushoverEmergency {
+		form.Set("retry", strconv.Itoa(pushoverRetrySeconds))
+		form.Set("expire", strconv.Itoa(pushoverExpireSeconds))
+	}
+
+	resp, err := notifyClient.PostForm(pushoverAPI, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Status int      `json:"status"`
+		Errors []string `json:"errors"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+
+	if result.Status != 1 || resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("pushover API returned status %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+		// Pushover asks clients not to retry rejected requests (invalid token, user or parameters)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// priority returns the Pushover priority of a severity: emergency for critical alerts,
+// the configured priority otherwise
+func (p *PushoverNotifier) priority(severity string) int {
+	if severity == SeverityCritical {
+		return pushoverEmergency
+	}
+	return p.config.Priority
+}
+
+// truncateRunes shortens s to at most n characters
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
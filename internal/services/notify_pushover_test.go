@@ -0,0 +1,137 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// usePushoverServer points the Pushover notifier at a test server for the test
+func usePushoverServer(t *testing.T, status int, body string) *captureServer {
+	t.Helper()
+	server := newCaptureServer(t, status, body)
+	prev := pushoverAPI
+	pushoverAPI = server.URL + "/1/messages.json"
+	t.Cleanup(func() { pushoverAPI = prev })
+	return server
+}
+
+func TestPushoverSendForm(t *testing.T) {
+	server := usePushoverServer(t, http.StatusOK, `{"status":1,"request":"abc"}`)
+	notifier := NewPushoverNotifier(&config.PushoverConfig{Token: "app-token", User: "user-key", Priority: 1})
+
+	if err := notifier.Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := server.last()
+	if req.Method != http.MethodPost || req.Path != "/1/messages.json" {
+		t.Errorf("request = %s %s, want POST /1/messages.json", req.Method, req.Path)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want a form", ct)
+	}
+
+	form := parseForm(t, req.Body)
+	title, message := pushMessage(testExpiryAlert())
+	want := map[string]string{
+		"token":    "app-token",
+		"user":     "user-key",
+		"title":    title,
+		"message":  message,
+		"priority": "1",
+	}
+	for key, value := range want {
+		if got := form.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	for _, key := range []string{"retry", "expire"} {
+		if form.Has(key) {
+			t.Errorf("%s sent for a non-emergency message", key)
+		}
+	}
+}
+
+func TestPushoverPriority(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured int
+		severity   string
+		want       string
+		wantRetry  bool
+	}{
+		{"info uses the configured priority", 0, SeverityInfo, "0", false},
+		{"warning uses the configured priority", -1, SeverityWarning, "-1", false},
+		{"critical is an emergency", 0, SeverityCritical, "2", true},
+		{"critical overrides the configured priority", 1, SeverityCritical, "2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := usePushoverServer(t, http.StatusOK, `{"status":1}`)
+			alert := testExpiryAlert()
+			alert.Severity = tt.severity
+
+			if err := NewPushoverNotifier(&config.PushoverConfig{Token: "t", User: "u", Priority: tt.configured}).Send(alert); err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+			form := parseForm(t, server.last().Body)
+			if got := form.Get("priority"); got != tt.want {
+				t.Errorf("priority = %q, want %q", got, tt.want)
+			}
+			if tt.wantRetry && (form.Get("retry") != "300" || form.Get("expire") != "3600") {
+				t.Errorf("retry, expire = %q, %q; want 300, 3600", form.Get("retry"), form.Get("expire"))
+			}
+			if !tt.wantRetry && (form.Has("retry") || form.Has("expire")) {
+				t.Error("retry and expire sent for a non-emergency message")
+			}
+		})
+	}
+}
+
+func TestPushoverTruncates(t *testing.T) {
+	server := usePushoverServer(t, http.StatusOK, `{"status":1}`)
+	alert := testExpiryAlert()
+	alert.Message = strings.Repeat("域名", pushoverMaxMessage)
+
+	if err := NewPushoverNotifier(&config.PushoverConfig{Token: "t", User: "u"}).Send(alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	message := parseForm(t, server.last().Body).Get("message")
+	if n := utf8.RuneCountInString(message); n != pushoverMaxMessage {
+		t.Errorf("message has %d characters, want %d", n, pushoverMaxMessage)
+	}
+	if !utf8.ValidString(message) || !strings.HasSuffix(message, "…") {
+		t.Error("message not truncated on a character boundary with an ellipsis")
+	}
+}
+
+func TestPushoverSendErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantErr       string
+		wantPermanent bool
+	}{
+		{"invalid token", http.StatusBadRequest, `{"status":0,"errors":["application token is invalid"]}`, "application token is invalid", true},
+		{"status 0", http.StatusOK, `{"status":0,"errors":["user key is invalid"]}`, "user key is invalid", false},
+		{"rate limited", http.StatusTooManyRequests, `{"status":0}`, "status 429", false},
+		{"server error", http.StatusInternalServerError, ``, "status 500", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usePushoverServer(t, tt.status, tt.body)
+			err := NewPushoverNotifier(&config.PushoverConfig{Token: "t", User: "u"}).Send(testExpiryAlert())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			var perm *permanentError
+			if got := errors.As(err, &perm); got != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// notifyHTTPTimeout bounds every HTTP request made by the notifiers
+const notifyHTTPTimeout = 30 * time.Second
+
+// notifyClient is the HTTP client shared by the webhook based notifiers
+var notifyClient = &http.Client{Timeout: notifyHTTPTimeout}
+
+// Defaults for retrying failed notifications
+const (
+	defaultNotifyRetries    = 3
+	defaultNotifyRetryDelay = time.Second
+)
+
+// permanentError marks a notifier error that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// permanent wraps an error so that the notify service doesn't retry it
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// sendWithRetry sends an alert through one notifier, retrying failures with exponential backoff
+func (s *NotifyService) sendWithRetry(notifier Notifier, alert *Alert) error {
+	delay := s.retryDelay
+	for attempt := 0; ; attempt++ {
+		err := notifier.Send(alert)
+		if err == nil {
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) || attempt >= s.maxRetries {
+			return err
+		}
+
+		slog.Warn("Notification failed, retrying", append(alert.logArgs(), "channel", notifier.Type(), "attempt", attempt+1, "delay", delay, "error", err)...)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newFlakyServer fails the first failures requests with a 502 and accepts the rest
+func newFlakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// notificationStatuses returns the recorded notification statuses by channel
+func notificationStatuses(t *testing.T) map[string]string {
+	t.Helper()
+	var notifications []models.Notification
+	if err := database.DB.Find(&notifications).Error; err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]string{}
+	for _, n := range notifications {
+		statuses[n.Type] = n.Status
+	}
+	return statuses
+}
+
+func TestSendRetriesFlakyWebhook(t *testing.T) {
+	setupTestDB(t)
+	server, calls := newFlakyServer(t, 2)
+
+	s := NewNotifyService(&config.NotificationsConfig{
+		MaxRetries: 3,
+		RetryDelay: "1ms",
+		Webhook:    config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+	if err := s.SendAlert(testExpiryAlert()); err != nil {
+		t.Fatalf("SendAlert: %v", err)
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("webhook called %d times, want 3", got)
+	}
+	if got := notificationStatuses(t)["webhook"]; got != "success" {
+		t.Errorf("recorded status = %q, want success", got)
+	}
+}
+
+func TestSendGivesUpAfterMaxRetries(t *testing.T) {
+	setupTestDB(t)
+	server, calls := newFlakyServer(t, 10)
+
+	s := NewNotifyService(&config.NotificationsConfig{
+		MaxRetries: 2,
+		RetryDelay: "1ms",
+		Webhook:    config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+	if err := s.SendAlert(testExpiryAlert()); err == nil {
+		t.Error("SendAlert succeeded although every attempt failed")
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("webhook called %d times, want the first attempt and 2 retries", got)
+	}
+	if got := notificationStatuses(t)["webhook"]; got != "failed" {
+		t.Errorf("recorded status = %q, want failed", got)
+	}
+}
+
+func TestSendRetryIsPerNotifier(t *testing.T) {
+	setupTestDB(t)
+	server, calls := newFlakyServer(t, 1)
+
+	s := NewNotifyService(&config.NotificationsConfig{
+		MaxRetries: 3,
+		RetryDelay: "1ms",
+		Webhook:    config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+	// A permanent failure is not retried and doesn't affect the other channel
+	broken := &fakeNotifier{channel: "telegram", fail: true}
+	s.notifiers = append(s.notifiers, broken)
+
+	s.SendAlert(testExpiryAlert())
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("webhook called %d times, want 2", got)
+	}
+	statuses := notificationStatuses(t)
+	if statuses["webhook"] != "success" || statuses["telegram"] != "failed" {
+		t.Errorf("recorded statuses = %v", statuses)
+	}
+}
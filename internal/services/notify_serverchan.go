@@ -0,0 +1,92 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// serverChanAPI is the ServerChan Turbo push endpoint, formatted with the SendKey
+var serverChanAPI = "https://sctapi.ftqq.com/%s.send"
+
+// ServerChanNotifier sends ServerChan (Server酱) WeChat push notifications
+type ServerChanNotifier struct {
+	config *config.ServerChanConfig
+}
+
+// NewServerChanNotifier creates a new ServerChan notifier
+func NewServerChanNotifier(cfg *config.ServerChanConfig) *ServerChanNotifier {
+	return &ServerChanNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (s *ServerChanNotifier) Type() string {
+	return "serverchan"
+}
+
+// Send pushes the alert with a markdown description
+func (s *ServerChanNotifier) Send(alert *Alert) error {
+	title, _ := pushMessage(alert)
+	form := url.Values{
+		"title": {title},
+		"desp":  {serverChanMarkdown(alert)},
+	}
+
+	resp, err := notifyClient.PostForm(fmt.Sprintf(serverChanAPI, url.PathEscape(s.config.SendKey)), form)
+	if err != nil {
+		// The request URL carries the SendKey, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("serverchan request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("serverchan API returned status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("invalid serverchan response: %w", err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("serverchan API error %d: %s", result.Code, result.Message)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("serverchan API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// serverChanMarkdown builds the markdown description of the alert
+func serverChanMarkdown(alert *Alert) string {
+	m := alert.messages()
+	if !alert.IsExpiry() {
+		return alert.domainLine("**%s**"+m.Colon+"%s\n\n") + alert.markdownMessage()
+	}
+	if alert.Body != "" {
+		return alert.Body
+	}
+
+	domain := alert.Domain
+	content := fmt.Sprintf("- **%s**%s%s\n- **%s**%s%s\n- **%s**%s%s\n- **%s**%s%s\n- **%s**%s%s",
+		m.Domain, m.Colon, domain.Name,
+		m.DaysRemaining, m.Colon, m.DaysText(alert.DaysRemaining),
+		m.ExpiryDate, m.Colon, models.FormatDate(models.TimeValue(domain.ExpiryDate)),
+		m.Registrar, m.Colon, domain.Registrar,
+		m.Status, m.Colon, domain.Status,
+	)
+	if alert.Message != "" {
+		content += "\n\n" + alert.Message
+	}
+	return content
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// useServerChanServer points the ServerChan notifier at a test server for the test
+func useServerChanServer(t *testing.T, status int, body string) *captureServer {
+	t.Helper()
+	server := newCaptureServer(t, status, body)
+	prev := serverChanAPI
+	serverChanAPI = server.URL + "/%s.send"
+	t.Cleanup(func() { serverChanAPI = prev })
+	return server
+}
+
+// lastNotification returns the latest recorded notification
+func lastNotification(t *testing.T) models.Notification {
+	t.Helper()
+	var notification models.Notification
+	if err := database.DB.Order("id desc").First(&notification).Error; err != nil {
+		t.Fatalf("no notification recorded: %v", err)
+	}
+	return notification
+}
+
+func TestServerChanSend(t *testing.T) {
+	setupTestDB(t)
+	server := useServerChanServer(t, http.StatusOK, `{"code":0,"message":"","data":{"pushid":"1"}}`)
+	notifier := NewServerChanNotifier(&config.ServerChanConfig{SendKey: "SCT123"})
+
+	if err := (&NotifyService{}).sendTo(notifier, testExpiryAlert(), nil, nil); err != nil {
+		t.Fatalf("sendTo: %v", err)
+	}
+
+	req := server.last()
+	if req.Method != http.MethodPost || req.Path != "/SCT123.send" {
+		t.Errorf("request = %s %s, want POST /SCT123.send", req.Method, req.Path)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want a form", ct)
+	}
+	form := parseForm(t, req.Body)
+	if title, _ := pushMessage(testExpiryAlert()); form.Get("title") != title {
+		t.Errorf("title = %q, want %q", form.Get("title"), title)
+	}
+	desp := form.Get("desp")
+	for _, want := range []string{"- **", "example.com", "Example Registrar", "2026-01-02"} {
+		if !strings.Contains(desp, want) {
+			t.Errorf("desp %q does not contain %q", desp, want)
+		}
+	}
+
+	if got := lastNotification(t); got.Type != "serverchan" || got.Status != "success" {
+		t.Errorf("recorded %s notification with status %q, want serverchan success", got.Type, got.Status)
+	}
+}
+
+func TestServerChanErrorCodeRecordedAsFailed(t *testing.T) {
+	setupTestDB(t)
+	server := useServerChanServer(t, http.StatusOK, `{"code":40001,"message":"bad pushtoken"}`)
+	notifier := NewServerChanNotifier(&config.ServerChanConfig{SendKey: "SCT123"})
+
+	err := (&NotifyService{}).sendTo(notifier, testExpiryAlert(), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "40001") || !strings.Contains(err.Error(), "bad pushtoken") {
+		t.Errorf("sendTo() error = %v, want the API error code and message", err)
+	}
+	if server.requests() != 1 {
+		t.Errorf("sent %d requests, want 1", server.requests())
+	}
+	if got := lastNotification(t); got.Type != "serverchan" || got.Status != "failed" {
+		t.Errorf("recorded %s notification with status %q, want serverchan failed", got.Type, got.Status)
+	}
+}
+
+func TestServerChanSendErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"HTTP error", http.StatusInternalServerError, `bad gateway`, "status 500"},
+		{"HTTP error with code", http.StatusBadRequest, `{"code":0}`, "status 400"},
+		{"invalid response", http.StatusOK, `<html>`, "invalid serverchan response"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useServerChanServer(t, tt.status, tt.body)
+			err := NewServerChanNotifier(&config.ServerChanConfig{SendKey: "SCT123"}).Send(testExpiryAlert())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"bytes"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TeamsNotifier sends Microsoft Teams incoming webhook notifications
+type TeamsNotifier struct {
+	config *config.TeamsConfig
+}
+
+// NewTeamsNotifier creates a new Teams notifier
+func NewTeamsNotifier(cfg *config.TeamsConfig) *TeamsNotifier {
+	return &TeamsNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (t *TeamsNotifier) Type() string {
+	return "teams"
+}
+
+// teamsFact is a name/value row of a MessageCard section
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Send posts the alert as a MessageCard colored by severity
+func (t *TeamsNotifier) Send(alert *Alert) error {
+	jsonData, err := json.Marshal(teamsCard(alert))
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifyClient.Post(t.config.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		// The webhook URL carries its signature, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("teams request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Webhooks answer 200 (connectors) or 202 (workflows); errors come as plain text
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("teams webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		// A rejected card or a removed webhook will not succeed on retry
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return permanent(err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// teamsCard builds the MessageCard of an alert: expiry reminders list the domain details
+// as facts, other alerts show their message as markdown
+func teamsCard(alert *Alert) map[string]interface{} {
+	m := alert.messages()
+	title := severityEmoji(alert.Severity) + " " + alert.Title
+	var facts []teamsFact
+	text := alert.markdownMessage()
+
+	if alert.IsExpiry() {
+		domain := alert.Domain
+		title = severityEmoji(alert.Severity) + " " + m.ExpiryTitle
+		facts = []teamsFact{
+			{Name: m.Domain, Value: domain.Name},
+			{Name: m.DaysRemaining, Value: m.DaysText(alert.DaysRemaining)},
+			{Name: m.ExpiryDate, Value: models.FormatDate(models.TimeValue(domain.ExpiryDate))},
+			{Name: m.Registrar, Value: domain.Registrar},
+			{Name: m.Status, Value: domain.Status},
+		}
+		if alert.Body != "" {
+			facts, text = nil, alert.Body
+		}
+	} else if alert.Domain != nil {
+		facts = []teamsFact{{Name: m.Domain, Value: alert.Domain.Name}}
+	}
+
+	section := map[string]interface{}{
+		"markdown": true,
+	}
+	if len(facts) > 0 {
+		section["facts"] = facts
+	}
+	if text != "" {
+		section["text"] = text
+	}
+
+	return map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": teamsColor(alert.Severity),
+		"summary":    alert.Subject(),
+		"title":      title,
+		"sections":   []interface{}{section},
+	}
+}
+
+// teamsColor returns the MessageCard theme color for a severity
+func teamsColor(severity string) string {
+	switch severity {
+	case SeverityCritical:
+		return "D93025"
+	case SeverityWarning:
+		return "F29900"
+	default:
+		return "188038"
+	}
+}
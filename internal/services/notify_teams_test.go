@@ -0,0 +1,166 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// messageCard is the structure of a Teams MessageCard
+type messageCard struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	ThemeColor string `json:"themeColor"`
+	Summary    string `json:"summary"`
+	Title      string `json:"title"`
+	Sections   []struct {
+		Markdown bool        `json:"markdown"`
+		Facts    []teamsFact `json:"facts"`
+		Text     string      `json:"text"`
+	} `json:"sections"`
+}
+
+// sendTeamsCard sends the alert to a test webhook and returns the card it received
+func sendTeamsCard(t *testing.T, alert *Alert) messageCard {
+	t.Helper()
+	server := newCaptureServer(t, http.StatusOK, `1`)
+	if err := NewTeamsNotifier(&config.TeamsConfig{WebhookURL: server.URL}).Send(alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := server.last()
+	if req.Method != http.MethodPost || req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("request = %s with Content-Type %q, want a JSON POST", req.Method, req.Header.Get("Content-Type"))
+	}
+	var card messageCard
+	if err := json.Unmarshal(req.Body, &card); err != nil {
+		t.Fatalf("decode %q: %v", req.Body, err)
+	}
+	if len(card.Sections) != 1 {
+		t.Fatalf("card has %d sections, want 1", len(card.Sections))
+	}
+	return card
+}
+
+func TestTeamsExpiryCard(t *testing.T) {
+	alert := testExpiryAlert()
+	alert.Domain.Status = "clientTransferProhibited"
+	card := sendTeamsCard(t, alert)
+
+	if card.Type != "MessageCard" || card.Context != "https://schema.org/extensions" {
+		t.Errorf("@type, @context = %q, %q; want a MessageCard", card.Type, card.Context)
+	}
+	if card.ThemeColor != "F29900" {
+		t.Errorf("themeColor = %q, want F29900 for a warning", card.ThemeColor)
+	}
+	if card.Summary != alert.Subject() {
+		t.Errorf("summary = %q, want %q", card.Summary, alert.Subject())
+	}
+	m := alert.messages()
+	if want := "🟡 " + m.ExpiryTitle; card.Title != want {
+		t.Errorf("title = %q, want %q", card.Title, want)
+	}
+
+	section := card.Sections[0]
+	if !section.Markdown || section.Text != "" {
+		t.Errorf("section markdown = %v, text = %q; want markdown without text", section.Markdown, section.Text)
+	}
+	wantFacts := []teamsFact{
+		{Name: m.Domain, Value: "example.com"},
+		{Name: m.DaysRemaining, Value: m.DaysText(7)},
+		{Name: m.ExpiryDate, Value: "2026-01-02"},
+		{Name: m.Registrar, Value: "Example Registrar"},
+		{Name: m.Status, Value: "clientTransferProhibited"},
+	}
+	if !reflect.DeepEqual(section.Facts, wantFacts) {
+		t.Errorf("facts = %+v, want %+v", section.Facts, wantFacts)
+	}
+}
+
+func TestTeamsColor(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{90, "188038"},
+		{30, "F29900"},
+		{7, "D93025"},
+		{-2, "D93025"},
+	}
+	for _, tt := range tests {
+		alert := testExpiryAlert()
+		alert.DaysRemaining = tt.days
+		alert.Severity = severityForDays(tt.days)
+		if got := sendTeamsCard(t, alert).ThemeColor; got != tt.want {
+			t.Errorf("themeColor with %d days = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestTeamsTemplatedCard(t *testing.T) {
+	alert := testExpiryAlert()
+	alert.Body = "**example.com** renews in 7 days"
+	section := sendTeamsCard(t, alert).Sections[0]
+	if section.Facts != nil || section.Text != alert.Body {
+		t.Errorf("section facts = %+v, text = %q; want only the template", section.Facts, section.Text)
+	}
+}
+
+func TestTeamsOtherAlertCard(t *testing.T) {
+	alert := &Alert{
+		Kind:     AlertDNS,
+		Domain:   &models.Domain{Name: "example.com"},
+		Severity: SeverityCritical,
+		Title:    "DNS changed",
+		Message:  "A record changed",
+	}
+	card := sendTeamsCard(t, alert)
+	if card.Title != "🔴 DNS changed" || card.ThemeColor != "D93025" {
+		t.Errorf("title, themeColor = %q, %q; want 🔴 DNS changed, D93025", card.Title, card.ThemeColor)
+	}
+	section := card.Sections[0]
+	if want := []teamsFact{{Name: alert.messages().Domain, Value: "example.com"}}; !reflect.DeepEqual(section.Facts, want) {
+		t.Errorf("facts = %+v, want %+v", section.Facts, want)
+	}
+	if !strings.Contains(section.Text, "A record changed") {
+		t.Errorf("text = %q, want the alert message", section.Text)
+	}
+}
+
+func TestTeamsSendStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantErr       string
+		wantPermanent bool
+	}{
+		{"workflow accepted", http.StatusAccepted, ``, "", false},
+		{"invalid card", http.StatusBadRequest, `Summary or Text is required.`, "Summary or Text is required", true},
+		{"throttled", http.StatusTooManyRequests, ``, "status 429", false},
+		{"server error", http.StatusBadGateway, ``, "status 502", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newCaptureServer(t, tt.status, tt.body)
+			err := NewTeamsNotifier(&config.TeamsConfig{WebhookURL: server.URL}).Send(testExpiryAlert())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Send: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+			var perm *permanentError
+			if got := errors.As(err, &perm); got != tt.wantPermanent {
+				t.Errorf("permanent = %v, want %v", got, tt.wantPermanent)
+			}
+		})
+	}
+}
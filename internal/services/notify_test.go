@@ -0,0 +1,63 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendSystemAlertCooldown(t *testing.T) {
+	setupTestDB(t)
+	s := NewNotifyService(&config.NotificationsConfig{})
+	channel := &fakeNotifier{channel: "telegram"}
+	s.systemNotifiers = []Notifier{channel}
+
+	s.SendSystemAlert("Check failed", "example.com: timeout")
+	s.SendSystemAlert("Check failed", "example.com: timeout")
+	if got := len(channel.sent()); got != 1 {
+		t.Fatalf("identical alerts sent %d times within the cooldown, want 1", got)
+	}
+
+	s.SendSystemAlert("Check failed", "example.org: timeout")
+	if got := len(channel.sent()); got != 2 {
+		t.Fatalf("sent %d alerts, want 2 after a different message", got)
+	}
+}
+
+func TestSendSystemAlertPrunesExpiredKeys(t *testing.T) {
+	setupTestDB(t)
+	s := NewNotifyService(&config.NotificationsConfig{})
+	s.systemNotifiers = []Notifier{&fakeNotifier{channel: "telegram"}}
+
+	expired := time.Now().Add(-2 * systemAlertCooldown)
+	for _, key := range []string{"old\n1", "old\n2", "old\n3"} {
+		s.systemSent[key] = expired
+	}
+	s.systemSent["recent\n1"] = time.Now()
+
+	s.SendSystemAlert("new", "alert")
+
+	if len(s.systemSent) != 2 {
+		t.Errorf("systemSent has %d keys, want the recent and the new one: %v", len(s.systemSent), s.systemSent)
+	}
+	if _, ok := s.systemSent["recent\n1"]; !ok {
+		t.Error("a key still in its cooldown was pruned")
+	}
+}
+
+func TestDingDingErrorHidesToken(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, ``)
+	server.Close()
+
+	d := NewDingDingNotifier(&config.DingDingConfig{Webhook: server.URL + "/robot/send?access_token=secret-token", Secret: "SECxyz"})
+	// post is a single attempt, without the retries of Send
+	_, err := d.post([]byte(`{}`))
+	if err == nil {
+		t.Fatal("post() succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-token") || strings.Contains(err.Error(), "sign=") {
+		t.Errorf("error %q reveals the signed webhook URL", err)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"testing"
+)
+
+func TestNotifierType(t *testing.T) {
+	tests := []struct {
+		notifier Notifier
+		want     string
+	}{
+		{NewEmailNotifier(&config.EmailConfig{}, ""), "email"},
+		{NewWebhookNotifier(&config.WebhookConfig{}), "webhook"},
+		{NewTelegramNotifier(&config.TelegramConfig{}), "telegram"},
+		{NewDingDingNotifier(&config.DingDingConfig{}), "dingding"},
+		{NewFeishuNotifier(&config.FeishuConfig{}), "feishu"},
+		{NewWeComNotifier(&config.WeComConfig{}), "wecom"},
+		{NewBarkNotifier(&config.BarkConfig{}), "bark"},
+		{NewServerChanNotifier(&config.ServerChanConfig{}), "serverchan"},
+		{NewPushoverNotifier(&config.PushoverConfig{}), "pushover"},
+		{NewGotifyNotifier(&config.GotifyConfig{}), "gotify"},
+		{NewTeamsNotifier(&config.TeamsConfig{}), "teams"},
+	}
+	for _, tt := range tests {
+		if got := tt.notifier.Type(); got != tt.want {
+			t.Errorf("%T.Type() = %q, want %q", tt.notifier, got, tt.want)
+		}
+	}
+}
+
+func TestChannelsBuildTheirType(t *testing.T) {
+	cfg := &config.NotificationsConfig{}
+	for _, channel := range channels {
+		if got := channel.build(cfg).Type(); got != channel.Type {
+			t.Errorf("channel %s builds a notifier of type %q", channel.Type, got)
+		}
+	}
+}
+
+func TestRecordNotificationType(t *testing.T) {
+	setupTestDB(t)
+	server, _ := newWebhookServer(t)
+	notify := &NotifyService{}
+
+	if err := notify.sendTo(NewWebhookNotifier(&config.WebhookConfig{URL: server.URL}), testExpiryAlert(), nil, nil); err != nil {
+		t.Fatalf("sendTo: %v", err)
+	}
+
+	var recorded models.Notification
+	if err := database.DB.First(&recorded).Error; err != nil {
+		t.Fatalf("no notification recorded: %v", err)
+	}
+	if recorded.Type != "webhook" {
+		t.Errorf("recorded type = %q, want webhook", recorded.Type)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// webhookRequest is a request received by the webhook test server
+type webhookRequest struct {
+	method string
+	header http.Header
+	body   []byte
+}
+
+// newWebhookServer records the requests it receives and answers 200
+func newWebhookServer(t *testing.T) (*httptest.Server, *[]webhookRequest) {
+	t.Helper()
+	var requests []webhookRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, webhookRequest{method: r.Method, header: r.Header.Clone(), body: body})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// testExpiryAlert is a 7-day expiry alert for example.com
+func testExpiryAlert() *Alert {
+	expiry := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	return &Alert{
+		Kind:          AlertExpiry,
+		Domain:        &models.Domain{Name: "example.com", Registrar: "Example Registrar", ExpiryDate: &expiry},
+		DaysRemaining: 7,
+		Severity:      SeverityWarning,
+	}
+}
+
+func TestWebhookDefaultPayload(t *testing.T) {
+	server, requests := newWebhookServer(t)
+
+	if err := NewWebhookNotifier(&config.WebhookConfig{URL: server.URL}).Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(*requests))
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", req.method)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("decode %q: %v", req.body, err)
+	}
+	if payload["domain"] != "example.com" || payload["days_remaining"] != float64(7) || payload["registrar"] != "Example Registrar" || payload["event"] != AlertExpiry {
+		t.Errorf("payload = %v", payload)
+	}
+}
+
+func TestWebhookCustomTemplate(t *testing.T) {
+	server, requests := newWebhookServer(t)
+
+	cfg := &config.WebhookConfig{
+		URL:      server.URL,
+		Method:   "put",
+		Template: `{"alert":{"name":{{json .Domain}},"days":{{.DaysRemaining}},"expires":"{{.ExpiryDate}}"}}`,
+	}
+	if err := NewWebhookNotifier(cfg).Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := (*requests)[0]
+	if req.method != http.MethodPut {
+		t.Errorf("method = %s, want PUT", req.method)
+	}
+	if want := `{"alert":{"name":"example.com","days":7,"expires":"2026-01-02"}}`; string(req.body) != want {
+		t.Errorf("body = %s, want %s", req.body, want)
+	}
+}
+
+func TestWebhookInvalidTemplate(t *testing.T) {
+	server, requests := newWebhookServer(t)
+
+	err := NewWebhookNotifier(&config.WebhookConfig{URL: server.URL, Template: "{{.Domain"}).Send(testExpiryAlert())
+	if err == nil {
+		t.Error("Send accepted an invalid template")
+	}
+	if len(*requests) != 0 {
+		t.Errorf("sent %d requests with an invalid template", len(*requests))
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	// HMAC-SHA256 of the body keyed with the secret, hex encoded
+	got := webhookSignature([]byte(`{"domain":"example.com"}`), "webhook-secret")
+	if want := "1b3ede4fb96968890d7761d08854d5632a93bc3b9746dbaa4d950b3395048ca6"; got != want {
+		t.Errorf("webhookSignature() = %s, want %s", got, want)
+	}
+}
+
+func TestWebhookSignedRequest(t *testing.T) {
+	server, requests := newWebhookServer(t)
+
+	cfg := &config.WebhookConfig{
+		URL:      server.URL,
+		Secret:   "webhook-secret",
+		Headers:  map[string]string{"Authorization": "Token abc"},
+		Template: `{"domain":{{json .Domain}}}`,
+	}
+	if err := NewWebhookNotifier(cfg).Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	req := (*requests)[0]
+	if string(req.body) != `{"domain":"example.com"}` {
+		t.Fatalf("body = %s", req.body)
+	}
+	if got := req.header.Get("X-Jiankong-Signature"); got != "1b3ede4fb96968890d7761d08854d5632a93bc3b9746dbaa4d950b3395048ca6" {
+		t.Errorf("X-Jiankong-Signature = %q", got)
+	}
+	if got := req.header.Get("Authorization"); got != "Token abc" {
+		t.Errorf("Authorization = %q, want the configured header", got)
+	}
+}
+
+func TestWebhookUnsignedRequest(t *testing.T) {
+	server, requests := newWebhookServer(t)
+
+	if err := NewWebhookNotifier(&config.WebhookConfig{URL: server.URL}).Send(testExpiryAlert()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := (*requests)[0].header.Get("X-Jiankong-Signature"); got != "" {
+		t.Errorf("X-Jiankong-Signature = %q without a secret", got)
+	}
+}
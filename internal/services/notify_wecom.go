@@ -0,0 +1,114 @@
+package services
+
+import (
+	"bytes"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WeComNotifier sends Enterprise WeChat (WeCom) group robot notifications
+type WeComNotifier struct {
+	config *config.WeComConfig
+}
+
+// NewWeComNotifier creates a new WeCom notifier
+func NewWeComNotifier(cfg *config.WeComConfig) *WeComNotifier {
+	return &WeComNotifier{config: cfg}
+}
+
+// Type returns the channel identifier
+func (w *WeComNotifier) Type() string {
+	return "wecom"
+}
+
+// Send sends a WeCom markdown message
+func (w *WeComNotifier) Send(alert *Alert) error {
+	payload := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]interface{}{
+			"content": weComMarkdown(alert),
+		},
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	resp, err := notifyClient.Post(w.config.WebhookURL, "application/json", bytes.NewBuffer(jsonData))
+	if err != nil {
+		// The webhook URL carries the robot key, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("wecom request failed: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wecom webhook returned status %d", resp.StatusCode)
+	}
+
+	// 检查响应
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err == nil {
+		if errCode, ok := result["errcode"].(float64); ok && errCode != 0 {
+			return fmt.Errorf("wecom API error %d: %v", int(errCode), result["errmsg"])
+		}
+	}
+
+	return nil
+}
+
+// weComMarkdown builds the markdown content, coloring the days remaining by severity
+func weComMarkdown(alert *Alert) string {
+	color := weComColor(alert.Severity)
+	m := alert.messages()
+
+	if !alert.IsExpiry() {
+		return fmt.Sprintf("## <font color=\"%s\">%s</font>\n%s%s",
+			color,
+			alert.Title,
+			alert.domainLine("> %s"+m.Colon+"%s\n\n"),
+			alert.Message,
+		)
+	}
+	if alert.Body != "" {
+		return alert.Body
+	}
+
+	domain := alert.Domain
+	content := fmt.Sprintf("## %s %s\n"+
+		"> %s\n"+
+		"> %s<font color=\"%s\">%s</font>\n"+
+		"> %s\n"+
+		"> %s\n"+
+		"> %s",
+		severityEmoji(alert.Severity), m.ExpiryTitle,
+		m.Field(m.Domain, domain.Name),
+		m.DaysRemaining+m.Colon, color, m.DaysText(alert.DaysRemaining),
+		m.Field(m.ExpiryDate, models.FormatDate(models.TimeValue(domain.ExpiryDate))),
+		m.Field(m.Registrar, domain.Registrar),
+		m.Field(m.Status, domain.Status),
+	)
+	if alert.Message != "" {
+		content += "\n\n" + alert.Message
+	}
+	return content
+}
+
+// weComColor returns the WeCom markdown font color for a severity
+func weComColor(severity string) string {
+	switch severity {
+	case SeverityCritical, SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
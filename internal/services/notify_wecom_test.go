@@ -0,0 +1,87 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWeComSendMarkdown(t *testing.T) {
+	var payload struct {
+		MsgType  string `json:"msgtype"`
+		Markdown struct {
+			Content string `json:"content"`
+		} `json:"markdown"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decode payload: %v", err)
+		}
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	alert := &Alert{Domain: &models.Domain{Name: "example.com", Registrar: "Example Registrar"}, DaysRemaining: 3, Severity: SeverityCritical}
+	if err := NewWeComNotifier(&config.WeComConfig{WebhookURL: server.URL}).Send(alert); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if payload.MsgType != "markdown" {
+		t.Errorf("msgtype = %q, want markdown", payload.MsgType)
+	}
+	for _, want := range []string{"example.com", "Example Registrar", `<font color="warning">`} {
+		if !strings.Contains(payload.Markdown.Content, want) {
+			t.Errorf("content %q does not contain %q", payload.Markdown.Content, want)
+		}
+	}
+}
+
+func TestWeComMarkdownColor(t *testing.T) {
+	alert := &Alert{Domain: &models.Domain{Name: "example.com"}, DaysRemaining: 60, Severity: SeverityInfo}
+	if content := weComMarkdown(alert); !strings.Contains(content, `<font color="info">`) {
+		t.Errorf("info alert content %q is not colored info", content)
+	}
+}
+
+func TestWeComSendErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"API error", http.StatusOK, `{"errcode":93000,"errmsg":"invalid webhook url"}`, "93000"},
+		{"HTTP error", http.StatusNotFound, ``, "status 404"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := NewWeComNotifier(&config.WeComConfig{WebhookURL: server.URL}).Send(&Alert{Domain: &models.Domain{Name: "example.com"}})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Send() error = %v, want it to mention %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWeComErrorHidesKey(t *testing.T) {
+	server := newCaptureServer(t, http.StatusOK, ``)
+	server.Close()
+
+	err := NewWeComNotifier(&config.WeComConfig{WebhookURL: server.URL + "/cgi-bin/webhook/send?key=secret-key"}).Send(testExpiryAlert())
+	if err == nil {
+		t.Fatal("Send() succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "secret-key") {
+		t.Errorf("error %q reveals the robot key", err)
+	}
+}
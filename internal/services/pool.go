@@ -0,0 +1,35 @@
+package services
+
+import (
+	"sync"
+)
+
+// runPool calls fn for every item using at most workers goroutines and waits for completion
+func runPool[T any](items []T, workers int, fn func(item T)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range jobs {
+				fn(item)
+			}
+		}()
+	}
+
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+
+	wg.Wait()
+}
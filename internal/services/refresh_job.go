@@ -0,0 +1,124 @@
+package services
+
+import (
+	"domain-monitor/internal/models"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Refresh job states
+const (
+	JobRunning  = "running"
+	JobFinished = "finished"
+)
+
+// maxRefreshJobs is the number of finished refresh jobs kept for status queries
+const maxRefreshJobs = 20
+
+// ErrRefreshRunning is returned when a refresh job is started while another one runs
+var ErrRefreshRunning = errors.New("a refresh is already running")
+
+// RefreshJob tracks the progress of a background re-check of domains
+type RefreshJob struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"` // running/finished
+	Total      int        `json:"total"`
+	Checked    int        `json:"checked"` // Domains processed so far, including failures
+	Failed     int        `json:"failed"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// refreshJobs keeps the recent refresh jobs of a monitor service
+type refreshJobs struct {
+	mu    sync.Mutex
+	seq   int
+	jobs  []*RefreshJob // Oldest first
+	byID  map[string]*RefreshJob
+	alive *RefreshJob // Running job, if any
+}
+
+// StartRefresh re-checks the domains in the background through a bounded worker pool
+// and returns the job for progress tracking. Refreshes are manual, so they query WHOIS
+// live instead of using the caches. Only one refresh runs at a time. onFinish, if not
+// nil, is called when a started job finishes.
+func (s *MonitorService) StartRefresh(domains []models.Domain, onFinish func()) (*RefreshJob, error) {
+	s.refresh.mu.Lock()
+	defer s.refresh.mu.Unlock()
+
+	if s.refresh.alive != nil {
+		job := *s.refresh.alive
+		return &job, ErrRefreshRunning
+	}
+	if !s.startWork() {
+		return nil, ErrShuttingDown
+	}
+
+	s.refresh.seq++
+	job := &RefreshJob{
+		ID:        strconv.Itoa(s.refresh.seq),
+		Status:    JobRunning,
+		Total:     len(domains),
+		StartedAt: time.Now(),
+	}
+	if s.refresh.byID == nil {
+		s.refresh.byID = make(map[string]*RefreshJob)
+	}
+	s.refresh.jobs = append(s.refresh.jobs, job)
+	s.refresh.byID[job.ID] = job
+	s.refresh.alive = job
+
+	if len(s.refresh.jobs) > maxRefreshJobs {
+		delete(s.refresh.byID, s.refresh.jobs[0].ID)
+		s.refresh.jobs = s.refresh.jobs[1:]
+	}
+
+	go s.runRefresh(job, domains, onFinish)
+
+	snapshot := *job
+	return &snapshot, nil
+}
+
+// RefreshJob returns a snapshot of a refresh job, or false if it is unknown
+func (s *MonitorService) RefreshJob(id string) (RefreshJob, bool) {
+	s.refresh.mu.Lock()
+	defer s.refresh.mu.Unlock()
+
+	job, ok := s.refresh.byID[id]
+	if !ok {
+		return RefreshJob{}, false
+	}
+	return *job, true
+}
+
+// runRefresh checks the domains of a refresh job and records its progress
+func (s *MonitorService) runRefresh(job *RefreshJob, domains []models.Domain, onFinish func()) {
+	defer s.work.Done()
+	if onFinish != nil {
+		defer onFinish()
+	}
+
+	slog.Info("Refresh job started", "job", job.ID, "count", len(domains))
+
+	s.checkDomains(s.ctx, domains, true, func(err error) {
+		s.refresh.mu.Lock()
+		defer s.refresh.mu.Unlock()
+
+		job.Checked++
+		if err != nil {
+			job.Failed++
+		}
+	})
+
+	s.refresh.mu.Lock()
+	now := time.Now()
+	job.Status = JobFinished
+	job.FinishedAt = &now
+	s.refresh.alive = nil
+	s.refresh.mu.Unlock()
+
+	slog.Info("Refresh job finished", "job", job.ID, "checked", job.Checked, "failed", job.Failed)
+}
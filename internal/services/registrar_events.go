@@ -0,0 +1,143 @@
+package services
+
+import (
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownProvider is returned for registrar webhook providers that are not supported
+var ErrUnknownProvider = errors.New("unknown registrar webhook provider")
+
+// RegistrarEvent represents a domain event pushed by a registrar
+type RegistrarEvent struct {
+	Provider   string    `json:"provider"`
+	Event      string    `json:"event"` // e.g. renewal/transfer
+	Domain     string    `json:"domain"`
+	Registrar  string    `json:"registrar"`
+	ExpiryDate time.Time `json:"expiry_date"`
+}
+
+// ParseRegistrarEvent parses a registrar webhook payload for the given provider.
+//
+// Supported providers:
+//   - generic: flat or nested JSON mapped through the configured field paths
+//   - cloudevents: CloudEvents envelope (e.g. Alibaba Cloud EventBridge) with the
+//     event in "type" and domainName/expirationDate/registrar in "data"
+func ParseRegistrarEvent(provider string, body []byte, mapping config.RegistrarFieldMapping) (*RegistrarEvent, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON payload: %w", err)
+	}
+
+	event := &RegistrarEvent{Provider: provider}
+	var expiry string
+
+	switch provider {
+	case "generic":
+		event.Domain = lookupString(payload, defaultString(mapping.DomainField, "domain"))
+		event.Event = lookupString(payload, defaultString(mapping.EventField, "event"))
+		event.Registrar = lookupString(payload, defaultString(mapping.RegistrarField, "registrar"))
+		expiry = lookupString(payload, defaultString(mapping.ExpiryField, "expiry_date"))
+	case "cloudevents":
+		event.Event = lookupString(payload, "type")
+		event.Domain = firstString(payload, "data.domainName", "data.domain", "subject")
+		event.Registrar = firstString(payload, "data.registrar", "source")
+		expiry = firstString(payload, "data.expirationDate", "data.expiryDate", "data.expiry_date")
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+
+	event.Domain = strings.ToLower(strings.TrimSpace(event.Domain))
+	if event.Domain == "" {
+		return nil, fmt.Errorf("payload does not contain a domain name")
+	}
+
+	if expiry != "" {
+		t, err := parseDate(expiry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry date: %w", err)
+		}
+		event.ExpiryDate = t
+	}
+
+	return event, nil
+}
+
+// ApplyRegistrarEvent updates the monitored domain from a registrar event
+func (s *MonitorService) ApplyRegistrarEvent(event *RegistrarEvent) (*models.Domain, error) {
+	var domain models.Domain
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Where("name = ?", event.Domain).First(&domain).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	if event.Registrar != "" {
+		domain.Registrar = event.Registrar
+	}
+	if !event.ExpiryDate.IsZero() {
+		domain.ExpiryDate = models.NullTime(event.ExpiryDate)
+		domain.DaysRemaining = models.DaysUntil(event.ExpiryDate, time.Now())
+	}
+	domain.UpdatedAt = time.Now()
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(&domain).Error
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save domain: %w", err)
+	}
+
+	slog.Info("Applied registrar event", "provider", event.Provider, "event", event.Event, "domain", domain.Name, "days_remaining", domain.DaysRemaining)
+
+	s.CheckAndNotify(&domain)
+
+	return &domain, nil
+}
+
+// lookupString returns the string at a dot separated path in a JSON object
+func lookupString(payload map[string]interface{}, path string) string {
+	var current interface{} = payload
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = obj[key]
+	}
+
+	switch v := current.(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return ""
+	}
+}
+
+// firstString returns the first non-empty string found at the given paths
+func firstString(payload map[string]interface{}, paths ...string) string {
+	for _, path := range paths {
+		if v := lookupString(payload, path); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// defaultString returns value, or fallback when value is empty
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownImporter is returned for registrar import providers that are not supported
+var ErrUnknownImporter = errors.New("unknown registrar import provider")
+
+// defaultCloudflareAPI is the Cloudflare API base URL used when none is given
+const defaultCloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+// cloudflarePageSize is the number of zones requested per page (Cloudflare allows up to 50)
+const cloudflarePageSize = 50
+
+// RegistrarImporter lists the domains held in a registrar account
+type RegistrarImporter interface {
+	ListDomains() ([]string, error)
+}
+
+// NewRegistrarImporter creates an importer for the given provider.
+//
+// Supported providers:
+//   - cloudflare: lists account zones; credentials api_token, optional account_id and api_url
+func NewRegistrarImporter(provider string, credentials map[string]string) (RegistrarImporter, error) {
+	switch provider {
+	case "cloudflare":
+		if credentials["api_token"] == "" {
+			return nil, fmt.Errorf("cloudflare import requires api_token")
+		}
+		return &CloudflareImporter{
+			APIToken:  credentials["api_token"],
+			AccountID: credentials["account_id"],
+			BaseURL:   defaultString(credentials["api_url"], defaultCloudflareAPI),
+			client:    &http.Client{Timeout: 30 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownImporter, provider)
+	}
+}
+
+// CloudflareImporter imports the zones of a Cloudflare account
+type CloudflareImporter struct {
+	APIToken  string
+	AccountID string // Optional: only list zones of this account
+	BaseURL   string
+	client    *http.Client
+}
+
+// cloudflareZonesResponse represents a page of the Cloudflare zone list
+type cloudflareZonesResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Result []struct {
+		Name string `json:"name"`
+	} `json:"result"`
+	ResultInfo struct {
+		Page       int `json:"page"`
+		TotalPages int `json:"total_pages"`
+	} `json:"result_info"`
+}
+
+// ListDomains returns the names of all zones, following pagination
+func (c *CloudflareImporter) ListDomains() ([]string, error) {
+	domains := make([]string, 0)
+
+	for page := 1; ; page++ {
+		result, err := c.listZones(page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, zone := range result.Result {
+			domains = append(domains, strings.ToLower(zone.Name))
+		}
+
+		if page >= result.ResultInfo.TotalPages || len(result.Result) == 0 {
+			return domains, nil
+		}
+	}
+}
+
+// listZones fetches a single page of zones
+func (c *CloudflareImporter) listZones(page int) (*cloudflareZonesResponse, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("per_page", strconv.Itoa(cloudflarePageSize))
+	if c.AccountID != "" {
+		query.Set("account.id", c.AccountID)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(c.BaseURL, "/")+"/zones?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Cloudflare API URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Cloudflare zones: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result cloudflareZonesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to parse Cloudflare response (status %d): %w", resp.StatusCode, err)
+	}
+
+	if !result.Success || resp.StatusCode != http.StatusOK {
+		messages := make([]string, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			messages = append(messages, fmt.Sprintf("%d %s", e.Code, e.Message))
+		}
+		return nil, fmt.Errorf("Cloudflare API returned status %d: %s", resp.StatusCode, strings.Join(messages, "; "))
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UptimeResult represents the outcome of an uptime check
+type UptimeResult struct {
+	StatusCode int           // HTTP status code, 0 if there was no response
+	Latency    time.Duration // Time until the response headers arrived
+	Error      string        // Why the site is considered down
+}
+
+// Up reports whether the site responded with a success or redirect status
+func (r *UptimeResult) Up() bool {
+	return r.Error == ""
+}
+
+// UptimeService checks that the sites of monitored domains are reachable
+type UptimeService struct {
+	Client           *http.Client
+	Method           string // GET or HEAD
+	Concurrency      int
+	FailureThreshold int // Consecutive failed checks before a site is considered down
+	notifyService    *NotifyService
+}
+
+// NewUptimeService creates a new uptime service
+func NewUptimeService(cfg *config.UptimeConfig, notifyService *NotifyService) (*UptimeService, error) {
+	method := strings.ToUpper(cfg.Method)
+	switch method {
+	case "":
+		method = http.MethodGet
+	case http.MethodGet, http.MethodHead:
+	default:
+		return nil, fmt.Errorf("invalid method %q (GET or HEAD)", cfg.Method)
+	}
+
+	timeout := 10 * time.Second
+	if cfg.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+	}
+
+	concurrency := cfg.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	failureThreshold := cfg.FailureThreshold
+	if failureThreshold <= 0 {
+		failureThreshold = 2
+	}
+
+	return &UptimeService{
+		Client:           &http.Client{Timeout: timeout},
+		Method:           method,
+		Concurrency:      concurrency,
+		FailureThreshold: failureThreshold,
+		notifyService:    notifyService,
+	}, nil
+}
+
+// CheckURL requests the URL and reports whether the site is up. Status codes of 400 and
+// above, connection errors and timeouts count as down.
+func (s *UptimeService) CheckURL(ctx context.Context, url string) *UptimeResult {
+	req, err := http.NewRequestWithContext(ctx, s.Method, url, nil)
+	if err != nil {
+		return &UptimeResult{Error: err.Error()}
+	}
+	req.Header.Set("User-Agent", "domain-monitor/uptime")
+
+	start := time.Now()
+	resp, err := s.Client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return &UptimeResult{Latency: latency, Error: err.Error()}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	result := &UptimeResult{StatusCode: resp.StatusCode, Latency: latency}
+	if resp.StatusCode >= http.StatusBadRequest {
+		result.Error = "HTTP " + resp.Status
+	}
+	return result
+}
+
+// CheckAllUptime checks the sites of all active domains with uptime checks enabled.
+// Paused domains and domains pending deletion are skipped.
+func (s *UptimeService) CheckAllUptime(ctx context.Context) error {
+	var domains []models.Domain
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Omit("raw_whois").Where("is_active = ? AND uptime_enabled = ? AND delete_requested_by = ?", true, true, "").Find(&domains).Error
+	}); err != nil {
+		return fmt.Errorf("failed to fetch domains: %w", err)
+	}
+
+	now := time.Now()
+	domains = slices.DeleteFunc(domains, func(domain models.Domain) bool { return domain.IsPaused(now) })
+	if len(domains) == 0 {
+		return nil
+	}
+
+	slog.Debug("Checking uptime", "count", len(domains), "concurrency", s.Concurrency)
+
+	runPool(domains, s.Concurrency, func(domain models.Domain) {
+		if ctx.Err() != nil {
+			return
+		}
+		s.CheckDomain(ctx, &domain)
+	})
+	return nil
+}
+
+// CheckDomain checks the site of a domain, records the result and alerts when the site
+// goes down or recovers. A site is only considered down after FailureThreshold failed
+// checks in a row, so a single failed request doesn't cause a pair of alerts.
+func (s *UptimeService) CheckDomain(ctx context.Context, domain *models.Domain) *UptimeResult {
+	result := s.CheckURL(ctx, domain.UptimeCheckURL())
+	if ctx.Err() != nil {
+		return result
+	}
+
+	previous := domain.UptimeStatus
+	if result.Up() {
+		domain.UptimeStatus = models.UptimeUp
+		domain.UptimeFailures = 0
+	} else if domain.UptimeFailures++; domain.UptimeFailures >= s.FailureThreshold {
+		domain.UptimeStatus = models.UptimeDown
+	}
+	domain.LastHTTPStatus = result.StatusCode
+	domain.LastLatencyMs = result.Latency.Milliseconds()
+	domain.UptimeError = result.Error
+	domain.LastUptimeCheck = models.NullTime(time.Now())
+
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Model(&models.Domain{}).Where("id = ?", domain.ID).UpdateColumns(map[string]interface{}{
+			"uptime_status":     domain.UptimeStatus,
+			"last_http_status":  domain.LastHTTPStatus,
+			"last_latency_ms":   domain.LastLatencyMs,
+			"uptime_error":      domain.UptimeError,
+			"uptime_failures":   domain.UptimeFailures,
+			"last_uptime_check": domain.LastUptimeCheck,
+		}).Error
+	}); err != nil {
+		slog.Error("Failed to save uptime result", "domain", domain.Name, "error", err)
+		return result
+	}
+
+	// A site that is down on its first check is reported too; a first successful check isn't
+	if domain.UptimeStatus != previous && (domain.UptimeStatus == models.UptimeDown || previous == models.UptimeDown) {
+		s.notifyTransition(domain, result)
+	}
+	return result
+}
+
+// notifyTransition sends an alert that the site of a domain went down or recovered
+func (s *UptimeService) notifyTransition(domain *models.Domain, result *UptimeResult) {
+	url := domain.UptimeCheckURL()
+	m := s.notifyService.Messages()
+
+	alert := &Alert{
+		Kind:          AlertUptime,
+		Domain:        domain,
+		DaysRemaining: domain.DaysRemaining,
+	}
+	if domain.UptimeStatus == models.UptimeDown {
+		slog.Warn("Site is down", "domain", domain.Name, "url", url, "status_code", result.StatusCode, "error", result.Error)
+		alert.Severity = SeverityCritical
+		alert.Title = m.UptimeDownTitle
+		alert.Message = fmt.Sprintf(m.UptimeDownMessage, url, result.Error)
+	} else {
+		slog.Info("Site is up again", "domain", domain.Name, "url", url, "status_code", result.StatusCode)
+		alert.Severity = SeverityInfo
+		alert.Title = m.UptimeUpTitle
+		alert.Message = fmt.Sprintf(m.UptimeUpMessage, url, result.StatusCode, result.Latency.Milliseconds())
+	}
+
+	if s.notifyService == nil || !domain.NotifyEnabled {
+		return
+	}
+	if err := s.notifyService.SendAlert(alert); err != nil {
+		slog.Error("Failed to send uptime notification", "domain", domain.Name, "error", err)
+	}
+}
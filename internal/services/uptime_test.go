@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// testSite is a web site whose response status can be changed between checks
+type testSite struct {
+	*httptest.Server
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func newTestSite(t *testing.T) *testSite {
+	t.Helper()
+	site := &testSite{}
+	site.status.Store(http.StatusOK)
+	site.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.requests.Add(1)
+		w.WriteHeader(int(site.status.Load()))
+	}))
+	t.Cleanup(site.Close)
+	return site
+}
+
+// newTestUptime returns an uptime service alerting through a fake channel and a domain
+// whose site is served by site
+func newTestUptime(t *testing.T, cfg config.UptimeConfig, site *testSite) (*UptimeService, *fakeNotifier, *models.Domain) {
+	t.Helper()
+	notify := NewNotifyService(&config.NotificationsConfig{})
+	channel := &fakeNotifier{channel: "webhook"}
+	notify.notifiers = []Notifier{channel}
+
+	uptime, err := NewUptimeService(&cfg, notify)
+	if err != nil {
+		t.Fatalf("NewUptimeService: %v", err)
+	}
+
+	domain := createTestDomain(t, "example.com")
+	domain.UptimeEnabled = true
+	domain.UptimeURL = site.URL
+	database.DB.Model(domain).Updates(map[string]interface{}{"uptime_enabled": true, "uptime_url": site.URL})
+	return uptime, channel, domain
+}
+
+func TestUptimeTransitions(t *testing.T) {
+	setupTestDB(t)
+	site := newTestSite(t)
+	uptime, channel, domain := newTestUptime(t, config.UptimeConfig{FailureThreshold: 1}, site)
+	ctx := context.Background()
+
+	steps := []struct {
+		name       string
+		status     int
+		wantStatus string
+		wantAlerts int
+	}{
+		{"up", http.StatusOK, models.UptimeUp, 0},
+		{"down", http.StatusServiceUnavailable, models.UptimeDown, 1},
+		{"still down", http.StatusBadGateway, models.UptimeDown, 1},
+		{"recovered", http.StatusOK, models.UptimeUp, 2},
+	}
+	for _, step := range steps {
+		site.status.Store(int32(step.status))
+		result := uptime.CheckDomain(ctx, domain)
+		if result.StatusCode != step.status || result.Up() != (step.status < 400) {
+			t.Errorf("%s: result = %+v", step.name, result)
+		}
+
+		var stored models.Domain
+		database.DB.First(&stored, domain.ID)
+		if stored.UptimeStatus != step.wantStatus || stored.LastHTTPStatus != step.status || stored.LastUptimeCheck == nil {
+			t.Errorf("%s: stored status %q, HTTP %d, checked %v; want %q, HTTP %d", step.name, stored.UptimeStatus, stored.LastHTTPStatus, stored.LastUptimeCheck, step.wantStatus, step.status)
+		}
+		if alerts := channel.sentKind(AlertUptime); len(alerts) != step.wantAlerts {
+			t.Errorf("%s: %d uptime alerts, want %d", step.name, len(alerts), step.wantAlerts)
+		}
+	}
+
+	alerts := channel.sentKind(AlertUptime)
+	if len(alerts) == 2 && (alerts[0].Severity != SeverityCritical || alerts[1].Severity != SeverityInfo) {
+		t.Errorf("alert severities = %s, %s; want critical then info", alerts[0].Severity, alerts[1].Severity)
+	}
+}
+
+func TestUptimeFailureThreshold(t *testing.T) {
+	setupTestDB(t)
+	site := newTestSite(t)
+	uptime, channel, domain := newTestUptime(t, config.UptimeConfig{}, site)
+	ctx := context.Background()
+
+	uptime.CheckDomain(ctx, domain)
+	site.status.Store(http.StatusInternalServerError)
+
+	// One failed request isn't enough to call the site down
+	uptime.CheckDomain(ctx, domain)
+	if domain.UptimeStatus != models.UptimeUp || len(channel.sentKind(AlertUptime)) != 0 {
+		t.Fatalf("status = %q after one failure, want still up without an alert", domain.UptimeStatus)
+	}
+	uptime.CheckDomain(ctx, domain)
+	if domain.UptimeStatus != models.UptimeDown || len(channel.sentKind(AlertUptime)) != 1 {
+		t.Errorf("status = %q after two failures, want down with one alert", domain.UptimeStatus)
+	}
+}
+
+func TestUptimeConnectionFailure(t *testing.T) {
+	setupTestDB(t)
+	site := newTestSite(t)
+	uptime, channel, domain := newTestUptime(t, config.UptimeConfig{FailureThreshold: 1}, site)
+	site.Close()
+
+	result := uptime.CheckDomain(context.Background(), domain)
+	if result.Up() || result.StatusCode != 0 || result.Error == "" {
+		t.Errorf("result = %+v, want down without a status code", result)
+	}
+	// Down on the first check is reported too
+	if alerts := channel.sentKind(AlertUptime); len(alerts) != 1 {
+		t.Errorf("%d uptime alerts, want 1", len(alerts))
+	}
+}
+
+func TestCheckAllUptimeOnlyEnabled(t *testing.T) {
+	setupTestDB(t)
+	site := newTestSite(t)
+	uptime, _, _ := newTestUptime(t, config.UptimeConfig{}, site)
+	other := createTestDomain(t, "example.org")
+	database.DB.Model(other).Update("uptime_url", site.URL)
+
+	if err := uptime.CheckAllUptime(context.Background()); err != nil {
+		t.Fatalf("CheckAllUptime: %v", err)
+	}
+	if got := site.requests.Load(); got != 1 {
+		t.Errorf("site requested %d times, want only for the enabled domain", got)
+	}
+}
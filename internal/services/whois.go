@@ -1,11 +1,24 @@
 package services
 
 import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/database"
+	"domain-monitor/internal/metrics"
+	"domain-monitor/internal/models"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
 )
 
 // DomainInfo represents WHOIS query result
@@ -17,25 +30,254 @@ type DomainInfo struct {
 	UpdatedDate time.Time `json:"updated_date"`
 	Status      string    `json:"status"`
 	NameServers []string  `json:"name_servers"`
-	RawData     string    `json:"raw_data"`
+	RawData     string    `json:"raw_data,omitempty"`
 }
 
 // WhoisService handles WHOIS queries
 type WhoisService struct {
-	APIURL  string
-	Timeout time.Duration
+	Mode       string // api or native
+	APIURL     string
+	Timeout    time.Duration
+	EnableRDAP bool
+	RDAPURL    string
+	DBCacheTTL time.Duration // Zero disables the database-backed cache
+	MaxSize    int64         // Maximum response size in bytes
+	TLDs       []string      // Supported TLDs (empty = not restricted)
+	NotFound   notFoundRules // API responses meaning the domain is not registered
+	client     *http.Client  // Shared client so connections are reused across queries
+	limiter    *rate.Limiter // Paces live queries (nil = unlimited)
+	cache      *whoisCache   // In-memory results (nil = disabled)
 }
 
+// defaultMaxResponseSize caps WHOIS responses unless configured otherwise
+const defaultMaxResponseSize = 1 << 20
+
+// ErrUnsupportedTLD is returned when the WHOIS provider doesn't cover a domain's TLD
+var ErrUnsupportedTLD = errors.New("TLD is not supported by the WHOIS provider")
+
+// ErrDomainNotFound is returned when the WHOIS API reports that the domain is not registered
+var ErrDomainNotFound = errors.New("domain is not registered")
+
+// notFoundRules match WHOIS API errors that mean the domain is not registered
+type notFoundRules struct {
+	Codes    []int
+	Messages []string
+}
+
+// match reports whether an API error code and message mean "not found"
+func (r notFoundRules) match(code int, msg string) bool {
+	for _, c := range r.Codes {
+		if c == code {
+			return true
+		}
+	}
+	msg = strings.ToLower(msg)
+	for _, m := range r.Messages {
+		if m != "" && strings.Contains(msg, strings.ToLower(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrResponseTooLarge is returned when a WHOIS response exceeds the configured size
+var ErrResponseTooLarge = errors.New("WHOIS response exceeds the maximum size")
+
 // NewWhoisService creates a new WHOIS service
-func NewWhoisService(apiURL string, timeout time.Duration) *WhoisService {
+func NewWhoisService(cfg *config.WhoisConfig) *WhoisService {
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		timeout = 30 * time.Second
+	}
+
+	rdapURL := cfg.RDAPURL
+	if rdapURL == "" {
+		rdapURL = defaultRDAPURL
+	}
+
+	var dbCacheTTL time.Duration
+	if cfg.DBCacheTTL != "" {
+		if dbCacheTTL, err = time.ParseDuration(cfg.DBCacheTTL); err != nil {
+			slog.Warn("Invalid whois.db_cache_ttl, database cache disabled", "value", cfg.DBCacheTTL, "error", err)
+			dbCacheTTL = 0
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+
+	mode := cfg.Mode
+	if mode == "" {
+		mode = "api"
+	}
+
+	maxSize := cfg.MaxResponseSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxResponseSize
+	}
+
+	cacheTTL := defaultWhoisCacheTTL
+	if cfg.CacheTTL != "" {
+		if cacheTTL, err = time.ParseDuration(cfg.CacheTTL); err != nil {
+			slog.Warn("Invalid whois.cache_ttl, using the default", "value", cfg.CacheTTL, "default", defaultWhoisCacheTTL.String(), "error", err)
+			cacheTTL = defaultWhoisCacheTTL
+		}
+	}
+
+	var limiter *rate.Limiter
+	if cfg.RateLimit > 0 {
+		// Allow a one second burst so the pool workers don't all wait for the first token
+		limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), max(1, int(cfg.RateLimit)))
+	}
+
 	return &WhoisService{
-		APIURL:  apiURL,
-		Timeout: timeout,
+		Mode:       mode,
+		APIURL:     cfg.APIURL,
+		Timeout:    timeout,
+		EnableRDAP: cfg.EnableRDAP,
+		RDAPURL:    rdapURL,
+		DBCacheTTL: dbCacheTTL,
+		MaxSize:    maxSize,
+		TLDs:       cfg.SupportedTLDs,
+		NotFound:   notFoundRules{Codes: cfg.NotFoundCodes, Messages: cfg.NotFoundMessages},
+		limiter:    limiter,
+		cache:      newWhoisCache(cacheTTL),
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
 	}
 }
 
-// QueryDomain queries WHOIS information for a domain
+// QueryDomain queries WHOIS information for a domain.
+// Fresh results from the in-memory cache or the database cache are returned when
+// available; live queries are paced by whois.rate_limit.
 func (s *WhoisService) QueryDomain(domain string) (*DomainInfo, error) {
+	return s.QueryDomainContext(context.Background(), domain)
+}
+
+// QueryDomainContext is QueryDomain with a context that cancels the query
+func (s *WhoisService) QueryDomainContext(ctx context.Context, domain string) (*DomainInfo, error) {
+	return s.query(ctx, domain, false)
+}
+
+// QueryDomainLive queries WHOIS information bypassing the caches, and refreshes them
+func (s *WhoisService) QueryDomainLive(ctx context.Context, domain string) (*DomainInfo, error) {
+	return s.query(ctx, domain, true)
+}
+
+// query queries WHOIS information, skipping the caches when bypass is set
+func (s *WhoisService) query(ctx context.Context, domain string, bypass bool) (*DomainInfo, error) {
+	if !bypass {
+		if info, ok := s.cache.get(domain); ok {
+			return info, nil
+		}
+		if s.DBCacheTTL > 0 {
+			if info := s.loadSnapshot(domain); info != nil {
+				s.cache.set(domain, info)
+				return info, nil
+			}
+		}
+	}
+
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("WHOIS rate limit: %w", err)
+		}
+	}
+
+	info, err := s.queryLive(ctx, domain)
+	if err != nil && !errors.Is(err, ErrDomainNotFound) {
+		metrics.WhoisQueries.Inc("error")
+	} else {
+		metrics.WhoisQueries.Inc("success")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.set(domain, info)
+	if s.DBCacheTTL > 0 {
+		s.saveSnapshot(info)
+	}
+
+	return info, nil
+}
+
+// CheckTLD reports whether the domain's TLD is covered by the WHOIS provider.
+// The configured TLD list is used when set; otherwise native mode checks that a registry
+// WHOIS server can be found, while the API mode can't tell and accepts every TLD.
+func (s *WhoisService) CheckTLD(domain string) error {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if len(s.TLDs) > 0 {
+		for _, tld := range s.TLDs {
+			tld = strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+			if tld != "" && strings.HasSuffix(domain, "."+tld) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s", ErrUnsupportedTLD, domain[strings.LastIndex(domain, ".")+1:])
+	}
+
+	if s.Mode == "native" {
+		if _, err := s.whoisServer(context.Background(), domain); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnsupportedTLD, err)
+		}
+	}
+	return nil
+}
+
+// queryLive queries the WHOIS API or the registry WHOIS server, depending on the mode.
+// When RDAP is enabled it is used as a fallback if the query fails or returns no expiry date.
+func (s *WhoisService) queryLive(ctx context.Context, domain string) (*DomainInfo, error) {
+	var info *DomainInfo
+	var err error
+	if s.Mode == "native" {
+		info, err = s.queryNative(ctx, domain)
+	} else {
+		info, err = s.queryAPI(ctx, domain)
+	}
+	if !s.EnableRDAP || errors.Is(err, ErrDomainNotFound) || ctx.Err() != nil || (err == nil && !info.ExpiryDate.IsZero()) {
+		return info, err
+	}
+
+	rdapInfo, rdapErr := s.queryRDAP(ctx, domain)
+	if rdapErr != nil {
+		if err != nil {
+			return nil, fmt.Errorf("%w (RDAP fallback failed: %v)", err, rdapErr)
+		}
+		return info, nil
+	}
+
+	return rdapInfo, nil
+}
+
+// readLimited reads a response, failing with ErrResponseTooLarge beyond MaxSize bytes
+func (s *WhoisService) readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.MaxSize {
+		return nil, fmt.Errorf("%w (%d bytes)", ErrResponseTooLarge, s.MaxSize)
+	}
+	return data, nil
+}
+
+// queryAPI queries the configured WHOIS HTTP API
+func (s *WhoisService) queryAPI(ctx context.Context, domain string) (*DomainInfo, error) {
 	// Build API URL with parameters
 	apiURL, err := url.Parse(s.APIURL)
 	if err != nil {
@@ -46,13 +288,13 @@ func (s *WhoisService) QueryDomain(domain string) (*DomainInfo, error) {
 	params.Add("domain", domain)
 	apiURL.RawQuery = params.Encode()
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: s.Timeout,
+	// Send GET request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API URL: %w", err)
 	}
 
-	// Send GET request
-	resp, err := client.Get(apiURL.String())
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query WHOIS: %w", err)
 	}
@@ -69,13 +311,21 @@ func (s *WhoisService) QueryDomain(domain string) (*DomainInfo, error) {
 		Data map[string]interface{} `json:"data"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
+	body, err := s.readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read WHOIS response: %w", err)
+	}
+
+	if err := json.Unmarshal(body, &apiResponse); err != nil {
 		return nil, fmt.Errorf("failed to parse WHOIS response: %w", err)
 	}
 
 	// Check if query was successful
 	if apiResponse.Code != 0 {
-		return nil, fmt.Errorf("WHOIS API error: %s", apiResponse.Msg)
+		if s.NotFound.match(apiResponse.Code, apiResponse.Msg) {
+			return nil, fmt.Errorf("%w: %s", ErrDomainNotFound, apiResponse.Msg)
+		}
+		return nil, fmt.Errorf("WHOIS API error (code %d): %s", apiResponse.Code, apiResponse.Msg)
 	}
 
 	result := apiResponse.Data
@@ -138,20 +388,92 @@ func (s *WhoisService) QueryDomain(domain string) (*DomainInfo, error) {
 	return domainInfo, nil
 }
 
-// parseDate tries to parse various date formats
+// loadSnapshot returns the cached WHOIS result if it is still fresh
+func (s *WhoisService) loadSnapshot(domain string) *DomainInfo {
+	var snapshot models.WhoisSnapshot
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Where("domain = ? AND fetched_at > ?", domain, time.Now().Add(-s.DBCacheTTL)).First(&snapshot).Error
+	}); err != nil {
+		return nil
+	}
+
+	var info DomainInfo
+	if err := json.Unmarshal([]byte(snapshot.Data), &info); err != nil {
+		return nil
+	}
+
+	return &info
+}
+
+// saveSnapshot stores a live WHOIS result in the database cache
+func (s *WhoisService) saveSnapshot(info *DomainInfo) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+
+	snapshot := models.WhoisSnapshot{
+		Domain:    info.Domain,
+		Data:      string(data),
+		FetchedAt: time.Now(),
+	}
+	if err := database.WithRetry(func(db *gorm.DB) error {
+		return db.Save(&snapshot).Error
+	}); err != nil {
+		slog.Error("Failed to cache WHOIS result", "domain", info.Domain, "error", err)
+	}
+}
+
+// dateFormats are the date layouts returned by WHOIS APIs, RDAP and registry WHOIS servers.
+// Month names match case-insensitively, so "15-JAN-2025" parses too. Layouts without a zone
+// are taken as UTC.
+var dateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006.01.02 15:04:05",
+	"2006.01.02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"02.01.2006 15:04:05",
+	"02.01.2006",
+	"02-Jan-2006 15:04:05 MST",
+	"02-Jan-2006 15:04:05",
+	"2-Jan-2006",
+	"02 Jan 2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"20060102",
+	"2006年01月02日",
+	time.RFC1123,
+	time.RFC1123Z,
+	time.RFC850,
+	time.UnixDate,
+	time.ANSIC,
+}
+
+// parseDate tries the known date formats and returns the time in UTC
 func parseDate(dateStr string) (time.Time, error) {
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02T15:04:05Z",
-		"2006-01-02 15:04:05",
-		"2006-01-02",
+	value := strings.TrimSpace(dateStr)
+	// Some registries append the layout, e.g. "2025-01-15 (YYYY-MM-DD)"
+	if i := strings.Index(value, " ("); i > 0 {
+		value = value[:i]
 	}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t, nil
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t.UTC(), nil
 		}
 	}
 
+	slog.Debug("Unrecognized WHOIS date format", "date", dateStr)
 	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
 }
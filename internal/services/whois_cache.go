@@ -0,0 +1,75 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultWhoisCacheTTL is how long WHOIS results are reused in memory by default
+const defaultWhoisCacheTTL = 12 * time.Hour
+
+// whoisCacheEntry is a cached WHOIS result
+type whoisCacheEntry struct {
+	info      *DomainInfo
+	fetchedAt time.Time
+}
+
+// whoisCache is an in-memory, concurrency-safe cache of WHOIS results
+type whoisCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]whoisCacheEntry
+}
+
+// newWhoisCache creates a cache keeping results for ttl, or nil when ttl is not positive
+func newWhoisCache(ttl time.Duration) *whoisCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &whoisCache{
+		ttl:     ttl,
+		entries: make(map[string]whoisCacheEntry),
+	}
+}
+
+// get returns a copy of the cached result if it is still fresh
+func (c *whoisCache) get(domain string) (*DomainInfo, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[domain]
+	if !ok {
+		return nil, false
+	}
+	if time.Since(entry.fetchedAt) >= c.ttl {
+		delete(c.entries, domain)
+		return nil, false
+	}
+
+	info := *entry.info
+	return &info, true
+}
+
+// set stores a result, dropping expired entries so the cache stays bounded
+func (c *whoisCache) set(domain string, info *DomainInfo) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for name, entry := range c.entries {
+		if now.Sub(entry.fetchedAt) >= c.ttl {
+			delete(c.entries, name)
+		}
+	}
+
+	stored := *info
+	c.entries[domain] = whoisCacheEntry{info: &stored, fetchedAt: now}
+}
@@ -0,0 +1,198 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Native WHOIS settings
+const (
+	whoisPort       = "43"
+	ianaWhoisServer = "whois.iana.org"
+)
+
+// whoisServers maps TLDs to their registry WHOIS servers
+var whoisServers = map[string]string{
+	"com":  "whois.verisign-grs.com",
+	"net":  "whois.verisign-grs.com",
+	"org":  "whois.pir.org",
+	"cn":   "whois.cnnic.cn",
+	"io":   "whois.nic.io",
+	"co":   "whois.nic.co",
+	"me":   "whois.nic.me",
+	"cc":   "ccwhois.verisign-grs.com",
+	"tv":   "tvwhois.verisign-grs.com",
+	"biz":  "whois.nic.biz",
+	"info": "whois.nic.info",
+	"xyz":  "whois.nic.xyz",
+	"top":  "whois.nic.top",
+	"app":  "whois.nic.google",
+	"dev":  "whois.nic.google",
+	"ai":   "whois.nic.ai",
+	"uk":   "whois.nic.uk",
+	"de":   "whois.denic.de",
+	"jp":   "whois.jprs.jp",
+}
+
+// referralCache remembers WHOIS servers discovered through IANA referrals
+var referralCache sync.Map
+
+// notFoundMarkers are response fragments meaning the domain is not registered
+var notFoundMarkers = []string{
+	"no match for",
+	"not found",
+	"no matching record",
+	"no data found",
+	"no entries found",
+	"status: free",
+}
+
+// QueryDomainNative queries the registry WHOIS server of the domain over TCP port 43
+func (s *WhoisService) QueryDomainNative(domain string) (*DomainInfo, error) {
+	return s.queryNative(context.Background(), domain)
+}
+
+// queryNative queries the registry WHOIS server of the domain, cancelled with the context
+func (s *WhoisService) queryNative(ctx context.Context, domain string) (*DomainInfo, error) {
+	server, err := s.whoisServer(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := s.queryWhoisServer(ctx, server, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	domainInfo := parseWhoisText(domain, raw)
+
+	// Only trust not-found markers when nothing was parsed, since legal notices may contain them
+	if domainInfo.ExpiryDate.IsZero() {
+		lower := strings.ToLower(raw)
+		for _, marker := range notFoundMarkers {
+			if strings.Contains(lower, marker) {
+				return nil, fmt.Errorf("domain %s not found on %s", domain, server)
+			}
+		}
+	}
+
+	return domainInfo, nil
+}
+
+// whoisServer returns the WHOIS server for the TLD of the domain
+func (s *WhoisService) whoisServer(ctx context.Context, domain string) (string, error) {
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	if tld == "" {
+		return "", fmt.Errorf("invalid domain: %s", domain)
+	}
+
+	if server, ok := whoisServers[tld]; ok {
+		return server, nil
+	}
+	if server, ok := referralCache.Load(tld); ok {
+		return server.(string), nil
+	}
+
+	// Ask IANA for the registry WHOIS server of the TLD
+	raw, err := s.queryWhoisServer(ctx, ianaWhoisServer, tld)
+	if err == nil {
+		for _, line := range strings.Split(raw, "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "whois") {
+				if server := strings.TrimSpace(value); server != "" {
+					referralCache.Store(tld, server)
+					return server, nil
+				}
+			}
+		}
+	}
+
+	// Most registries follow the whois.nic.<tld> convention
+	return "whois.nic." + tld, nil
+}
+
+// queryWhoisServer sends a query to a WHOIS server and returns the raw response
+func (s *WhoisService) queryWhoisServer(ctx context.Context, server, query string) (string, error) {
+	dialer := &net.Dialer{Timeout: s.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(server, whoisPort))
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.Timeout)); err != nil {
+		return "", err
+	}
+
+	// Unblock reads and writes when the context is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := conn.Write([]byte(query + "\r\n")); err != nil {
+		return "", fmt.Errorf("failed to send query to %s: %w", server, err)
+	}
+
+	data, err := s.readLimited(conn)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("query to %s cancelled: %w", server, ctx.Err())
+		}
+		return "", fmt.Errorf("failed to read response from %s: %w", server, err)
+	}
+
+	return string(data), nil
+}
+
+// parseWhoisText extracts domain information from a raw WHOIS response
+func parseWhoisText(domain, raw string) *DomainInfo {
+	domainInfo := &DomainInfo{
+		Domain:  domain,
+		RawData: raw,
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(raw))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case "registrar", "sponsoring registrar":
+			if domainInfo.Registrar == "" {
+				domainInfo.Registrar = value
+			}
+		case "registry expiry date", "registrar registration expiration date", "expiration time", "expiry date", "expiration date", "paid-till":
+			if t, err := parseDate(value); err == nil && domainInfo.ExpiryDate.IsZero() {
+				domainInfo.ExpiryDate = t
+			}
+		case "creation date", "registration time", "created":
+			if t, err := parseDate(value); err == nil && domainInfo.CreatedDate.IsZero() {
+				domainInfo.CreatedDate = t
+			}
+		case "updated date", "last updated", "changed":
+			if t, err := parseDate(value); err == nil && domainInfo.UpdatedDate.IsZero() {
+				domainInfo.UpdatedDate = t
+			}
+		case "domain status", "status":
+			if domainInfo.Status == "" {
+				// Drop the ICANN reference URL after the status code
+				domainInfo.Status = strings.Fields(value)[0]
+			}
+		case "name server", "nserver":
+			domainInfo.NameServers = append(domainInfo.NameServers, strings.ToLower(strings.Fields(value)[0]))
+		}
+	}
+
+	return domainInfo
+}
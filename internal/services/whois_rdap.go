@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// defaultRDAPURL is the RDAP bootstrap redirector used when none is configured
+const defaultRDAPURL = "https://rdap.org/domain/"
+
+// rdapResponse represents the parts of an RDAP domain response that are used
+type rdapResponse struct {
+	LDHName string   `json:"ldhName"`
+	Status  []string `json:"status"`
+	Events  []struct {
+		EventAction string `json:"eventAction"`
+		EventDate   string `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string        `json:"roles"`
+		VCardArray json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+	Nameservers []struct {
+		LDHName string `json:"ldhName"`
+	} `json:"nameservers"`
+}
+
+// QueryRDAP queries RDAP information for a domain
+func (s *WhoisService) QueryRDAP(domain string) (*DomainInfo, error) {
+	return s.queryRDAP(context.Background(), domain)
+}
+
+// queryRDAP queries RDAP information for a domain, cancelled with the context
+func (s *WhoisService) queryRDAP(ctx context.Context, domain string) (*DomainInfo, error) {
+	rdapURL := strings.TrimSuffix(s.RDAPURL, "/") + "/" + url.PathEscape(domain)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rdapURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RDAP URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query RDAP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RDAP server returned status %d", resp.StatusCode)
+	}
+
+	body, err := s.readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RDAP response: %w", err)
+	}
+
+	return parseRDAP(domain, body)
+}
+
+// parseRDAP maps an RDAP domain response into DomainInfo
+func parseRDAP(domain string, body []byte) (*DomainInfo, error) {
+	var result rdapResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse RDAP response: %w", err)
+	}
+
+	domainInfo := &DomainInfo{
+		Domain:  domain,
+		RawData: string(body),
+	}
+
+	if len(result.Status) > 0 {
+		domainInfo.Status = result.Status[0]
+	}
+
+	for _, event := range result.Events {
+		t, err := parseDate(event.EventDate)
+		if err != nil {
+			continue
+		}
+		switch event.EventAction {
+		case "expiration":
+			domainInfo.ExpiryDate = t
+		case "registration":
+			domainInfo.CreatedDate = t
+		case "last changed":
+			domainInfo.UpdatedDate = t
+		}
+	}
+
+	for _, entity := range result.Entities {
+		for _, role := range entity.Roles {
+			if role == "registrar" {
+				domainInfo.Registrar = vcardName(entity.VCardArray)
+			}
+		}
+	}
+
+	for _, ns := range result.Nameservers {
+		if ns.LDHName != "" {
+			domainInfo.NameServers = append(domainInfo.NameServers, strings.ToLower(ns.LDHName))
+		}
+	}
+
+	return domainInfo, nil
+}
+
+// vcardName extracts the "fn" property from a jCard array
+func vcardName(raw json.RawMessage) string {
+	var vcard []interface{}
+	if err := json.Unmarshal(raw, &vcard); err != nil || len(vcard) < 2 {
+		return ""
+	}
+
+	properties, ok := vcard[1].([]interface{})
+	if !ok {
+		return ""
+	}
+
+	for _, p := range properties {
+		property, ok := p.([]interface{})
+		if !ok || len(property) < 4 {
+			continue
+		}
+		if name, _ := property[0].(string); name == "fn" {
+			value, _ := property[3].(string)
+			return value
+		}
+	}
+
+	return ""
+}
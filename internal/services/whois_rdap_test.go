@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rdapFixture is a recorded RDAP response for example.com
+func rdapFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/rdap_example.com.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseRDAP(t *testing.T) {
+	info, err := parseRDAP("example.com", rdapFixture(t))
+	if err != nil {
+		t.Fatalf("parseRDAP: %v", err)
+	}
+
+	if want := time.Date(2026, 8, 13, 4, 0, 0, 0, time.UTC); !info.ExpiryDate.Equal(want) {
+		t.Errorf("ExpiryDate = %v, want %v", info.ExpiryDate, want)
+	}
+	if want := time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC); !info.CreatedDate.Equal(want) {
+		t.Errorf("CreatedDate = %v, want %v", info.CreatedDate, want)
+	}
+	if want := time.Date(2025, 8, 14, 7, 1, 39, 0, time.UTC); !info.UpdatedDate.Equal(want) {
+		t.Errorf("UpdatedDate = %v, want %v", info.UpdatedDate, want)
+	}
+	if info.Registrar != "RESERVED-Internet Assigned Numbers Authority" {
+		t.Errorf("Registrar = %q", info.Registrar)
+	}
+	if info.Status != "client delete prohibited" {
+		t.Errorf("Status = %q", info.Status)
+	}
+	if want := []string{"a.iana-servers.net", "b.iana-servers.net"}; !slices.Equal(info.NameServers, want) {
+		t.Errorf("NameServers = %v, want %v", info.NameServers, want)
+	}
+	if info.RawData == "" {
+		t.Error("RawData is empty")
+	}
+}
+
+func TestParseRDAPInvalid(t *testing.T) {
+	if _, err := parseRDAP("example.com", []byte("<html>")); err == nil {
+		t.Error("parseRDAP accepted a non-JSON response")
+	}
+}
+
+// newRDAPTestService returns a WHOIS service using the API and RDAP test servers
+func newRDAPTestService(apiURL, rdapURL string) *WhoisService {
+	return NewWhoisService(&config.WhoisConfig{APIURL: apiURL, EnableRDAP: true, RDAPURL: rdapURL, CacheTTL: "0"})
+}
+
+func TestQueryFallsBackToRDAP(t *testing.T) {
+	fixture := rdapFixture(t)
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		if !strings.Contains(r.Header.Get("Accept"), "application/rdap+json") {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.Write(fixture)
+	}))
+	defer rdap.Close()
+
+	tests := []struct {
+		name string
+		api  http.HandlerFunc
+	}{
+		{"api error", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}},
+		{"no expiry date", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"code":0,"data":{"registrar":"Example Registrar"}}`))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := httptest.NewServer(tt.api)
+			defer api.Close()
+
+			info, err := newRDAPTestService(api.URL, rdap.URL+"/domain/").QueryDomainLive(context.Background(), "example.com")
+			if err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			if info.ExpiryDate.Year() != 2026 || info.Registrar != "RESERVED-Internet Assigned Numbers Authority" {
+				t.Errorf("got %+v, want the RDAP result", info)
+			}
+		})
+	}
+}
+
+func TestQuerySkipsRDAPWithExpiryDate(t *testing.T) {
+	rdap := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("RDAP queried although the API returned an expiry date")
+	}))
+	defer rdap.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"registrar":"Example Registrar","expirationDate":"2027-01-02T00:00:00Z"}}`))
+	}))
+	defer api.Close()
+
+	info, err := newRDAPTestService(api.URL, rdap.URL).QueryDomainLive(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if info.Registrar != "Example Registrar" {
+		t.Errorf("Registrar = %q, want the API result", info.Registrar)
+	}
+}
+
+func TestQueryRDAPFallbackFails(t *testing.T) {
+	rdap := httptest.NewServer(http.NotFoundHandler())
+	defer rdap.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer api.Close()
+
+	_, err := newRDAPTestService(api.URL, rdap.URL).QueryDomainLive(context.Background(), "example.com")
+	if err == nil || !strings.Contains(err.Error(), "RDAP fallback failed") {
+		t.Errorf("err = %v, want the API error with the RDAP failure", err)
+	}
+}
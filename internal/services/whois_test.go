@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"domain-monitor/internal/config"
+	"domain-monitor/internal/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newAPITestService returns a WHOIS service querying the fake API
+func newAPITestService(api *fakeWhoisAPI, configure func(cfg *config.WhoisConfig)) *WhoisService {
+	cfg := &config.WhoisConfig{APIURL: api.URL, CacheTTL: "0"}
+	if configure != nil {
+		configure(cfg)
+	}
+	return NewWhoisService(cfg)
+}
+
+func TestQueryRateLimit(t *testing.T) {
+	api := newFakeWhoisAPI(t)
+	api.expireIn(200)
+	s := newAPITestService(api, func(cfg *config.WhoisConfig) { cfg.RateLimit = 10 })
+
+	// A one second burst of 10 queries, then one query every 100ms
+	const queries = 15
+	var (
+		mu    sync.Mutex
+		times []time.Duration
+		wg    sync.WaitGroup
+	)
+	start := time.Now()
+	for i := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.QueryDomain(fmt.Sprintf("example%d.com", i)); err != nil {
+				t.Errorf("query: %v", err)
+			}
+			mu.Lock()
+			times = append(times, time.Since(start))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if got := api.queryCount(); got != queries {
+		t.Fatalf("API queried %d times, want %d", got, queries)
+	}
+	immediate := 0
+	for _, elapsed := range times {
+		if elapsed < 50*time.Millisecond {
+			immediate++
+		}
+	}
+	if immediate > 10 {
+		t.Errorf("%d queries ran without waiting, want at most the burst of 10", immediate)
+	}
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Errorf("%d queries took %v, want at least 500ms at 10/s after the burst", queries, elapsed)
+	}
+}
+
+func TestQueryRateLimitCancelled(t *testing.T) {
+	api := newFakeWhoisAPI(t)
+	api.expireIn(200)
+	s := newAPITestService(api, func(cfg *config.WhoisConfig) { cfg.RateLimit = 0.1 })
+
+	// Use up the burst; the next token is 10 seconds away
+	if _, err := s.QueryDomain("example.com"); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.QueryDomainContext(ctx, "example.org")
+	if err == nil || !strings.Contains(err.Error(), "WHOIS rate limit") {
+		t.Errorf("err = %v, want a rate limit error", err)
+	}
+	if api.queryCount() != 1 {
+		t.Errorf("API queried %d times, want the waiting query skipped", api.queryCount())
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	api := newFakeWhoisAPI(t)
+	api.expireIn(200)
+	s := newAPITestService(api, func(cfg *config.WhoisConfig) { cfg.CacheTTL = "1h" })
+
+	first, err := s.QueryDomain("example.com")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	api.setRegistrar("Other Registrar")
+
+	// Hit: the fresh result is reused
+	cached, err := s.QueryDomain("example.com")
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if api.queryCount() != 1 || cached.Registrar != first.Registrar {
+		t.Errorf("queries = %d, registrar = %q; want the cached result", api.queryCount(), cached.Registrar)
+	}
+
+	// Bypass: a live query refreshes the cache
+	live, err := s.QueryDomainLive(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("live query: %v", err)
+	}
+	if api.queryCount() != 2 || live.Registrar != "Other Registrar" {
+		t.Errorf("queries = %d, registrar = %q; want a live query", api.queryCount(), live.Registrar)
+	}
+	if cached, _ := s.QueryDomain("example.com"); cached.Registrar != "Other Registrar" || api.queryCount() != 2 {
+		t.Errorf("cache not refreshed by the live query: %q", cached.Registrar)
+	}
+
+	// Expiry: results older than the TTL are queried again
+	s.cache.mu.Lock()
+	entry := s.cache.entries["example.com"]
+	entry.fetchedAt = time.Now().Add(-2 * time.Hour)
+	s.cache.entries["example.com"] = entry
+	s.cache.mu.Unlock()
+
+	if _, err := s.QueryDomain("example.com"); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if api.queryCount() != 3 {
+		t.Errorf("queries = %d, want a new query after the TTL", api.queryCount())
+	}
+}
+
+func TestQueryCacheReturnsCopies(t *testing.T) {
+	api := newFakeWhoisAPI(t)
+	api.expireIn(200)
+	s := newAPITestService(api, func(cfg *config.WhoisConfig) { cfg.CacheTTL = "1h" })
+
+	info, _ := s.QueryDomain("example.com")
+	info.Registrar = "changed by the caller"
+	if cached, _ := s.QueryDomain("example.com"); cached.Registrar != "Example Registrar" {
+		t.Errorf("cached registrar = %q, want it unaffected by callers", cached.Registrar)
+	}
+}
+
+func TestQueryCacheDisabled(t *testing.T) {
+	api := newFakeWhoisAPI(t)
+	api.expireIn(200)
+	s := newAPITestService(api, nil)
+
+	s.QueryDomain("example.com")
+	s.QueryDomain("example.com")
+	if api.queryCount() != 2 {
+		t.Errorf("queries = %d, want every query live with cache_ttl 0", api.queryCount())
+	}
+}
+
+func TestRefreshBypassesCache(t *testing.T) {
+	setupTestDB(t)
+	monitor, api, _ := newTestMonitor(t, config.MonitorConfig{})
+	api.expireIn(200)
+	monitor.whoisService = newAPITestService(api, func(cfg *config.WhoisConfig) { cfg.CacheTTL = "1h" })
+	domain := createTestDomain(t, "example.com")
+
+	monitor.CheckDomain(domain)
+	monitor.CheckDomain(domain)
+	if api.queryCount() != 1 {
+		t.Fatalf("queries = %d, want scheduled checks to use the cache", api.queryCount())
+	}
+
+	if err := monitor.RefreshDomain(context.Background(), domain); err != nil {
+		t.Fatalf("RefreshDomain: %v", err)
+	}
+	if api.queryCount() != 2 {
+		t.Errorf("queries = %d, want a manual refresh to query live", api.queryCount())
+	}
+
+	job, err := monitor.StartRefresh([]models.Domain{*domain}, nil)
+	if err != nil {
+		t.Fatalf("StartRefresh: %v", err)
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if current, _ := monitor.RefreshJob(job.ID); current.Status == JobFinished {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("refresh job did not finish")
+		}
+	}
+	if api.queryCount() != 3 {
+		t.Errorf("queries = %d, want refresh-all to query live", api.queryCount())
+	}
+}
+
+// newHangingAPI returns a WHOIS API that answers only after the client gives up
+func newHangingAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func TestQueryDomainContextCancelled(t *testing.T) {
+	s := NewWhoisService(&config.WhoisConfig{APIURL: newHangingAPI(t).URL, CacheTTL: "0"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := s.QueryDomainContext(ctx, "example.com")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancelled query returned after %v", elapsed)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	jan15 := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+	jan15Noon := time.Date(2025, 1, 15, 12, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"2025-01-15T12:30:45Z", jan15Noon},
+		{"2025-01-15T12:30:45.123Z", jan15Noon.Add(123 * time.Millisecond)},
+		{"2025-01-15T20:30:45+08:00", jan15Noon},
+		{"2025-01-15T20:30:45+0800", jan15Noon},
+		{"2025-01-15T12:30:45", jan15Noon},
+		{"2025-01-15 12:30:45", jan15Noon},
+		{"2025-01-15 20:30:45+08", jan15Noon},
+		{"2025-01-15 12:30:45 UTC", jan15Noon},
+		{"2025-01-15", jan15},
+		{" 2025-01-15 (YYYY-MM-DD) ", jan15},
+		{"2025.01.15", jan15},
+		{"2025.01.15 12:30:45", jan15Noon},
+		{"2025/01/15", jan15},
+		{"15.01.2025", jan15},
+		{"15-Jan-2025", jan15},
+		{"15-JAN-2025", jan15},
+		{"15-Jan-2025 12:30:45 UTC", jan15Noon},
+		{"15 Jan 2025", jan15},
+		{"January 15, 2025", jan15},
+		{"20250115", jan15},
+		{"2025年01月15日", jan15},
+		{"Wed, 15 Jan 2025 12:30:45 UTC", jan15Noon},
+		{"Wed, 15 Jan 2025 20:30:45 +0800", jan15Noon},
+		{"Wed Jan 15 12:30:45 2025", jan15Noon},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.input)
+		if err != nil {
+			t.Errorf("parseDate(%q) error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) || got.Location() != time.UTC {
+			t.Errorf("parseDate(%q) = %v, want %v in UTC", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, input := range []string{"", "soon", "2025-13-45", "15/01/2025 noon"} {
+		if got, err := parseDate(input); err == nil {
+			t.Errorf("parseDate(%q) = %v, want an error", input, got)
+		}
+	}
+}